@@ -0,0 +1,205 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// A tiny OpenID Connect issuer for trying social login locally.
+// Point a provider in OIDC_PROVIDERS_FILE at it:
+//
+//	[{"name": "mock", "displayName": "Mock", "issuer": "http://localhost:9999",
+//	  "clientId": "armoire", "redirectUrl": "http://localhost:8080/auth/oidc/mock/callback"}]
+//
+// then open http://localhost:8080/auth/oidc/mock/login. Add &email=...&approve=1 to the
+// authorize URL to skip the form (handy with curl).
+
+const keyID = "mock-1"
+
+type pendingCode struct {
+	ClientID      string
+	RedirectURI   string
+	CodeChallenge string
+	Nonce         string
+	Email         string
+	Name          string
+	Verified      bool
+	ExpiresAt     time.Time
+}
+
+var (
+	issuer     string
+	signingKey *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes = map[string]pendingCode{}
+)
+
+var loginForm = template.Must(template.New("login").Parse(`<!doctype html>
+<title>Mock OIDC login</title>
+<h1>Mock OIDC login</h1>
+<form method="get" action="/authorize">
+  {{range $k, $v := .Params}}{{if ne $k "email"}}<input type="hidden" name="{{$k}}" value="{{index $v 0}}">{{end}}{{end}}
+  <input type="hidden" name="approve" value="1">
+  <p><label>Email <input name="email" value="mock.user@example.com"></label></p>
+  <p><label>Name <input name="name" value="Mock User"></label></p>
+  <p><label><input type="checkbox" name="email_verified" value="true" checked> Email verified</label></p>
+  <button>Sign in</button>
+</form>`))
+
+func main() {
+	addr := os.Getenv("MOCK_OIDC_ADDR")
+	if addr == "" {
+		addr = "localhost:9999"
+	}
+	issuer = "http://" + addr
+
+	var err error
+	signingKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	http.HandleFunc("/.well-known/openid-configuration", discoveryHandler)
+	http.HandleFunc("/authorize", authorizeHandler)
+	http.HandleFunc("/token", tokenHandler)
+	http.HandleFunc("/jwks", jwksHandler)
+
+	fmt.Printf("Mock OIDC issuer listening on %s\n", issuer)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
+
+func discoveryHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                issuer,
+		"authorization_endpoint":                issuer + "/authorize",
+		"token_endpoint":                        issuer + "/token",
+		"jwks_uri":                              issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func authorizeHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "expected response_type=code with an S256 code_challenge", http.StatusBadRequest)
+		return
+	}
+
+	if q.Get("approve") != "1" {
+		loginForm.Execute(w, map[string]url.Values{"Params": q})
+		return
+	}
+
+	code := randomString()
+	mu.Lock()
+	codes[code] = pendingCode{
+		ClientID:      q.Get("client_id"),
+		RedirectURI:   q.Get("redirect_uri"),
+		CodeChallenge: q.Get("code_challenge"),
+		Nonce:         q.Get("nonce"),
+		Email:         q.Get("email"),
+		Name:          q.Get("name"),
+		Verified:      q.Get("email_verified") != "false",
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+	mu.Unlock()
+
+	redirect := q.Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {q.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect, http.StatusFound)
+}
+
+func tokenHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	mu.Lock()
+	pending, ok := codes[r.Form.Get("code")]
+	delete(codes, r.Form.Get("code")) // Codes are single use
+	mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	if !ok || time.Now().After(pending.ExpiresAt) ||
+		pending.ClientID != r.Form.Get("client_id") ||
+		pending.RedirectURI != r.Form.Get("redirect_uri") ||
+		pending.CodeChallenge != challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	// A stable subject per email, like a real provider's user ID
+	subject := sha256.Sum256([]byte(pending.Email))
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            issuer,
+		"sub":            base64.RawURLEncoding.EncodeToString(subject[:12]),
+		"aud":            pending.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          pending.Nonce,
+		"email":          pending.Email,
+		"email_verified": pending.Verified,
+		"name":           pending.Name,
+	})
+	token.Header["kid"] = keyID
+
+	idToken, err := token.SignedString(signingKey)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func jwksHandler(w http.ResponseWriter, r *http.Request) {
+	public := signingKey.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": keyID,
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func randomString() string {
+	buf := make([]byte, 24)
+	rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Rewrites stored emails to models.NormalizeEmail form, for accounts made
+// before sign-up and login normalized them:
+//
+//	go run ./cmd/normalize_emails
+//
+// Two accounts whose emails only differ in case can't both keep theirs; they
+// are listed and left alone, to be merged or renamed by hand.
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+	database.InitDB(os.Getenv("MONGO_URI"))
+
+	ctx := context.Background()
+	users := database.GetCollection("users")
+	cursor, err := users.Find(ctx, bson.M{"email": bson.M{"$regex": `[A-Z]|^\s|\s$`}})
+	if err != nil {
+		log.Fatal("Could not list users: ", err)
+	}
+	defer cursor.Close(ctx)
+
+	updated, conflicts := 0, 0
+	for cursor.Next(ctx) {
+		var user models.User
+		if err := cursor.Decode(&user); err != nil {
+			log.Printf("Could not decode user: %v", err)
+			continue
+		}
+		email := models.NormalizeEmail(user.Email)
+		if email == user.Email {
+			continue
+		}
+
+		_, err := users.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": bson.M{"email": email}})
+		if mongo.IsDuplicateKeyError(err) {
+			fmt.Printf("Conflict: %s (%s) and another account are both %s\n", user.ID.Hex(), user.Email, email)
+			conflicts++
+			continue
+		}
+		if err != nil {
+			log.Printf("Could not update %s: %v", user.ID.Hex(), err)
+			continue
+		}
+
+		// Outstanding links are checked against the address they were sent to
+		if _, err := database.GetCollection("user_tokens").UpdateMany(ctx,
+			bson.M{"user_id": user.ID.Hex(), "email": user.Email},
+			bson.M{"$set": bson.M{"email": email}},
+		); err != nil {
+			log.Printf("Could not update tokens of %s: %v", user.ID.Hex(), err)
+		}
+		updated++
+	}
+	if err := cursor.Err(); err != nil {
+		log.Fatal("Could not list users: ", err)
+	}
+
+	fmt.Printf("Normalized %d email(s), %d conflict(s)\n", updated, conflicts)
+}
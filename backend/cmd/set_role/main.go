@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Sets a user's role from the command line, which is how the first admin is made:
+//
+//	go run ./cmd/set_role -email you@example.com -role admin
+//
+// Reads MONGO_URI from the environment or .env like the server does.
+func main() {
+	email := flag.String("email", "", "Email of the user to change")
+	role := flag.String("role", models.RoleAdmin, "Role to give them (user or admin)")
+	flag.Parse()
+
+	if *email == "" || !slices.Contains(models.Roles, *role) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+	database.InitDB(os.Getenv("MONGO_URI"))
+
+	result, err := database.GetCollection("users").UpdateOne(context.Background(),
+		bson.M{"email": models.NormalizeEmail(*email)},
+		bson.M{"$set": bson.M{"role": *role}},
+	)
+	if err != nil {
+		log.Fatal("Could not update role: ", err)
+	}
+	if result.MatchedCount == 0 {
+		log.Fatalf("No user with email %s", *email)
+	}
+	fmt.Printf("%s is now %s\n", *email, *role)
+}
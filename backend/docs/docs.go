@@ -16,40 +16,34 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/admin/clothing/{id}/reprocess": {
             "post": {
-                "description": "Authenticate user and return JWT token",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Admin only. Re-run AI tagging, care label reading and the embedding on an item's stored images, e.g. after a taxonomy or model change. Manual edits to tagged fields are overwritten. Usage is booked to the admin.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Log into an account",
+                "summary": "Re-process a clothing item",
                 "parameters": [
                     {
-                        "description": "Login credentials",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful with token and user data",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.ClothingItem"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid clothing ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -57,8 +51,26 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Invalid email or password",
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Item has no photo yet",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -75,45 +87,41 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/auth/register": {
-            "post": {
-                "description": "Create a new user account with name, email and password",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/usage": {
+            "get": {
+                "description": "Admin only. Totals per operation for a month, plus the heaviest users.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Register a new user",
+                "summary": "Get AI usage across all users",
                 "parameters": [
                     {
-                        "description": "Registration details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.RegisterRequest"
-                        }
+                        "type": "string",
+                        "description": "Month as YYYY-MM, defaults to the current month",
+                        "name": "period",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User created successfully",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/handlers.AdminUsageSummary"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid period",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -121,8 +129,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Email already registered",
+                    "403": {
+                        "description": "Admin access required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -139,188 +147,236 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/clothing/search": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Search using keyword matching or AI-powered \"vibe\" search with filters",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "description": "Admin only. Page through users, optionally searching name and email and filtering by role or disabled state.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "clothing"
+                    "admin"
                 ],
-                "summary": "Search clothing items",
+                "summary": "List users",
                 "parameters": [
                     {
-                        "description": "Search parameters",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.SearchRequest"
-                        }
+                        "type": "string",
+                        "description": "Matches name or email (case-insensitive)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "user or admin",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only disabled (true) or enabled (false) accounts",
+                        "name": "disabled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, from 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (max 200)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ClothingItem"
+                            "$ref": "#/definitions/handlers.AdminUserList"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/clothing/stats": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get statistics about a user's clothing collection including total count, color distribution, and category distribution",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "clothing"
-                ],
-                "summary": "Get user clothing statistics",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.UserStatsResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
-                }
-            }
-        },
-        "/clothing/upload": {
-            "post": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Upload a clothing item image, analyze it with AI for auto-tagging, generate vector embeddings, and store in database",
-                "consumes": [
-                    "multipart/form-data"
-                ],
+                ]
+            }
+        },
+        "/admin/users/{id}": {
+            "get": {
+                "description": "Admin only. A user with item, outfit and active session counts and the storage their images take up.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "clothing"
+                    "admin"
                 ],
-                "summary": "Upload a clothing item",
+                "summary": "Get a user",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Clothing item image (max 10MB)",
-                        "name": "image",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully uploaded and processed clothing item",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ClothingItem"
+                            "$ref": "#/definitions/handlers.AdminUserDetail"
                         }
                     },
-                    "400": {
-                        "description": "Invalid file",
+                    "403": {
+                        "description": "Admin access required",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Failed to upload to GCS / AI Analysis Failed / Vector Embedding Failed / Database Save Failed",
+                        "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
-                }
-            }
-        },
-        "/clothing/{id}": {
-            "get": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
+                ]
+            }
+        },
+        "/admin/users/{id}/disable": {
+            "post": {
+                "description": "Admin only. The user is signed out everywhere and can't sign in again until re-enabled. Their data is kept.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get details of a specific clothing item by its ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "clothing"
+                    "admin"
                 ],
-                "summary": "Get a single clothing item by ID",
+                "summary": "Disable a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Clothing item ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Reason, shown to other admins",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DisableUserRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ClothingItem"
+                            "$ref": "#/definitions/models.User"
                         }
                     },
                     "400": {
-                        "description": "Invalid clothing ID",
+                        "description": "Admins can't disable themselves",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "404": {
-                        "description": "Clothing item not found",
+                        "description": "User not found",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Delete an existing clothing item by ID and remove the image from storage",
+                ]
+            }
+        },
+        "/admin/users/{id}/enable": {
+            "post": {
+                "description": "Admin only. Lets a disabled user sign in again.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "clothing"
+                    "admin"
                 ],
-                "summary": "Delete a clothing item",
+                "summary": "Re-enable a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Clothing item ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -329,6 +385,12 @@ const docTemplate = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin access required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -336,33 +398,26 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid clothing ID",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
                     "404": {
-                        "description": "Clothing item not found",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to delete clothing item",
+                        "description": "User not found",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
-                }
-            },
-            "patch": {
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Update an existing clothing item by ID",
+                ]
+            }
+        },
+        "/admin/users/{id}/role": {
+            "put": {
+                "description": "Admin only. Promote a user to admin or demote them back to user.",
                 "consumes": [
                     "application/json"
                 ],
@@ -370,24 +425,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "clothing"
+                    "admin"
                 ],
-                "summary": "Update a clothing item",
+                "summary": "Change a user's role",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Clothing item ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated clothing item data",
-                        "name": "clothing",
+                        "description": "New role",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ClothingItem"
+                            "$ref": "#/definitions/handlers.SetRoleRequest"
                         }
                     }
                 ],
@@ -395,52 +450,78 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ClothingItem"
+                            "$ref": "#/definitions/models.User"
                         }
                     },
                     "400": {
-                        "description": "Invalid clothing ID or request body",
+                        "description": "Unknown role, or demoting yourself",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Clothing item not found",
+                    "403": {
+                        "description": "Admin access required",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Failed to update clothing item",
+                    "404": {
+                        "description": "User not found",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/clothing/{id}/owner": {
-            "get": {
-                "description": "Get the name of the owner of a specific clothing item by its ID",
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user and return a short-lived JWT access token plus a refresh token",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "clothing"
+                    "auth"
                 ],
-                "summary": "Get clothing item owner name",
+                "summary": "Log into an account",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Clothing item ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Login successful with token and user data",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -448,86 +529,224 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid clothing ID",
+                    "401": {
+                        "description": "Invalid email or password",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Clothing item not found",
+                    "403": {
+                        "description": "Account disabled",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts for this email, temporarily locked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Failed to fetch owner information",
+                        "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/ping": {
-            "get": {
-                "description": "Returns pong message",
-                "consumes": [
-                    "application/json"
-                ],
+        "/auth/logout": {
+            "post": {
+                "description": "Revoke the current session so its access and refresh tokens stop working",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "auth"
                 ],
-                "summary": "Ping endpoint",
+                "summary": "Log out",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/router.PingResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
             }
         },
-        "/user/userinfo": {
-            "get": {
+        "/auth/logout-all": {
+            "post": {
+                "description": "Revoke every session of the authenticated user, including the current one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log out of all devices",
+                "responses": {
+                    "200": {
+                        "description": "Number of sessions revoked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
                 "security": [
                     {
                         "BearerAuth": []
                     }
-                ],
-                "description": "Get the authenticated user's information from JWT token",
-                "consumes": [
+                ]
+            }
+        },
+        "/auth/oidc/providers": {
+            "get": {
+                "description": "List the configured OpenID Connect providers",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "List social login providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.OIDCProvider"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/callback": {
+            "get": {
+                "description": "Provider callback. Exchanges the code, links or creates the account by verified email and starts a session. Redirects to OIDC_SUCCESS_REDIRECT with the tokens in the URL fragment when configured, otherwise returns JSON.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "auth"
+                ],
+                "summary": "Finish a social login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State from the login redirect",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get current user",
                 "responses": {
                     "200": {
-                        "description": "User data",
+                        "description": "Tokens and user data",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Login failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Email belongs to an existing account, but the provider or the account has not verified it",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/login": {
+            "get": {
+                "description": "Redirect to the provider's authorization page (authorization code flow with PKCE)",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start a social login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Unknown provider",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -535,8 +754,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "502": {
+                        "description": "Provider unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -546,153 +765,7825 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
+        },
+        "/auth/password-reset/confirm": {
+            "post": {
+                "description": "Set a new password with a reset token. Signs the user out everywhere.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Reset a password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password-reset/request": {
+            "post": {
+                "description": "Email a password reset link. Always succeeds so it can't be used to discover accounts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Request a password reset",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token. The refresh token is rotated; replaying an old one revokes the session.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh an access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid, expired or revoked refresh token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Create a new user account with name, email and password. A verification link is emailed to the address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "Registration details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Email already registered",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email/confirm": {
+            "post": {
+                "description": "Redeem a verification token from the email link",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Confirm an email address",
+                "parameters": [
+                    {
+                        "description": "Verification token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ConfirmTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email/request": {
+            "post": {
+                "description": "Send (or re-send) a verification link to the authenticated user's email address",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Request an email verification link",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Could not send verification email",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/borrow-requests": {
+            "get": {
+                "description": "The authenticated user's borrowing history: requests they made and requests for their items, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "borrowing"
+                ],
+                "summary": "List borrow requests",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "borrower (requests you made) or owner (requests for your items); both if omitted",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only requests in this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only requests for this item",
+                        "name": "itemId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.BorrowRequestView"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Ask to borrow another user's public item for a range of days. Requests that overlap a pending, approved or ongoing loan of the same item are rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "borrowing"
+                ],
+                "summary": "Request to borrow an item",
+                "parameters": [
+                    {
+                        "description": "Item and dates",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateBorrowRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BorrowRequestView"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or dates",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Item can't be borrowed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Dates overlap another loan",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/borrow-requests/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "borrowing"
+                ],
+                "summary": "Get a borrow request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Borrow request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BorrowRequestView"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid borrow request ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Borrow request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/borrow-requests/{id}/approve": {
+            "post": {
+                "description": "Approve a pending request for one of your items. If the loan period has started the item is marked lent straight away; otherwise it is marked lent when the period starts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "borrowing"
+                ],
+                "summary": "Approve a borrow request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Borrow request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BorrowRequestView"
+                        }
+                    },
+                    "404": {
+                        "description": "Borrow request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Not pending, dates overlap an approved loan, or the period has started and the item isn't available",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/borrow-requests/{id}/cancel": {
+            "post": {
+                "description": "Withdraw a request you made, as long as the loan hasn't started",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "borrowing"
+                ],
+                "summary": "Cancel a borrow request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Borrow request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BorrowRequestView"
+                        }
+                    },
+                    "404": {
+                        "description": "Borrow request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Loan already started or finished",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/borrow-requests/{id}/decline": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "borrowing"
+                ],
+                "summary": "Decline a borrow request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Borrow request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BorrowRequestView"
+                        }
+                    },
+                    "404": {
+                        "description": "Borrow request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Not pending",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/borrow-requests/{id}/return": {
+            "post": {
+                "description": "The owner marks a lent item as back, ending the loan early. The item becomes available again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "borrowing"
+                ],
+                "summary": "Mark a loan returned",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Borrow request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BorrowRequestView"
+                        }
+                    },
+                    "404": {
+                        "description": "Borrow request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Item isn't lent out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/calendar/{token}": {
+            "get": {
+                "description": "iCalendar feed of planned outfits from 30 days ago to a year ahead, one all-day event per entry. The URL comes from POST /planner/calendar-feed; no other authentication is needed.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "planner"
+                ],
+                "summary": "Planner calendar feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed secret, optionally followed by .ics",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Feed not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/clothing/analysis": {
+            "get": {
+                "description": "Work out how many outfits (a top and bottom, or a dress, with shoes, that go together and share an occasion) the closet makes, overall and per occasion and season. Lists the key pieces in the most outfits, orphans that go with nothing, under-served occasions, seasons and categories (e.g. no Formal shoes), and the single items that would add the most new outfits.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Analyze the closet as a capsule wardrobe",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only count items that are available right now",
+                        "name": "available",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ClosetAnalysisResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch clothing items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/availability": {
+            "patch": {
+                "description": "Set the availability status (available, in_laundry, at_dry_cleaner, lent, in_storage, needs_repair) of several items at once, recording the transition time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Bulk update item availability",
+                "parameters": [
+                    {
+                        "description": "Items and their new status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AvailabilityUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AvailabilityUpdateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, clothing ID or status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update availability",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/import-url": {
+            "post": {
+                "description": "Fetch a shop's product page, read its title, brand, price and main image from the JSON-LD Product or OpenGraph metadata, and add it to the closet through the normal upload pipeline (background removal, thumbnail, AI tagging and embedding). The page's title and brand win over the AI's guesses.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Import a clothing item from a product page",
+                "parameters": [
+                    {
+                        "description": "Product page URL",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImportFromURLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClothingItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid URL",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "No product or product image found on the page",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to upload to GCS / AI Analysis Failed / Vector Embedding Failed / Database Save Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Could not fetch the page or image",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/receipts": {
+            "post": {
+                "description": "Read an order confirmation email (pasted as text or HTML, or uploaded as .eml, .html or .txt) or a receipt photo or PDF, extract the clothing bought with AI, and create a draft closet item per piece, pre-filled with name, brand, size, color, price and purchase date. Drafts have no photo or AI tags until one is added with POST /clothing/{id}/photo.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Create draft items from a receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pasted order email: plain text, HTML or a raw message",
+                        "name": "email",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Order email (.eml, .html, .txt), receipt photo or PDF (max 10MB); used instead of email",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReceiptImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "No readable email or receipt",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "No clothing found on the receipt",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read the receipt / Database Save Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/search": {
+            "post": {
+                "description": "Search using keyword matching or AI-powered \"vibe\" search with filters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Search clothing items",
+                "parameters": [
+                    {
+                        "description": "Search parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SearchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ClothingItem"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/stats": {
+            "get": {
+                "description": "Get statistics about a user's clothing collection including total count, color distribution, category distribution and sub-category breakdown within each category",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Get user clothing statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UserStatsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/upload": {
+            "post": {
+                "description": "Upload a clothing item image, analyze it with AI for auto-tagging, generate vector embeddings, and store in database",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Upload a clothing item",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Clothing item image (max 10MB)",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Optional photo of the care label",
+                        "name": "care_label",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully uploaded and processed clothing item",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClothingItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to upload to GCS / AI Analysis Failed / Vector Embedding Failed / Database Save Failed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/{id}": {
+            "get": {
+                "description": "Get details of a specific clothing item by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Get a single clothing item by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClothingItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid clothing ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete an existing clothing item by ID and remove the image from storage",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Delete a clothing item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid clothing ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete clothing item",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Update an existing clothing item by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Update a clothing item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated clothing item data",
+                        "name": "clothing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ClothingItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClothingItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid clothing ID, request body or taxonomy values",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update clothing item",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/{id}/care-label": {
+            "post": {
+                "description": "Attach a photo of an item's care label, extracting fiber composition and care symbols with AI. Replaces any previous care label.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Upload a care label photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Care label photo",
+                        "name": "care_label",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClothingItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid clothing ID or file",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to process care label",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/{id}/comment-settings": {
+            "put": {
+                "description": "Owner only. Existing comments stay visible; no new ones can be posted while comments are off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Turn comments on an item on or off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommentSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "boolean"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/{id}/comments": {
+            "get": {
+                "description": "Comments on a public clothing item, newest first. Page with ?before= and ?beforeId= set to the previous page's nextBefore and nextBeforeId.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "List comments on an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp; only comments posted before it",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last comment of the previous page; with before, also returns later comments posted at the same time",
+                        "name": "beforeId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Comments per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommentPage"
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Comment on an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommentView"
+                        }
+                    },
+                    "400": {
+                        "description": "Empty or too long",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Comments are turned off",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/{id}/comments/{commentId}": {
+            "delete": {
+                "description": "The comment's author or the item's owner can delete it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Delete a comment on an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/{id}/owner": {
+            "get": {
+                "description": "Get the name of the owner of a specific clothing item by its ID. The owner's user ID, e.g. to link to their profile, is only included for public items.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Get clothing item owner name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid clothing ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch owner information",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/clothing/{id}/photo": {
+            "post": {
+                "description": "Complete a draft created from a receipt with its photo. The photo goes through the normal upload pipeline (background removal, thumbnail, AI tagging and embedding); the name, brand, size, price and purchase date from the receipt are kept, as are its category and colors if it had them.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clothing"
+                ],
+                "summary": "Add a photo to a draft item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Clothing item image (max 10MB)",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ClothingItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid clothing ID or file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The item already has a photo",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to upload to GCS / AI Analysis Failed / Vector Embedding Failed / Database Save Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/clothing/{id}/reactions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Get reactions on an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactionSummary"
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Leave an emoji reaction (one of ❤️ 🔥 😍 👍 😂 👀 ✨ 💯). Reacting again with the same emoji does nothing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "React to an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reaction",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactionSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported reaction",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Remove a reaction from an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reaction to remove",
+                        "name": "emoji",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactionSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported reaction",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Clothing item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/collections": {
+            "get": {
+                "description": "Get all of the user's collections, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "List collections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Collection"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Group some of the user's clothing items under a name, e.g. a capsule or a trip",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Create a collection",
+                "parameters": [
+                    {
+                        "description": "Collection",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateCollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/collections/{id}": {
+            "get": {
+                "description": "Get one of the user's collections by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Get a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid collection ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete one of the user's collections. The clothing items are kept; share links to the collection are revoked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Delete a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid collection ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Rename a collection, change its description or replace its items",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "collections"
+                ],
+                "summary": "Update a collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateCollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Collection not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/dashboard/stylist": {
+            "get": {
+                "description": "Get a personalized AI message based on closet stats",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dashboard"
+                ],
+                "summary": "Get Stylist Message",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/gin.H"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/feed": {
+            "get": {
+                "description": "Recent public items and outfits from the users you follow, newest first. Page with ?before= and ?beforeId= set to the previous page's nextBefore and nextBeforeId.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Get the feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp; only entries created before it",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last entry of the previous page; with before, also returns later entries created at the same time",
+                        "name": "beforeId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entries per page (max 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FeedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cursor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/laundry/loads": {
+            "post": {
+                "description": "Sort a set of clothing items into loads with compatible care requirements (method, colors, cycle and temperature). Defaults to the items marked as in the laundry.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "laundry"
+                ],
+                "summary": "Group items into laundry loads",
+                "parameters": [
+                    {
+                        "description": "Items to wash",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LaundryLoadsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LaundryLoadsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch clothing items",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/notifications": {
+            "get": {
+                "description": "The authenticated user's notifications, newest first, with the number still unread. Page with ?before= and ?beforeId= set to the previous page's nextBefore and nextBeforeId.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "List notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp; only notifications created before it",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last notification of the previous page; with before, also returns later notifications created at the same time",
+                        "name": "beforeId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Notifications per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NotificationPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid cursor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/notifications/read-all": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark all notifications read",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer",
+                                "format": "int64"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/notifications/stream": {
+            "get": {
+                "description": "Server-sent events: one \"notification\" event per new notification, with the notification's ID as the event ID. Reconnect with Last-Event-ID (or ?lastEventId=) to receive what was missed. Browsers' EventSource can't send headers, so it authenticates with ?ticket= from POST /notifications/stream-ticket instead; a ticket works once, so get a new one before reconnecting. Streams close after an hour or when the session is revoked.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Stream notifications",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stream ticket, if no Authorization header is sent",
+                        "name": "ticket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replay notifications after this one",
+                        "name": "lastEventId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/notifications/stream-ticket": {
+            "post": {
+                "description": "Issue a single-use ticket that opens GET /notifications/stream without an Authorization header, for browsers' EventSource. It expires after a minute; get a new one for every (re)connect.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Get a notification stream ticket",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StreamTicketResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "User ID not found in context",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create ticket",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/notifications/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Delete a notification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid notification ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/notifications/{id}/read": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark a notification read",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid notification ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Notification not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/outfits": {
+            "get": {
+                "description": "Get all of the user's outfits, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outfits"
+                ],
+                "summary": "List outfits",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Outfit"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Save a combination of the user's clothing items as an outfit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outfits"
+                ],
+                "summary": "Create an outfit",
+                "parameters": [
+                    {
+                        "description": "Outfit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateOutfitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Outfit"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/outfits/{id}": {
+            "get": {
+                "description": "Get one of the user's outfits by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outfits"
+                ],
+                "summary": "Get an outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Outfit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Outfit"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid outfit ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Outfit not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete one of the user's outfits. The clothing items are kept; share links to the outfit are revoked and its comments and reactions deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outfits"
+                ],
+                "summary": "Delete an outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Outfit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid outfit ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Outfit not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Rename an outfit, change its items or vibe tags, or make it public",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "outfits"
+                ],
+                "summary": "Update an outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Outfit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateOutfitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Outfit"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Outfit not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/outfits/{id}/comment-settings": {
+            "put": {
+                "description": "Owner only. Existing comments stay visible; no new ones can be posted while comments are off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Turn comments on an outfit on or off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Outfit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommentSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "boolean"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Outfit not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/outfits/{id}/comments": {
+            "get": {
+                "description": "Comments on a public outfit, newest first. Page with ?before= and ?beforeId= set to the previous page's nextBefore and nextBeforeId.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "List comments on an outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Outfit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 timestamp; only comments posted before it",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last comment of the previous page; with before, also returns later comments posted at the same time",
+                        "name": "beforeId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Comments per page (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommentPage"
+                        }
+                    },
+                    "404": {
+                        "description": "Outfit not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Comment on an outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Outfit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommentView"
+                        }
+                    },
+                    "400": {
+                        "description": "Empty or too long",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Comments are turned off",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Outfit not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/outfits/{id}/comments/{commentId}": {
+            "delete": {
+                "description": "The comment's author or the outfit's owner can delete it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Delete a comment on an outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Outfit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/outfits/{id}/reactions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Get reactions on an outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Outfit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactionSummary"
+                        }
+                    },
+                    "404": {
+                        "description": "Outfit not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Leave an emoji reaction (one of ❤️ 🔥 😍 👍 😂 👀 ✨ 💯). Reacting again with the same emoji does nothing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "React to an outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Outfit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reaction",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactionSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported reaction",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Outfit not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Remove a reaction from an outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Outfit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reaction to remove",
+                        "name": "emoji",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ReactionSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported reaction",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Outfit not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/packing-lists": {
+            "get": {
+                "description": "The user's packing lists, latest trip first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packing"
+                ],
+                "summary": "List packing lists",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PackingList"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Pick a minimal capsule from the closet for a trip: the fewest items that still give each planned activity (a taxonomy occasion) a different outfit every day it comes up, suited to the expected weather. Items needing repair or lent out during the trip are left out. The result is saved as a checklist with an outfit for each day.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packing"
+                ],
+                "summary": "Generate a packing list",
+                "parameters": [
+                    {
+                        "description": "Trip dates, activities and weather",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreatePackingListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PackingListView"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, dates, activities or weather",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/packing-lists/{id}": {
+            "get": {
+                "description": "A packing list with its checklist progress and the details of its items",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packing"
+                ],
+                "summary": "Get a packing list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Packing list ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PackingListView"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid packing list ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Packing list not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete one of the user's packing lists. The clothing items are kept.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packing"
+                ],
+                "summary": "Delete a packing list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Packing list ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid packing list ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Packing list not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/packing-lists/{id}/items/{itemId}": {
+            "patch": {
+                "description": "Mark an item on the checklist as packed, or not packed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "packing"
+                ],
+                "summary": "Tick off a packing list item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Packing list ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Clothing item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Packed or not",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdatePackingItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PackingListView"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Packing list or item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Returns pong message",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Ping endpoint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/router.PingResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/planner": {
+            "get": {
+                "description": "Planned outfits for a range of days (a week from today by default, at most 92 days), with warnings about repeats on consecutive days and unavailable or lent items",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "planner"
+                ],
+                "summary": "Get the planner",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD (default today)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD (default a week from from)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlannerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Put an outfit, or an ad-hoc list of items, on a day. The response warns if an item is also planned the day before or after, is lent out that day, or is currently unavailable.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "planner"
+                ],
+                "summary": "Plan an outfit",
+                "parameters": [
+                    {
+                        "description": "Day and outfit or items",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreatePlanEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlanEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, date, outfit or items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/planner/calendar-feed": {
+            "post": {
+                "description": "Create a secret iCalendar (.ics) URL for the planner that calendar apps can subscribe to. Calling this again replaces the URL; the old one stops working.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "planner"
+                ],
+                "summary": "Turn on the calendar feed",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CalendarFeedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "planner"
+                ],
+                "summary": "Turn off the calendar feed",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/planner/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "planner"
+                ],
+                "summary": "Delete a planned outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plan entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid plan entry ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Plan entry not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Move an entry to another day, change what's planned or edit its notes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "planner"
+                ],
+                "summary": "Update a planned outfit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plan entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdatePlanEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PlanEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, date, outfit or items",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Plan entry not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/share-links": {
+            "get": {
+                "description": "List the user's share links with their view counts, optionally for a single resource",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sharing"
+                ],
+                "summary": "List share links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "item, outfit or collection",
+                        "name": "resourceType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource ID",
+                        "name": "resourceId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ShareLink"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create an unguessable link that lets anyone view an item, outfit or collection without an account. The token is only shown in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sharing"
+                ],
+                "summary": "Create a share link",
+                "parameters": [
+                    {
+                        "description": "What to share, and optional expiry and view limit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateShareLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ShareLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Resource not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/share-links/{id}": {
+            "delete": {
+                "description": "Stop a share link from working. The link and its view count are kept for the owner's records.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sharing"
+                ],
+                "summary": "Revoke a share link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShareLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid share link ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Share link not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/shared/{token}": {
+            "get": {
+                "description": "Public. View the item, outfit or collection behind a share link. Each successful call counts as a view.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sharing"
+                ],
+                "summary": "Open a share link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SharedView"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown link, or the shared resource was deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Link expired, revoked or out of views",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/taxonomy": {
+            "get": {
+                "description": "Get the versioned category → sub-category → attribute hierarchy with display names in the requested language",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomy"
+                ],
+                "summary": "Get the clothing taxonomy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language code (e.g. en, fr). Defaults to the Accept-Language header, then English",
+                        "name": "lang",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/taxonomy.LocalizedTaxonomy"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "delete": {
+                "description": "Permanently delete the account with all of its clothing, outfits, collections, share links, wear logs, sessions and stored images, and report what was removed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete account",
+                "parameters": [
+                    {
+                        "description": "Password (or confirm: \\",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DeleteAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PurgeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Confirmation missing",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Password is incorrect",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/avatar": {
+            "post": {
+                "description": "Upload a profile picture. It is resized and replaces any previous avatar.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Upload avatar",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Avatar image",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid file",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/export": {
+            "get": {
+                "description": "Download a zip containing manifest.json (profile, clothing items without embeddings, outfits, collections) and every original, thumbnail and care label image",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Export account data",
+                "responses": {
+                    "200": {
+                        "description": "Zip archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/follow-requests": {
+            "get": {
+                "description": "Pending requests to follow the authenticated user, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "List follow requests",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.FollowRequest"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/follow-requests/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Decline a follow request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Follow request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid follow request ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Follow request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/follow-requests/{id}/approve": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Approve a follow request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Follow request ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Follow"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid follow request ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Follow request not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/followers/{id}": {
+            "delete": {
+                "description": "Make another user stop following the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Remove a follower",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Follower's user ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not a follower",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/import": {
+            "post": {
+                "description": "Restore an archive produced by the export endpoint into the authenticated account. Items, outfits and collections get new IDs, images are re-uploaded and embeddings re-computed. Items are validated like an edit and skipped if a field is invalid; everything is imported private, with no loans and fresh timestamps. Existing data is kept.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Import account data",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Export zip",
+                        "name": "archive",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid archive",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Archive too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/password": {
+            "put": {
+                "description": "Change the password after confirming the current one. Every other session is signed out. Social-only accounts can set a first password without a current one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Current password is incorrect",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/profile": {
+            "patch": {
+                "description": "Change the authenticated user's name, email or profile visibility. Changing the email needs currentPassword (unless the account is social-only); the new address must be re-verified, a verification link is sent to it and the old address is told about the change. Making a profile public accepts all pending follow requests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update profile",
+                "parameters": [
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Current password is incorrect",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Email already registered",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/usage": {
+            "get": {
+                "description": "Get the authenticated user's AI usage (calls, Gemini tokens, Clipdrop credits) per operation for a month, and how much of each monthly quota is left",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get AI usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month as YYYY-MM, defaults to the current month",
+                        "name": "period",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UsageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/user/userinfo": {
+            "get": {
+                "description": "Get the authenticated user's information from JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "User data",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "A user's name, avatar, follow counts and, if their privacy setting allows the viewer, their most recent public items and outfits",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Get a user's profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Profile"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/follow": {
+            "post": {
+                "description": "Follow another user. Following a public profile takes effect immediately; other profiles get a follow request to approve.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Follow a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Follow"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID, or following yourself",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Stop following a user, or withdraw a pending follow request",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "Unfollow a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/followers": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "List a user's followers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.UserSummary"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "This profile is private",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/users/{id}/following": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "social"
+                ],
+                "summary": "List who a user follows",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.UserSummary"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "This profile is private",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/wishlist": {
+            "get": {
+                "description": "Everything on the wishlist, newest first, each with how many outfits it would make with the closet and the most similar item already owned, if it's a near-duplicate",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Get the wishlist",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.WishlistItemView"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Save something to buy: a name, shop link and price, and a photo (uploaded or from a URL) that goes through the same background removal and AI tagging as a closet upload. Tags sent with the request win over AI ones. The response says how many outfits it would make with the closet and whether something very similar is already owned.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Add to the wishlist",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Photo of the item (max 10MB)",
+                        "name": "image",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Photo URL, used if no file is sent",
+                        "name": "imageUrl",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name, required without a photo",
+                        "name": "name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shop page",
+                        "name": "link",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Price",
+                        "name": "price",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category",
+                        "name": "category",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sub-category",
+                        "name": "subCategory",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Colors",
+                        "name": "colors",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Notes",
+                        "name": "notes",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WishlistItemView"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid fields or image",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to upload to GCS / AI Analysis Failed / Vector Embedding Failed / Database Save Failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/wishlist/{id}": {
+            "get": {
+                "description": "A wishlist item with how many outfits it would make with the closet and any near-duplicate already owned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Get a wishlist item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wishlist item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WishlistItemView"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid wishlist item ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wishlist item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a wishlist item and its photo",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Remove from the wishlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wishlist item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid wishlist item ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Wishlist item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "Change a wishlist item's name, link, price, tags or notes. Items without a photo are re-embedded when their name, category or colors change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Update a wishlist item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wishlist item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateWishlistItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WishlistItemView"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or fields",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Wishlist item not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        }
+    },
     "definitions": {
+        "capsule.Gap": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "description": "occasion, season or category",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "e.g. \"No Formal shoes\"",
+                    "type": "string"
+                },
+                "missing": {
+                    "description": "Categories with no suitable items, e.g. [\"Shoes\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "outfits": {
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "e.g. \"Formal\"",
+                    "type": "string"
+                }
+            }
+        },
+        "capsule.ItemOutfits": {
+            "type": "object",
+            "properties": {
+                "itemId": {
+                    "type": "string"
+                },
+                "outfits": {
+                    "type": "integer"
+                }
+            }
+        },
+        "capsule.Suggestion": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "newOutfits": {
+                    "type": "integer"
+                },
+                "occasion": {
+                    "type": "string"
+                }
+            }
+        },
+        "gin.H": {
+            "type": "object",
+            "additionalProperties": {}
+        },
+        "handlers.AdminUsageSummary": {
+            "type": "object",
+            "properties": {
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.UsageTotals"
+                    }
+                },
+                "period": {
+                    "type": "string"
+                },
+                "topUsers": {
+                    "description": "By total tokens, then calls",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.UsageTotals"
+                    }
+                }
+            }
+        },
+        "handlers.AdminUser": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "description": "Disabled accounts can't sign in; their sessions are revoked when disabled",
+                    "type": "boolean"
+                },
+                "disabledAt": {
+                    "type": "string"
+                },
+                "disabledReason": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "emailVerified": {
+                    "type": "boolean"
+                },
+                "emailVerifiedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identities": {
+                    "description": "External accounts (OIDC) linked to this user. Users created through\nsocial login have no password until they set one.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Identity"
+                    }
+                },
+                "itemCount": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "profileVisibility": {
+                    "description": "Empty means ProfilePublic",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Empty means RoleUser",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AdminUserDetail": {
+            "type": "object",
+            "properties": {
+                "activeSessions": {
+                    "type": "integer"
+                },
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "description": "Disabled accounts can't sign in; their sessions are revoked when disabled",
+                    "type": "boolean"
+                },
+                "disabledAt": {
+                    "type": "string"
+                },
+                "disabledReason": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "emailVerified": {
+                    "type": "boolean"
+                },
+                "emailVerifiedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identities": {
+                    "description": "External accounts (OIDC) linked to this user. Users created through\nsocial login have no password until they set one.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Identity"
+                    }
+                },
+                "itemCount": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "outfitCount": {
+                    "type": "integer"
+                },
+                "profileVisibility": {
+                    "description": "Empty means ProfilePublic",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Empty means RoleUser",
+                    "type": "string"
+                },
+                "storage": {
+                    "$ref": "#/definitions/handlers.StorageUsage"
+                }
+            }
+        },
+        "handlers.AdminUserList": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AdminUser"
+                    }
+                }
+            }
+        },
+        "handlers.AvailabilityUpdateRequest": {
+            "type": "object",
+            "required": [
+                "itemIds",
+                "status"
+            ],
+            "properties": {
+                "itemIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "note": {
+                    "description": "e.g. \"Lent to Sam\"",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "in_laundry"
+                }
+            }
+        },
+        "handlers.AvailabilityUpdateResponse": {
+            "type": "object",
+            "properties": {
+                "matched": {
+                    "type": "integer"
+                },
+                "modified": {
+                    "description": "Items already in the requested status are left untouched",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.BorrowRequestView": {
+            "type": "object",
+            "properties": {
+                "borrower": {
+                    "$ref": "#/definitions/handlers.UserSummary"
+                },
+                "borrowerId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "item": {
+                    "description": "Missing if the item has been deleted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.SharedItem"
+                        }
+                    ]
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "lentAt": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "owner": {
+                    "$ref": "#/definitions/handlers.UserSummary"
+                },
+                "ownerId": {
+                    "type": "string"
+                },
+                "respondedAt": {
+                    "type": "string"
+                },
+                "returnedAt": {
+                    "type": "string"
+                },
+                "startBlockedAt": {
+                    "description": "Set while an approved loan can't start because the owner has the item\nmarked unavailable",
+                    "type": "string"
+                },
+                "startDate": {
+                    "description": "First and last day of the loan, both inclusive, at midnight UTC",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CalendarFeedResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "description": "Subscribe to this in a calendar app; anyone with it can see your plans",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "newPassword"
+            ],
+            "properties": {
+                "currentPassword": {
+                    "type": "string"
+                },
+                "newPassword": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "handlers.ClosetAnalysisResponse": {
+            "type": "object",
+            "properties": {
+                "byOccasion": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "bySeason": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "clothing": {
+                    "description": "Details of the key pieces and orphans",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SharedItem"
+                    }
+                },
+                "gaps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/capsule.Gap"
+                    }
+                },
+                "keyPieces": {
+                    "description": "The items that are part of the most outfits",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/capsule.ItemOutfits"
+                    }
+                },
+                "orphanItemIds": {
+                    "description": "Items that don't go with anything, so can't be part of any outfit",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "suggestions": {
+                    "description": "Single items to add that would make the most new outfits",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/capsule.Suggestion"
+                    }
+                },
+                "totalItems": {
+                    "type": "integer"
+                },
+                "totalOutfits": {
+                    "description": "Distinct outfits: a top and a bottom, or a one-piece, with shoes, that\ngo together and share an occasion. Outerwear is layered on top and\ndoesn't count towards new outfits.",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.CommentPage": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CommentView"
+                    }
+                },
+                "nextBefore": {
+                    "description": "Pass as ?before= and ?beforeId= to get older comments; empty on the last page",
+                    "type": "string"
+                },
+                "nextBeforeId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CommentSettingsRequest": {
+            "type": "object",
+            "required": [
+                "disabled"
+            ],
+            "properties": {
+                "disabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.CommentView": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/handlers.UserSummary"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "description": "Owner of the target, who moderates",
+                    "type": "string"
+                },
+                "targetId": {
+                    "type": "string"
+                },
+                "targetType": {
+                    "description": "item or outfit",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "Author",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ConfirmTokenRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateBorrowRequest": {
+            "type": "object",
+            "required": [
+                "endDate",
+                "itemId",
+                "startDate"
+            ],
+            "properties": {
+                "endDate": {
+                    "description": "Last day, inclusive",
+                    "type": "string",
+                    "example": "2026-05-03"
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "startDate": {
+                    "description": "First day, inclusive",
+                    "type": "string",
+                    "example": "2026-05-01"
+                }
+            }
+        },
+        "handlers.CreateCollectionRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "itemIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateCommentRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateOutfitRequest": {
+            "type": "object",
+            "required": [
+                "itemIds",
+                "name"
+            ],
+            "properties": {
+                "isPublic": {
+                    "type": "boolean"
+                },
+                "itemIds": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "vibeTags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.CreatePackingListRequest": {
+            "type": "object",
+            "required": [
+                "startDate"
+            ],
+            "properties": {
+                "activities": {
+                    "description": "Casual every day if empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PackingActivity"
+                    }
+                },
+                "days": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "destination": {
+                    "type": "string",
+                    "example": "Lisbon, Portugal"
+                },
+                "endDate": {
+                    "type": "string",
+                    "example": "2026-06-16"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Lisbon"
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2026-06-12"
+                },
+                "weather": {
+                    "description": "Leave out if unknown",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.TripWeather"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.CreatePlanEntryRequest": {
+            "type": "object",
+            "required": [
+                "date"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-05-01"
+                },
+                "itemIds": {
+                    "description": "...or an ad-hoc list of items",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "outfitId": {
+                    "description": "Either an outfit...",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateShareLinkRequest": {
+            "type": "object",
+            "required": [
+                "resourceId",
+                "resourceType"
+            ],
+            "properties": {
+                "expiresAt": {
+                    "description": "Omit for a link that never expires",
+                    "type": "string"
+                },
+                "maxViews": {
+                    "description": "0 for unlimited",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "resourceId": {
+                    "type": "string"
+                },
+                "resourceType": {
+                    "description": "item, outfit or collection",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.DeleteAccountRequest": {
+            "type": "object",
+            "properties": {
+                "confirm": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Required for accounts that have a password; social-only accounts confirm with \"DELETE\"",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.DisableUserRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.EmailRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.FeedEntry": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "item": {
+                    "$ref": "#/definitions/handlers.SharedItem"
+                },
+                "outfit": {
+                    "$ref": "#/definitions/handlers.SharedOutfit"
+                },
+                "owner": {
+                    "$ref": "#/definitions/handlers.UserSummary"
+                },
+                "type": {
+                    "description": "item or outfit",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.FeedResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.FeedEntry"
+                    }
+                },
+                "nextBefore": {
+                    "description": "Pass as ?before= and ?beforeId= to get the next page; empty on the last page",
+                    "type": "string"
+                },
+                "nextBeforeId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.FollowRequest": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "from": {
+                    "$ref": "#/definitions/handlers.UserSummary"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ImportFromURLRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ImportReport": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "integer"
+                },
+                "images": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "outfits": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "Items that could not be restored",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "warnings": {
+                    "description": "Restored, but with something missing",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.LaundryLoadsRequest": {
+            "type": "object",
+            "properties": {
+                "itemIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.LaundryLoadsResponse": {
+            "type": "object",
+            "properties": {
+                "loads": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/laundry.Load"
+                    }
+                },
+                "missingItemIds": {
+                    "description": "IDs that were not found in the user's closet",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "handlers.LoginRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.NotificationPage": {
+            "type": "object",
+            "properties": {
+                "nextBefore": {
+                    "description": "Pass as ?before= and ?beforeId= to get older notifications; empty on the last page",
+                    "type": "string"
+                },
+                "nextBeforeId": {
+                    "type": "string"
+                },
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Notification"
+                    }
+                },
+                "unreadCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.OIDCProvider": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PackingListView": {
+            "type": "object",
+            "properties": {
+                "activities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PackingActivity"
+                    }
+                },
+                "clothing": {
+                    "description": "Details of the items still in the closet, in checklist order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SharedItem"
+                    }
+                },
+                "combinations": {
+                    "description": "How many different outfits the packed items make, per occasion",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PackingDay"
+                    }
+                },
+                "destination": {
+                    "type": "string"
+                },
+                "endDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PackingItem"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "packed": {
+                    "type": "integer"
+                },
+                "startDate": {
+                    "description": "First and last day of the trip, both inclusive, at midnight UTC",
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "uncovered": {
+                    "description": "Occasions no outfit could be put together for from the closet",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "weather": {
+                    "$ref": "#/definitions/models.TripWeather"
+                }
+            }
+        },
+        "handlers.PlanEntryResponse": {
+            "type": "object",
+            "properties": {
+                "entry": {
+                    "$ref": "#/definitions/handlers.PlanEntryView"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PlanWarning"
+                    }
+                }
+            }
+        },
+        "handlers.PlanEntryView": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "description": "The day, at midnight UTC",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itemIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SharedItem"
+                    }
+                },
+                "notes": {
+                    "description": "e.g. \"Dinner with Sam's parents\"",
+                    "type": "string"
+                },
+                "outfitId": {
+                    "description": "Exactly one of OutfitID and ItemIDs is set",
+                    "type": "string"
+                },
+                "outfitName": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PlanWarning": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "entryId": {
+                    "type": "string"
+                },
+                "itemId": {
+                    "type": "string"
+                },
+                "itemName": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "otherEntryId": {
+                    "description": "For consecutive_days, the entry on the day before",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PlannerResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PlanEntryView"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PlanWarning"
+                    }
+                }
+            }
+        },
+        "handlers.Profile": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "followStatus": {
+                    "description": "The viewer's relationship with this user",
+                    "type": "string"
+                },
+                "followerCount": {
+                    "type": "integer"
+                },
+                "followingCount": {
+                    "type": "integer"
+                },
+                "followsYou": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SharedItem"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "outfits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SharedOutfit"
+                    }
+                },
+                "profileVisibility": {
+                    "type": "string"
+                },
+                "visible": {
+                    "description": "False when the privacy setting hides items and outfits from the viewer",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.PurgeReport": {
+            "type": "object",
+            "properties": {
+                "documents": {
+                    "description": "Deleted documents per collection",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "imageFailures": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "images": {
+                    "description": "Stored objects removed (photos, thumbnails, care labels, avatar)",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.QuotaStatus": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "quota": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "used": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ReactionRequest": {
+            "type": "object",
+            "required": [
+                "emoji"
+            ],
+            "properties": {
+                "emoji": {
+                    "type": "string",
+                    "example": "🔥"
+                }
+            }
+        },
+        "handlers.ReactionSummary": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "mine": {
+                    "description": "Emojis the viewer has left",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.ReceiptImportResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClothingItem"
+                    }
+                },
+                "merchant": {
+                    "type": "string"
+                },
+                "purchaseDate": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "returnToken": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SearchRequest": {
+            "type": "object",
+            "properties": {
+                "aiSearch": {
+                    "description": "Toggle between Regex match vs Vector Match",
+                    "type": "boolean"
+                },
+                "brand": {
+                    "description": "Partial match on the brand text",
+                    "type": "string"
+                },
+                "categories": {
+                    "description": "Hard filter",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "colors": {
+                    "description": "Hard filter",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "drafts": {
+                    "description": "Only return drafts from receipts that are still waiting for a photo",
+                    "type": "boolean"
+                },
+                "fits": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "includeUnavailable": {
+                    "description": "Items in the laundry, lent out, etc. are hidden unless this is set",
+                    "type": "boolean"
+                },
+                "materials": {
+                    "description": "Garment attribute hard filters",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "necklines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "patterns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "query": {
+                    "description": "e.g. \"Dinner date\" or \"Blue jacket\"",
+                    "type": "string"
+                },
+                "sleeveLengths": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.SetRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ShareLinkResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "nil never expires",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastViewedAt": {
+                    "type": "string"
+                },
+                "maxViews": {
+                    "description": "0 is unlimited",
+                    "type": "integer"
+                },
+                "resourceId": {
+                    "type": "string"
+                },
+                "resourceType": {
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.SharedCollection": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SharedItem"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SharedItem": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "colors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "commentCount": {
+                    "type": "integer"
+                },
+                "commentsDisabled": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "fit": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "materials": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "neckline": {
+                    "type": "string"
+                },
+                "occasions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "reactionCounts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sleeveLength": {
+                    "type": "string"
+                },
+                "subCategory": {
+                    "type": "string"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SharedOutfit": {
+            "type": "object",
+            "properties": {
+                "commentCount": {
+                    "type": "integer"
+                },
+                "commentsDisabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SharedItem"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reactionCounts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "vibeTags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.SharedView": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "$ref": "#/definitions/handlers.SharedCollection"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "item": {
+                    "$ref": "#/definitions/handlers.SharedItem"
+                },
+                "maxViews": {
+                    "type": "integer"
+                },
+                "outfit": {
+                    "$ref": "#/definitions/handlers.SharedOutfit"
+                },
+                "ownerName": {
+                    "type": "string"
+                },
+                "resourceType": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.StorageUsage": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "missing": {
+                    "description": "Referenced but not found in the bucket",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "objects": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.StreamTicketResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "ticket": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "expiresIn": {
+                    "description": "Access token lifetime in seconds",
+                    "type": "integer"
+                },
+                "refreshToken": {
+                    "description": "Single-use, rotated on every refresh",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Short-lived access token (JWT)",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UpdateCollectionRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "itemIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UpdateOutfitRequest": {
+            "type": "object",
+            "properties": {
+                "isPublic": {
+                    "type": "boolean"
+                },
+                "itemIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "vibeTags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.UpdatePackingItemRequest": {
+            "type": "object",
+            "required": [
+                "packed"
+            ],
+            "properties": {
+                "packed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.UpdatePlanEntryRequest": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2026-05-01"
+                },
+                "itemIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "outfitId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "currentPassword": {
+                    "description": "Required to change the email of an account that has a password",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "profileVisibility": {
+                    "description": "public, followers or private",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UpdateWishlistItemRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "colors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "subCategory": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UsageResponse": {
+            "type": "object",
+            "properties": {
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UsageCounter"
+                    }
+                },
+                "period": {
+                    "type": "string"
+                },
+                "quotas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.QuotaStatus"
+                    }
+                },
+                "resetsAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UsageTotals": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer"
+                },
+                "credits": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "outputTokens": {
+                    "type": "integer"
+                },
+                "promptTokens": {
+                    "type": "integer"
+                },
+                "totalTokens": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.UserStatsResponse": {
+            "type": "object",
+            "properties": {
+                "categoryCounts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "colorCounts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "subCategoryCounts": {
+                    "description": "Sub-category counts grouped by their parent category",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "taxonomyVersion": {
+                    "type": "string"
+                },
+                "totalItems": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.UserSummary": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.WishlistItemView": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "Tags, from the user or from AI tagging of the photo",
+                    "type": "string"
+                },
+                "colors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "ISO 4217, e.g. \"EUR\"",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "description": "Photo, processed like a closet upload; empty if none was given",
+                    "type": "string"
+                },
+                "link": {
+                    "description": "Shop page",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "newOutfits": {
+                    "description": "Outfits it would make with the closet as it is now",
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "occasions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ownedSimilar": {
+                    "description": "The most similar item already in the closet, if it looks like the same thing",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.SharedItem"
+                        }
+                    ]
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "similarity": {
+                    "type": "number"
+                },
+                "subCategory": {
+                    "type": "string"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "laundry.Load": {
+            "type": "object",
+            "properties": {
+                "colorGroup": {
+                    "type": "string"
+                },
+                "cycle": {
+                    "type": "string"
+                },
+                "hangDryItemIds": {
+                    "description": "Items to pull out before the dryer",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "itemIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "maxTemperature": {
+                    "description": "°C, machine loads only",
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "e.g. \"Darks, 30°C, delicate\"",
+                    "type": "string"
+                },
+                "unknownCareItemIds": {
+                    "description": "Items without care label data, washed with the safest settings",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.AvailabilityChange": {
+            "type": "object",
+            "properties": {
+                "changedAt": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CareInstructions": {
+            "type": "object",
+            "properties": {
+                "bleach": {
+                    "type": "string"
+                },
+                "dryClean": {
+                    "type": "string"
+                },
+                "fibers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FiberContent"
+                    }
+                },
+                "iron": {
+                    "type": "string"
+                },
+                "labelText": {
+                    "description": "Whatever text the model could read, kept for reference",
+                    "type": "string"
+                },
+                "tumbleDry": {
+                    "type": "string"
+                },
+                "washMethod": {
+                    "type": "string"
+                },
+                "washTemperature": {
+                    "description": "Max °C, 0 if unknown",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ClothingItem": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "description": "Whether the item is in the closet right now (see AvailabilityStatuses)",
+                    "type": "string"
+                },
+                "availabilityChangedAt": {
+                    "type": "string"
+                },
+                "availabilityHistory": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AvailabilityChange"
+                    }
+                },
+                "brandText": {
+                    "description": "Text read off the garment's logo and labels",
+                    "type": "string"
+                },
+                "care": {
+                    "description": "Read from the care label photo, if one was uploaded",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CareInstructions"
+                        }
+                    ]
+                },
+                "careLabelUrl": {
+                    "description": "Optional second photo of the care label",
+                    "type": "string"
+                },
+                "category": {
+                    "description": "e.g., \"Outerwear\", \"Top\", \"Bottom\"",
+                    "type": "string"
+                },
+                "colors": {
+                    "description": "Tags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "commentCount": {
+                    "type": "integer"
+                },
+                "commentsDisabled": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "description": "Timestamps",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "ISO 4217, e.g. \"EUR\"",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "The \"Vibe\" Engine\nGemini will generate a text description, which we then convert to a vector",
+                    "type": "string"
+                },
+                "draft": {
+                    "description": "Drafts are created from receipts and wait for a photo; until one is\nadded they have no image, embedding or AI tags",
+                    "type": "boolean"
+                },
+                "fit": {
+                    "description": "Slim, Relaxed",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imageUrl": {
+                    "description": "Image Data",
+                    "type": "string"
+                },
+                "isPublic": {
+                    "type": "boolean"
+                },
+                "materials": {
+                    "description": "Garment attributes (values from the taxonomy attribute lists)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Basic Metadata",
+                    "type": "string"
+                },
+                "neckline": {
+                    "description": "Crew, V-Neck",
+                    "type": "string"
+                },
+                "needsReview": {
+                    "description": "Set when AI tagging could not be validated against the taxonomy",
+                    "type": "boolean"
+                },
+                "occasions": {
+                    "description": "Casual, Formal",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pattern": {
+                    "description": "Striped, Plaid, Floral",
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Purchase details, when known",
+                    "type": "number"
+                },
+                "purchaseDate": {
+                    "type": "string"
+                },
+                "reactionCounts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "reviewNotes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "seasons": {
+                    "description": "Winter, Summer",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sizeLabel": {
+                    "type": "string"
+                },
+                "sleeveLength": {
+                    "description": "Short, Long",
+                    "type": "string"
+                },
+                "sourceUrl": {
+                    "description": "Shop page it was imported from",
+                    "type": "string"
+                },
+                "subCategory": {
+                    "description": "e.g., \"Jacket\", \"Shirt\", \"Pants\"",
+                    "type": "string"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "Good for scaling later",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Collection": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itemIds": {
+                    "description": "References to ClothingItems",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FiberContent": {
+            "type": "object",
+            "properties": {
+                "fiber": {
+                    "description": "e.g., \"Cotton\"",
+                    "type": "string"
+                },
+                "percent": {
+                    "description": "0 when the label doesn't say",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Follow": {
+            "type": "object",
+            "properties": {
+                "acceptedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "followeeId": {
+                    "type": "string"
+                },
+                "followerId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Identity": {
+            "type": "object",
             "properties": {
                 "email": {
                     "type": "string"
                 },
-                "password": {
+                "linkedAt": {
+                    "type": "string"
+                },
+                "provider": {
                     "type": "string"
                 }
             }
         },
-        "handlers.RegisterRequest": {
+        "models.Notification": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "description": "Who caused it; empty for system events such as a finished upload",
+                    "type": "string"
+                },
+                "actorName": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "readAt": {
+                    "type": "string"
+                },
+                "targetId": {
+                    "type": "string"
+                },
+                "targetType": {
+                    "description": "What it is about, e.g. \"item\" and the item's ID, for deep links",
+                    "type": "string"
+                },
+                "text": {
+                    "description": "e.g. \"Sam commented on Denim Jacket\"",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "Recipient",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Outfit": {
+            "type": "object",
+            "properties": {
+                "commentCount": {
+                    "type": "integer"
+                },
+                "commentsDisabled": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "isPublic": {
+                    "description": "Shown on the owner's profile and in followers' feeds",
+                    "type": "boolean"
+                },
+                "itemIds": {
+                    "description": "References to ClothingItems",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reactionCounts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "vibeTags": {
+                    "description": "\"Vibe\" for the whole outfit\ne.g., \"Cozy study session fit\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.PackingActivity": {
             "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
+                "days": {
+                    "description": "0 means every day",
+                    "type": "integer",
+                    "example": 2
                 },
-                "password": {
+                "occasion": {
+                    "description": "One of the taxonomy occasions",
                     "type": "string",
-                    "minLength": 6
-                },
-                "returnToken": {
-                    "type": "boolean"
+                    "example": "Formal"
                 }
             }
         },
-        "handlers.SearchRequest": {
+        "models.PackingDay": {
             "type": "object",
             "properties": {
-                "aiSearch": {
-                    "description": "Toggle between Regex match vs Vector Match",
-                    "type": "boolean"
+                "date": {
+                    "description": "Midnight UTC",
+                    "type": "string"
                 },
-                "categories": {
-                    "description": "Hard filter",
+                "occasions": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "colors": {
-                    "description": "Hard filter",
+                "outfits": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.PackingOutfit"
                     }
+                }
+            }
+        },
+        "models.PackingItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
                 },
-                "query": {
-                    "description": "e.g. \"Dinner date\" or \"Blue jacket\"",
+                "itemId": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Copied from the item so the checklist still reads well if it is deleted",
+                    "type": "string"
+                },
+                "note": {
+                    "description": "e.g. \"In the laundry, wash it before you go\"",
+                    "type": "string"
+                },
+                "packed": {
+                    "type": "boolean"
+                },
+                "packedAt": {
                     "type": "string"
                 }
             }
         },
-        "handlers.UserStatsResponse": {
+        "models.PackingList": {
             "type": "object",
             "properties": {
-                "categoryCounts": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
+                "activities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PackingActivity"
                     }
                 },
-                "colorCounts": {
+                "combinations": {
+                    "description": "How many different outfits the packed items make, per occasion",
                     "type": "object",
                     "additionalProperties": {
                         "type": "integer"
                     }
                 },
-                "totalItems": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.ClothingItem": {
-            "type": "object",
-            "properties": {
-                "category": {
-                    "description": "e.g., \"Outerwear\", \"Top\", \"Bottom\"",
+                "createdAt": {
                     "type": "string"
                 },
-                "colors": {
-                    "description": "Tags",
+                "days": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/models.PackingDay"
                     }
                 },
-                "createdAt": {
-                    "description": "Timestamps",
+                "destination": {
                     "type": "string"
                 },
-                "description": {
-                    "description": "The \"Vibe\" Engine\nGemini will generate a text description, which we then convert to a vector",
+                "endDate": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "imageUrl": {
-                    "description": "Image Data",
-                    "type": "string"
-                },
-                "isPublic": {
-                    "type": "boolean"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PackingItem"
+                    }
                 },
                 "name": {
-                    "description": "Basic Metadata",
                     "type": "string"
                 },
-                "occasions": {
-                    "description": "Casual, Formal",
+                "startDate": {
+                    "description": "First and last day of the trip, both inclusive, at midnight UTC",
+                    "type": "string"
+                },
+                "uncovered": {
+                    "description": "Occasions no outfit could be put together for from the closet",
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "seasons": {
-                    "description": "Winter, Summer",
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "weather": {
+                    "$ref": "#/definitions/models.TripWeather"
+                }
+            }
+        },
+        "models.PackingOutfit": {
+            "type": "object",
+            "properties": {
+                "itemIds": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "subCategory": {
-                    "description": "e.g., \"Jacket\", \"Shirt\", \"Pants\"",
+                "occasion": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ShareLink": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "nil never expires",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastViewedAt": {
+                    "type": "string"
+                },
+                "maxViews": {
+                    "description": "0 is unlimited",
+                    "type": "integer"
+                },
+                "resourceId": {
+                    "type": "string"
+                },
+                "resourceType": {
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TripWeather": {
+            "type": "object",
+            "properties": {
+                "maxTemp": {
+                    "description": "°C",
+                    "type": "number",
+                    "example": 21
+                },
+                "minTemp": {
+                    "description": "°C",
+                    "type": "number",
+                    "example": 12
+                },
+                "rain": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.UsageCounter": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "integer"
+                },
+                "credits": {
+                    "description": "Clipdrop credits",
+                    "type": "integer"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "outputTokens": {
+                    "type": "integer"
+                },
+                "period": {
+                    "description": "\"2006-01\"",
                     "type": "string"
                 },
+                "promptTokens": {
+                    "type": "integer"
+                },
+                "totalTokens": {
+                    "type": "integer"
+                },
                 "updatedAt": {
                     "type": "string"
                 },
                 "userId": {
-                    "description": "Good for scaling later",
                     "type": "string"
                 }
             }
@@ -700,17 +8591,51 @@ const docTemplate = `{
         "models.User": {
             "type": "object",
             "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
                 "createdAt": {
                     "type": "string"
                 },
+                "disabled": {
+                    "description": "Disabled accounts can't sign in; their sessions are revoked when disabled",
+                    "type": "boolean"
+                },
+                "disabledAt": {
+                    "type": "string"
+                },
+                "disabledReason": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
+                "emailVerified": {
+                    "type": "boolean"
+                },
+                "emailVerifiedAt": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "identities": {
+                    "description": "External accounts (OIDC) linked to this user. Users created through\nsocial login have no password until they set one.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Identity"
+                    }
+                },
                 "name": {
                     "type": "string"
+                },
+                "profileVisibility": {
+                    "description": "Empty means ProfilePublic",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Empty means RoleUser",
+                    "type": "string"
                 }
             }
         },
@@ -722,6 +8647,115 @@ const docTemplate = `{
                     "example": "pong"
                 }
             }
+        },
+        "taxonomy.LocalizedAttribute": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxonomy.LocalizedValue"
+                    }
+                }
+            }
+        },
+        "taxonomy.LocalizedCategory": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "subCategories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxonomy.LocalizedSubCategory"
+                    }
+                }
+            }
+        },
+        "taxonomy.LocalizedSubCategory": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "taxonomy.LocalizedTaxonomy": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxonomy.LocalizedAttribute"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxonomy.LocalizedCategory"
+                    }
+                },
+                "colors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxonomy.LocalizedValue"
+                    }
+                },
+                "language": {
+                    "type": "string"
+                },
+                "languages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "occasions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxonomy.LocalizedValue"
+                    }
+                },
+                "seasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/taxonomy.LocalizedValue"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "taxonomy.LocalizedValue": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -2,14 +2,16 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"os"
+	"strconv"
 )
 
-func RemoveBackground(imageBytes []byte, filename string) ([]byte, error) {
+func RemoveBackground(ctx context.Context, imageBytes []byte, filename string) ([]byte, error) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
@@ -20,7 +22,7 @@ func RemoveBackground(imageBytes []byte, filename string) ([]byte, error) {
 	part.Write(imageBytes)
 	writer.Close()
 
-	req, _ := http.NewRequest("POST", "https://clipdrop-api.co/remove-background/v1", body)
+	req, _ := http.NewRequestWithContext(ctx, "POST", "https://clipdrop-api.co/remove-background/v1", body)
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
 	req.Header.Set("x-api-key", os.Getenv("CLIPDROP_API_KEY"))
@@ -36,5 +38,12 @@ func RemoveBackground(imageBytes []byte, filename string) ([]byte, error) {
 		return nil, fmt.Errorf("API error %d", resp.StatusCode)
 	}
 
+	// Clipdrop reports what the call cost; it's 1 credit per image at the time of writing
+	credits, err := strconv.ParseInt(resp.Header.Get("x-credits-consumed"), 10, 64)
+	if err != nil {
+		credits = 1
+	}
+	recordUsage(ctx, Usage{Operation: OpBackgroundRemoval, Model: "clipdrop-remove-background", Credits: credits})
+
 	return io.ReadAll(resp.Body)
 }
@@ -0,0 +1,129 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/exply/armoire/internal/models"
+	"google.golang.org/genai"
+)
+
+// maxWashTemperature is the hottest wash symbol on a care label (95°C)
+const maxWashTemperature = 95
+
+var careOptions = map[string][]string{
+	"wash_method": {models.WashMachine, models.WashHand, models.WashDoNotWash},
+	"bleach":      {models.BleachAny, models.BleachNonChlorine, models.BleachNo},
+	"tumble_dry":  {models.TumbleDryNormal, models.TumbleDryLow, models.TumbleDryNo},
+	"iron":        {models.IronLow, models.IronMedium, models.IronHigh, models.IronNo},
+	"dry_clean":   {models.DryCleanAllowed, models.DryCleanOnly, models.DryCleanNo},
+}
+
+// This struct matches the JSON we want Gemini to read off a care label
+type careLabelAnalysis struct {
+	Fibers []struct {
+		Fiber   string `json:"fiber"`
+		Percent int    `json:"percent"`
+	} `json:"fibers"`
+	WashMethod      string `json:"wash_method"`
+	WashTemperature int    `json:"wash_temperature"`
+	Bleach          string `json:"bleach"`
+	TumbleDry       string `json:"tumble_dry"`
+	Iron            string `json:"iron"`
+	DryClean        string `json:"dry_clean"`
+	LabelText       string `json:"label_text"`
+}
+
+// AnalyzeCareLabel reads fiber composition and care symbols off a photo of a care label
+func (c *AIClient) AnalyzeCareLabel(ctx context.Context, imageData io.Reader, mimeType string) (*models.CareInstructions, error) {
+	prompt := fmt.Sprintf(`
+		You are a textile care expert. Read the care label in this image.
+		Interpret both the printed text and the ISO/GINETEX care symbols.
+
+		STRICT RULES:
+		1. Return ONLY valid JSON.
+		2. Use ONLY the allowed values below, or "" if the symbol is missing or illegible.
+		3. wash_temperature is the maximum wash temperature in °C as a number, or 0 if unknown.
+
+		ALLOWED VALUES:
+		- wash_method: [%s]
+		- bleach: [%s]
+		- tumble_dry: [%s]
+		- iron: [%s]
+		- dry_clean: [%s]
+
+		JSON STRUCTURE:
+		{
+			"fibers": [{"fiber": "Cotton", "percent": 95}, {"fiber": "Elastane", "percent": 5}],
+			"wash_method": "machine",
+			"wash_temperature": 30,
+			"bleach": "do_not_bleach",
+			"tumble_dry": "low",
+			"iron": "medium",
+			"dry_clean": "allowed",
+			"label_text": "All legible text on the label"
+		}
+	`, strings.Join(careOptions["wash_method"], ", "), strings.Join(careOptions["bleach"], ", "),
+		strings.Join(careOptions["tumble_dry"], ", "), strings.Join(careOptions["iron"], ", "),
+		strings.Join(careOptions["dry_clean"], ", "))
+
+	imgBytes, err := io.ReadAll(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image data: %w", err)
+	}
+	parts := []*genai.Part{
+		{Text: prompt},
+		{InlineData: &genai.Blob{Data: imgBytes, MIMEType: mimeType}},
+	}
+
+	var analysis careLabelAnalysis
+	if err := c.generateJSON(ctx, OpCareLabel, parts, &analysis); err != nil {
+		return nil, err
+	}
+
+	return analysis.normalize(), nil
+}
+
+// normalize drops anything outside the allowed care values instead of trusting the model
+func (a careLabelAnalysis) normalize() *models.CareInstructions {
+	care := &models.CareInstructions{
+		Fibers:    []models.FiberContent{},
+		LabelText: strings.TrimSpace(a.LabelText),
+	}
+
+	for _, fiber := range a.Fibers {
+		name := strings.TrimSpace(fiber.Fiber)
+		if name == "" {
+			continue
+		}
+		percent := fiber.Percent
+		if percent < 0 || percent > 100 {
+			percent = 0
+		}
+		care.Fibers = append(care.Fibers, models.FiberContent{Fiber: name, Percent: percent})
+	}
+
+	if a.WashTemperature > 0 && a.WashTemperature <= maxWashTemperature {
+		care.WashTemperature = a.WashTemperature
+	}
+
+	care.WashMethod = careOption("wash_method", a.WashMethod)
+	care.Bleach = careOption("bleach", a.Bleach)
+	care.TumbleDry = careOption("tumble_dry", a.TumbleDry)
+	care.Iron = careOption("iron", a.Iron)
+	care.DryClean = careOption("dry_clean", a.DryClean)
+
+	return care
+}
+
+func careOption(field, value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, option := range careOptions[field] {
+		if option == value {
+			return option
+		}
+	}
+	return ""
+}
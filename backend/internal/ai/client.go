@@ -21,6 +21,21 @@ type ClothingAnalysis struct {
 	Seasons     []string `json:"seasons"`
 	Occasions   []string `json:"occasions"`
 	Description string   `json:"description"`
+
+	// Garment attributes, constrained by taxonomy.AttributeValues
+	Materials    []string `json:"materials"`
+	Pattern      string   `json:"pattern"`
+	Fit          string   `json:"fit"`
+	Neckline     string   `json:"neckline"`
+	SleeveLength string   `json:"sleeve_length"`
+
+	// Free text read off the garment itself
+	Brand     string `json:"brand"`
+	SizeLabel string `json:"size_label"`
+
+	// Set when Gemini's output still failed validation after a re-prompt
+	NeedsReview bool     `json:"-"`
+	ReviewNotes []string `json:"-"`
 }
 
 type AIClient struct {
@@ -46,9 +61,19 @@ func (c *AIClient) AnalyzeImage(ctx context.Context, imageData io.Reader, mimeTy
 
 	// join the slices into comma-separated strings
 	validCategories := strings.Join(taxonomy.Categories, ", ")
-	validSubCategories := strings.Join(taxonomy.SubCategories, ", ")
+	var subCategoryGroups []string
+	for _, category := range taxonomy.Categories {
+		subCategoryGroups = append(subCategoryGroups, category+": "+strings.Join(taxonomy.SubCategoriesByCategory[category], ", "))
+	}
+	validSubCategories := strings.Join(subCategoryGroups, "; ")
 	validColors := strings.Join(taxonomy.Colors, ", ")
+	validSeasons := strings.Join(taxonomy.Seasons, ", ")
 	validOccasions := strings.Join(taxonomy.Occasions, ", ")
+	validMaterials := strings.Join(taxonomy.AttributeValues[taxonomy.AttrMaterial], ", ")
+	validPatterns := strings.Join(taxonomy.AttributeValues[taxonomy.AttrPattern], ", ")
+	validFits := strings.Join(taxonomy.AttributeValues[taxonomy.AttrFit], ", ")
+	validNecklines := strings.Join(taxonomy.AttributeValues[taxonomy.AttrNeckline], ", ")
+	validSleeveLengths := strings.Join(taxonomy.AttributeValues[taxonomy.AttrSleeveLength], ", ")
 
 	prompt := fmt.Sprintf(`
 		You are a fashion archivist. Analyze this image of a clothing item.
@@ -56,12 +81,21 @@ func (c *AIClient) AnalyzeImage(ctx context.Context, imageData io.Reader, mimeTy
 		STRICT RULES:
 		1. Return ONLY valid JSON.
 		2. Use ONLY the allowed values provided below. Do not invent new tags.
+		3. The sub_category must belong to the chosen category.
+		4. Leave an attribute empty ("" or []) when it does not apply or is not visible.
+		5. Only fill brand and size_label with text you can actually read on the item or its labels.
 
 		ALLOWED VALUES:
 		- category: Choose one from [%s]
-		- sub_category: Choose one from [%s]
+		- sub_category: Choose one listed under the chosen category from [%s]
 		- colors: Choose up to 3 from [%s]
-        - occasions: Choose from [%s]
+		- seasons: Choose from [%s]
+		- occasions: Choose from [%s]
+		- materials: Choose up to 2 from [%s]
+		- pattern: Choose one from [%s]
+		- fit: Choose one from [%s]
+		- neckline: Choose one from [%s]
+		- sleeve_length: Choose one from [%s]
 
 		JSON STRUCTURE:
 		{
@@ -71,9 +105,17 @@ func (c *AIClient) AnalyzeImage(ctx context.Context, imageData io.Reader, mimeTy
 			"colors": ["Value1", "Value2"],
 			"seasons": ["Winter", "Fall"],
 			"occasions": ["Casual"],
-			"description": "A detailed visual description for search embedding."
+			"description": "A detailed visual description for search embedding.",
+			"materials": ["Cotton"],
+			"pattern": "Striped",
+			"fit": "Regular",
+			"neckline": "Crew",
+			"sleeve_length": "Short",
+			"brand": "Visible brand or logo text, or empty",
+			"size_label": "Size label text (e.g. 'M', '32x30'), or empty"
 		}
-	`, validCategories, validSubCategories, validColors, validOccasions)
+	`, validCategories, validSubCategories, validColors, validSeasons, validOccasions,
+		validMaterials, validPatterns, validFits, validNecklines, validSleeveLengths)
 
 	// Read image data into bytes
 	imgBytes, err := io.ReadAll(imageData)
@@ -86,33 +128,66 @@ func (c *AIClient) AnalyzeImage(ctx context.Context, imageData io.Reader, mimeTy
 		{InlineData: &genai.Blob{Data: imgBytes, MIMEType: mimeType}},
 	}
 
+	var analysis ClothingAnalysis
+	if err := c.generateJSON(ctx, OpTagging, parts, &analysis); err != nil {
+		return nil, err
+	}
+
+	errs := analysis.Normalize()
+	if len(errs) == 0 {
+		return &analysis, nil
+	}
+
+	// Give Gemini one chance to correct itself before flagging the item
+	correction := fmt.Sprintf(`
+		Your previous answer broke the rules: %s.
+		Answer again with the same JSON STRUCTURE, using ONLY the allowed values.
+	`, errs.Error())
+
+	var retry ClothingAnalysis
+	if err := c.generateJSON(ctx, OpTagging, append(parts, &genai.Part{Text: correction}), &retry); err == nil {
+		retryErrs := retry.Normalize()
+		if len(retryErrs) == 0 {
+			return &retry, nil
+		}
+		if len(retryErrs) < len(errs) {
+			analysis, errs = retry, retryErrs
+		}
+	}
+
+	analysis.NeedsReview = true
+	for _, fieldErr := range errs {
+		analysis.ReviewNotes = append(analysis.ReviewNotes, fieldErr.Field+": "+fieldErr.Message)
+	}
+
+	return &analysis, nil
+}
+
+// generateJSON asks Gemini for a JSON answer and decodes it into out
+func (c *AIClient) generateJSON(ctx context.Context, operation string, parts []*genai.Part, out interface{}) error {
 	resp, err := c.client.Models.GenerateContent(ctx, "gemini-2.5-flash", []*genai.Content{{Parts: parts}}, &genai.GenerateContentConfig{
 		ResponseMIMEType: "application/json",
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
+	recordUsage(ctx, generateUsage(operation, "gemini-2.5-flash", resp))
 
 	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("empty response from Gemini")
+		return fmt.Errorf("empty response from Gemini")
 	}
 
 	// Extract text part
 	part := resp.Candidates[0].Content.Parts[0]
 	if part.Text == "" {
-		return nil, fmt.Errorf("unexpected response format")
+		return fmt.Errorf("unexpected response format")
 	}
 
 	jsonStr := strings.TrimPrefix(part.Text, "```json")
 	jsonStr = strings.TrimPrefix(jsonStr, "```")
 	jsonStr = strings.TrimSuffix(jsonStr, "```")
 
-	var analysis ClothingAnalysis
-	if err := json.Unmarshal([]byte(jsonStr), &analysis); err != nil {
-		return nil, err
-	}
-
-	return &analysis, nil
+	return json.Unmarshal([]byte(jsonStr), out)
 }
 
 // GetEmbedding converts the description into a vector
@@ -122,6 +197,8 @@ func (c *AIClient) GetEmbedding(ctx context.Context, text string) ([]float32, er
 	if err != nil {
 		return nil, err
 	}
+	// The Gemini API reports no token counts for embeddings, so only the call is counted
+	recordUsage(ctx, Usage{Operation: OpEmbedding, Model: "gemini-embedding-001"})
 	return resp.Embeddings[0].Values, nil
 }
 
@@ -149,6 +226,7 @@ func (c *AIClient) GenerateStylistBlurb(ctx context.Context, stats map[string]in
 	if err != nil {
 		return "", err
 	}
+	recordUsage(ctx, generateUsage(OpStylist, "gemini-2.5-flash", resp))
 
 	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
 		return "Your closet is looking great today! Time to mix and match.", nil
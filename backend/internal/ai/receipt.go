@@ -0,0 +1,175 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/taxonomy"
+	"google.golang.org/genai"
+)
+
+// maxReceiptText caps how much of an email is sent to Gemini
+const maxReceiptText = 60_000
+
+// maxReceiptQuantity caps how many drafts one receipt line can turn into
+const maxReceiptQuantity = 10
+
+// ReceiptLine is one clothing item bought, as read off a receipt
+type ReceiptLine struct {
+	Name        string   `json:"name"`
+	Brand       string   `json:"brand"`
+	Size        string   `json:"size"`
+	Color       string   `json:"color"` // As written, e.g. "Navy melange"
+	Colors      []string `json:"-"`     // Color mapped onto the taxonomy, if it could be
+	Category    string   `json:"category"`
+	SubCategory string   `json:"sub_category"`
+	Price       float64  `json:"price"` // Per unit, after discounts
+	Currency    string   `json:"currency"`
+	Quantity    int      `json:"quantity"`
+}
+
+// Receipt is what Gemini reads off an order confirmation or till receipt
+type Receipt struct {
+	Merchant     string        `json:"merchant"`
+	OrderDate    string        `json:"order_date"` // YYYY-MM-DD
+	Currency     string        `json:"currency"`
+	Items        []ReceiptLine `json:"items"`
+	PurchaseDate *time.Time    `json:"-"` // OrderDate, if it's a valid date
+}
+
+// ParseReceipt extracts the clothing bought from an order email's text, or
+// from a photo or PDF of a receipt when data is given instead. Shipping, taxes,
+// gift cards and anything that isn't clothing, shoes or accessories are left out.
+func (c *AIClient) ParseReceipt(ctx context.Context, text string, data []byte, mimeType string) (*Receipt, error) {
+	var subCategoryGroups []string
+	for _, category := range taxonomy.Categories {
+		subCategoryGroups = append(subCategoryGroups, category+": "+strings.Join(taxonomy.SubCategoriesByCategory[category], ", "))
+	}
+
+	prompt := fmt.Sprintf(`
+		You are a meticulous personal shopper. Read this order confirmation or receipt
+		and list the clothing, shoes and accessories that were bought.
+
+		STRICT RULES:
+		1. Return ONLY valid JSON.
+		2. Skip shipping, taxes, discounts, gift cards, returns and anything that is not worn.
+		3. price is the price paid for ONE unit, after discounts, as a number without a currency symbol, or 0 if unknown.
+		4. currency is an ISO 4217 code (e.g. "EUR", "USD"), or "" if unknown.
+		5. order_date is the purchase date as YYYY-MM-DD, or "" if unknown.
+		6. Only fill brand, size and color with what the receipt says; use "" otherwise.
+		7. category and sub_category are your best guess from the allowed values, or "".
+
+		ALLOWED VALUES:
+		- category: Choose one from [%s]
+		- sub_category: Choose one listed under the chosen category from [%s]
+
+		JSON STRUCTURE:
+		{
+			"merchant": "Shop name",
+			"order_date": "2024-03-18",
+			"currency": "EUR",
+			"items": [
+				{
+					"name": "Product name as written",
+					"brand": "Brand, or empty",
+					"size": "M",
+					"color": "Navy",
+					"category": "Top",
+					"sub_category": "T-Shirt",
+					"price": 19.95,
+					"currency": "EUR",
+					"quantity": 1
+				}
+			]
+		}
+	`, strings.Join(taxonomy.Categories, ", "), strings.Join(subCategoryGroups, "; "))
+
+	parts := []*genai.Part{{Text: prompt}}
+	if len(data) > 0 {
+		parts = append(parts, &genai.Part{InlineData: &genai.Blob{Data: data, MIMEType: mimeType}})
+	} else {
+		if len(text) > maxReceiptText {
+			text = text[:maxReceiptText]
+		}
+		parts = append(parts, &genai.Part{Text: "RECEIPT:\n" + text})
+	}
+
+	var receipt Receipt
+	if err := c.generateJSON(ctx, OpReceipt, parts, &receipt); err != nil {
+		return nil, err
+	}
+	receipt.normalize()
+	return &receipt, nil
+}
+
+// normalize tidies the receipt in place, dropping lines without a name and
+// mapping categories and colors onto the taxonomy where possible
+func (r *Receipt) normalize() {
+	r.Merchant = truncate(strings.TrimSpace(r.Merchant), maxLabelText)
+	r.Currency = normalizeCurrency(r.Currency)
+	if date, err := time.Parse("2006-01-02", strings.TrimSpace(r.OrderDate)); err == nil && !date.After(time.Now()) {
+		r.PurchaseDate = &date
+	}
+
+	lines := []ReceiptLine{}
+	for _, line := range r.Items {
+		line.Name = truncate(strings.TrimSpace(line.Name), maxLabelText)
+		if line.Name == "" {
+			continue
+		}
+		line.Brand = truncate(strings.TrimSpace(line.Brand), maxLabelText)
+		line.Size = truncate(strings.TrimSpace(line.Size), maxLabelText)
+		line.Color = truncate(strings.TrimSpace(line.Color), maxLabelText)
+
+		line.Colors = []string{}
+		if color, ok := taxonomy.Normalize(line.Color, taxonomy.Colors); ok {
+			line.Colors = []string{color}
+		}
+
+		subCategory, subOK := taxonomy.Normalize(line.SubCategory, taxonomy.SubCategories)
+		category, categoryOK := taxonomy.Normalize(line.Category, taxonomy.Categories)
+		if subOK {
+			category, _ = taxonomy.CategoryOf(subCategory)
+			categoryOK = true
+		}
+		line.Category, line.SubCategory = "", ""
+		if categoryOK {
+			line.Category = category
+		}
+		if subOK {
+			line.SubCategory = subCategory
+		}
+
+		if line.Price < 0 {
+			line.Price = 0
+		}
+		line.Currency = normalizeCurrency(line.Currency)
+		if line.Currency == "" {
+			line.Currency = r.Currency
+		}
+		if line.Quantity < 1 {
+			line.Quantity = 1
+		}
+		if line.Quantity > maxReceiptQuantity {
+			line.Quantity = maxReceiptQuantity
+		}
+		lines = append(lines, line)
+	}
+	r.Items = lines
+}
+
+// normalizeCurrency returns an upper-case three-letter code, or "" for anything else
+func normalizeCurrency(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return ""
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return ""
+		}
+	}
+	return code
+}
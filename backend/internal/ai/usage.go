@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"context"
+
+	"google.golang.org/genai"
+)
+
+// Operations, as recorded in usage accounting and limited by quotas
+const (
+	OpTagging           = "tagging"            // AnalyzeImage, including its re-prompt
+	OpCareLabel         = "care_label"         // AnalyzeCareLabel
+	OpEmbedding         = "embedding"          // GetEmbedding
+	OpStylist           = "stylist"            // GenerateStylistBlurb
+	OpReceipt           = "receipt"            // ParseReceipt
+	OpBackgroundRemoval = "background_removal" // Clipdrop
+)
+
+var Operations = []string{OpTagging, OpCareLabel, OpEmbedding, OpStylist, OpReceipt, OpBackgroundRemoval}
+
+// Usage is what one call to an AI provider cost
+type Usage struct {
+	Operation    string
+	Model        string
+	PromptTokens int64
+	OutputTokens int64 // Candidates plus thinking tokens, both are billed as output
+	TotalTokens  int64
+	Credits      int64 // Clipdrop credits
+}
+
+// UsageRecorder receives a Usage after every successful provider call
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, u Usage)
+}
+
+type recorderKey struct{}
+
+// WithUsageRecorder attaches r to ctx; calls made with the returned context are reported to it
+func WithUsageRecorder(ctx context.Context, r UsageRecorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+func recordUsage(ctx context.Context, u Usage) {
+	if r, ok := ctx.Value(recorderKey{}).(UsageRecorder); ok {
+		r.RecordUsage(ctx, u)
+	}
+}
+
+// generateUsage reads token counts off a Gemini response
+func generateUsage(operation, model string, resp *genai.GenerateContentResponse) Usage {
+	u := Usage{Operation: operation, Model: model}
+	if m := resp.UsageMetadata; m != nil {
+		u.PromptTokens = int64(m.PromptTokenCount)
+		u.OutputTokens = int64(m.CandidatesTokenCount) + int64(m.ThoughtsTokenCount)
+		u.TotalTokens = int64(m.TotalTokenCount)
+	}
+	return u
+}
@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"strings"
+
+	"github.com/exply/armoire/internal/taxonomy"
+)
+
+// These mirror the "up to N" rules given to Gemini in the analysis prompt
+const (
+	maxColors    = 3
+	maxMaterials = 2
+)
+
+// maxLabelText caps the free text read off brand and size labels
+const maxLabelText = 60
+
+// Normalize coerces the analysis onto the taxonomy in place.
+// Values that cannot be mapped are cleared and reported as field errors.
+func (a *ClothingAnalysis) Normalize() taxonomy.ValidationErrors {
+	var errs taxonomy.ValidationErrors
+
+	a.Name = strings.TrimSpace(a.Name)
+	if a.Name == "" {
+		errs = append(errs, taxonomy.FieldError{Field: "name", Message: "required"})
+	}
+
+	a.Description = strings.TrimSpace(a.Description)
+	if a.Description == "" {
+		errs = append(errs, taxonomy.FieldError{Field: "description", Message: "required"})
+	}
+
+	subCategory, subOK := taxonomy.Normalize(a.SubCategory, taxonomy.SubCategories)
+	category, categoryOK := taxonomy.Normalize(a.Category, taxonomy.Categories)
+
+	// A known sub-category pins down its category, so a bad category can be repaired
+	if !categoryOK && subOK {
+		category, _ = taxonomy.CategoryOf(subCategory)
+		categoryOK = true
+	}
+
+	if !categoryOK {
+		errs = append(errs, taxonomy.FieldError{Field: "category", Value: a.Category, Message: "unknown value"})
+		category = ""
+	}
+	if !subOK {
+		errs = append(errs, taxonomy.FieldError{Field: "sub_category", Value: a.SubCategory, Message: "unknown value"})
+		subCategory = ""
+	} else if categoryOK {
+		if fieldErr := taxonomy.CheckSubCategory(category, subCategory); fieldErr != nil {
+			errs = append(errs, *fieldErr)
+			subCategory = ""
+		}
+	}
+	a.Category = category
+	a.SubCategory = subCategory
+
+	var listErrs taxonomy.ValidationErrors
+	a.Colors, listErrs = taxonomy.NormalizeList("colors", a.Colors, taxonomy.Colors)
+	errs = append(errs, listErrs...)
+	if len(a.Colors) > maxColors {
+		a.Colors = a.Colors[:maxColors]
+	}
+
+	a.Seasons, listErrs = taxonomy.NormalizeList("seasons", a.Seasons, taxonomy.Seasons)
+	errs = append(errs, listErrs...)
+
+	a.Occasions, listErrs = taxonomy.NormalizeList("occasions", a.Occasions, taxonomy.Occasions)
+	errs = append(errs, listErrs...)
+
+	// Attributes are optional, but whatever is given must come from the taxonomy
+	a.Materials, listErrs = taxonomy.NormalizeList("materials", a.Materials, taxonomy.AttributeValues[taxonomy.AttrMaterial])
+	errs = append(errs, listErrs...)
+	if len(a.Materials) > maxMaterials {
+		a.Materials = a.Materials[:maxMaterials]
+	}
+
+	for _, attr := range []struct {
+		field string
+		key   string
+		value *string
+	}{
+		{"pattern", taxonomy.AttrPattern, &a.Pattern},
+		{"fit", taxonomy.AttrFit, &a.Fit},
+		{"neckline", taxonomy.AttrNeckline, &a.Neckline},
+		{"sleeve_length", taxonomy.AttrSleeveLength, &a.SleeveLength},
+	} {
+		raw := strings.TrimSpace(*attr.value)
+		*attr.value = ""
+		if raw == "" || !taxonomy.AttributeApplies(a.SubCategory, attr.key) {
+			continue
+		}
+		canonical, ok := taxonomy.Normalize(raw, taxonomy.AttributeValues[attr.key])
+		if !ok {
+			errs = append(errs, taxonomy.FieldError{Field: attr.field, Value: raw, Message: "unknown value"})
+			continue
+		}
+		*attr.value = canonical
+	}
+	if !taxonomy.AttributeApplies(a.SubCategory, taxonomy.AttrMaterial) {
+		a.Materials = []string{}
+	}
+
+	a.Brand = truncate(strings.TrimSpace(a.Brand), maxLabelText)
+	a.SizeLabel = truncate(strings.TrimSpace(a.SizeLabel), maxLabelText)
+
+	return errs
+}
+
+func truncate(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max])
+	}
+	return s
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minSecretLength is the shortest HMAC secret accepted (256 bits for HS256)
+const minSecretLength = 32
+
+// Key is one signing/verification key, identified by its kid
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{} // nil for verify-only (retired) keys
+	verifyKey interface{}
+}
+
+// CanSign reports whether the key has private material
+func (k *Key) CanSign() bool {
+	return k.signKey != nil
+}
+
+// KeySet holds every key tokens may be verified with, plus the one new tokens are signed with.
+// Rotating keys means adding a new active key and keeping the old one verify-only until its tokens expire.
+type KeySet struct {
+	Active *Key
+	keys   map[string]*Key
+}
+
+// Lookup finds a key by kid
+func (ks *KeySet) Lookup(kid string) (*Key, bool) {
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// keySetFile is the on-disk format of JWT_KEYS_FILE
+type keySetFile struct {
+	ActiveKID string `json:"activeKid"`
+	Keys      []struct {
+		KID            string `json:"kid"`
+		Alg            string `json:"alg"` // HS256, RS256 or EdDSA
+		Secret         string `json:"secret"`
+		PrivateKey     string `json:"privateKey"` // PEM
+		PrivateKeyFile string `json:"privateKeyFile"`
+		PublicKey      string `json:"publicKey"` // PEM
+		PublicKeyFile  string `json:"publicKeyFile"`
+	} `json:"keys"`
+}
+
+// LoadKeySet reads a key set file. Relative PEM paths are resolved against the file's directory.
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keySetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid key set file: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	readPEM := func(inline, filename string) ([]byte, error) {
+		if inline != "" || filename == "" {
+			return []byte(inline), nil
+		}
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(dir, filename)
+		}
+		return os.ReadFile(filename)
+	}
+
+	ks := &KeySet{keys: make(map[string]*Key)}
+	for _, entry := range file.Keys {
+		if entry.KID == "" {
+			return nil, fmt.Errorf("every key needs a kid")
+		}
+		if _, dup := ks.keys[entry.KID]; dup {
+			return nil, fmt.Errorf("duplicate kid %q", entry.KID)
+		}
+
+		privatePEM, err := readPEM(entry.PrivateKey, entry.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", entry.KID, err)
+		}
+		publicPEM, err := readPEM(entry.PublicKey, entry.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", entry.KID, err)
+		}
+
+		key, err := newKey(entry.KID, entry.Alg, []byte(entry.Secret), privatePEM, publicPEM)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", entry.KID, err)
+		}
+		ks.keys[key.ID] = key
+	}
+
+	active, ok := ks.keys[file.ActiveKID]
+	if !ok {
+		return nil, fmt.Errorf("activeKid %q is not in the key set", file.ActiveKID)
+	}
+	if !active.CanSign() {
+		return nil, fmt.Errorf("active key %q has no private key", active.ID)
+	}
+	ks.Active = active
+
+	return ks, nil
+}
+
+// SecretKeySet builds a single-key HS256 set from a shared secret
+func SecretKeySet(kid, secret string) (*KeySet, error) {
+	key, err := newKey(kid, jwt.SigningMethodHS256.Alg(), []byte(secret), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{Active: key, keys: map[string]*Key{kid: key}}, nil
+}
+
+func newKey(kid, alg string, secret, privatePEM, publicPEM []byte) (*Key, error) {
+	key := &Key{ID: kid}
+
+	switch alg {
+	case jwt.SigningMethodHS256.Alg():
+		if len(secret) < minSecretLength {
+			return nil, fmt.Errorf("HS256 secret must be at least %d bytes", minSecretLength)
+		}
+		key.Method = jwt.SigningMethodHS256
+		key.signKey, key.verifyKey = secret, secret
+
+	case jwt.SigningMethodRS256.Alg():
+		key.Method = jwt.SigningMethodRS256
+		if len(privatePEM) > 0 {
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey, key.verifyKey = private, &private.PublicKey
+		}
+		if len(publicPEM) > 0 {
+			public, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+			if err != nil {
+				return nil, err
+			}
+			key.verifyKey = public
+		}
+		if rsaKey, ok := key.verifyKey.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("RSA keys must be at least 2048 bits")
+		}
+
+	case jwt.SigningMethodEdDSA.Alg():
+		key.Method = jwt.SigningMethodEdDSA
+		if len(privatePEM) > 0 {
+			private, err := jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			edKey, ok := private.(ed25519.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("not an Ed25519 private key")
+			}
+			key.signKey, key.verifyKey = edKey, edKey.Public()
+		}
+		if len(publicPEM) > 0 {
+			public, err := jwt.ParseEdPublicKeyFromPEM(publicPEM)
+			if err != nil {
+				return nil, err
+			}
+			key.verifyKey = public
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported alg %q (use HS256, RS256 or EdDSA)", alg)
+	}
+
+	if key.verifyKey == nil {
+		return nil, fmt.Errorf("no key material")
+	}
+	return key, nil
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Defaults for the iss/aud claims when JWT_ISSUER / JWT_AUDIENCE are not set
+const (
+	DefaultIssuer   = "armoire"
+	DefaultAudience = "armoire-api"
+)
+
+// leeway absorbs small clock differences between servers
+const leeway = 30 * time.Second
+
+var (
+	keys     *KeySet
+	issuer   string
+	audience string
+)
+
+// Claims are the claims of an Armoire access token
+type Claims struct {
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// Init loads the signing keys and claim settings from the environment.
+// JWT_KEYS_FILE (a key set for RS256/EdDSA/HS256 with rotation) takes precedence over JWT_SECRET.
+// It returns an error when no usable key is configured so the server refuses to start.
+func Init() error {
+	var err error
+
+	switch {
+	case os.Getenv("JWT_KEYS_FILE") != "":
+		keys, err = LoadKeySet(os.Getenv("JWT_KEYS_FILE"))
+	case os.Getenv("JWT_SECRET") != "":
+		keys, err = SecretKeySet("default", os.Getenv("JWT_SECRET"))
+	default:
+		err = errors.New("no signing key configured (set JWT_KEYS_FILE or JWT_SECRET)")
+	}
+	if err != nil {
+		return err
+	}
+
+	issuer = envOr("JWT_ISSUER", DefaultIssuer)
+	audience = envOr("JWT_AUDIENCE", DefaultAudience)
+	return nil
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// IssueAccessToken signs a token for userID bound to sessionID with the active key
+func IssueAccessToken(userID, sessionID string, ttl time.Duration) (string, error) {
+	if keys == nil {
+		return "", errors.New("auth keys not initialized")
+	}
+
+	now := time.Now()
+	claims := Claims{
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	token := jwt.NewWithClaims(keys.Active.Method, claims)
+	token.Header["kid"] = keys.Active.ID
+	return token.SignedString(keys.Active.signKey)
+}
+
+// VerifyAccessToken is the single place access tokens are checked. It pins the
+// algorithm to the one registered for the token's kid and requires iss, aud, exp, nbf, sub and sid.
+func VerifyAccessToken(tokenString string) (*Claims, error) {
+	if keys == nil {
+		return nil, errors.New("auth keys not initialized")
+	}
+
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys.Lookup(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+		// Never let the token choose its own algorithm (e.g. "none" or HS256 with a public key)
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+		}
+		return key.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{"HS256", "RS256", "EdDSA"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(leeway),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// nbf is validated by the parser when present; make it mandatory too
+	if claims.NotBefore == nil || claims.Subject == "" || claims.SessionID == "" {
+		return nil, errors.New("token is missing required claims")
+	}
+
+	return &claims, nil
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>" header
+func BearerToken(header string) (string, bool) {
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(prefix):]), true
+}
@@ -0,0 +1,298 @@
+package capsule
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/exply/armoire/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kinds of wardrobe gap
+const (
+	GapOccasion = "occasion"
+	GapSeason   = "season"
+	GapCategory = "category"
+)
+
+// Fewer outfits than this for an occasion or season counts as under-served
+const thinOutfits = 3
+
+// How many key pieces and suggestions an analysis lists
+const (
+	maxKeyPieces   = 5
+	maxSuggestions = 5
+)
+
+// Seasons in which outfits want outerwear on top
+var coldSeasons = []string{"Fall", "Winter"}
+
+var slotCategories = map[string]string{
+	SlotTop:       "Tops",
+	SlotBottom:    "Bottoms",
+	SlotOnePiece:  "Dresses",
+	SlotShoes:     "Shoes",
+	SlotOuterwear: "Outerwear",
+}
+
+// ItemOutfits is an item and how many outfits it's part of
+type ItemOutfits struct {
+	ItemID  string `json:"itemId"`
+	Outfits int    `json:"outfits"`
+}
+
+// Suggestion is an item the closet doesn't have that would make new outfits
+type Suggestion struct {
+	Category   string `json:"category"`
+	Color      string `json:"color"`
+	Occasion   string `json:"occasion"`
+	NewOutfits int    `json:"newOutfits"`
+}
+
+// Gap is an occasion, season or category the closet doesn't serve well
+type Gap struct {
+	Kind    string `json:"kind"`  // occasion, season or category
+	Value   string `json:"value"` // e.g. "Formal"
+	Outfits int    `json:"outfits"`
+	// Categories with no suitable items, e.g. ["Shoes"]
+	Missing []string `json:"missing"`
+	Message string   `json:"message"` // e.g. "No Formal shoes"
+}
+
+// Analysis is how well a closet's items combine into outfits
+type Analysis struct {
+	// Distinct outfits: a top and a bottom, or a one-piece, with shoes, that
+	// go together and share an occasion. Outerwear is layered on top and
+	// doesn't count towards new outfits.
+	TotalOutfits int            `json:"totalOutfits"`
+	ByOccasion   map[string]int `json:"byOccasion"`
+	BySeason     map[string]int `json:"bySeason"`
+
+	// The items that are part of the most outfits
+	KeyPieces []ItemOutfits `json:"keyPieces"`
+	// Single items to add that would make the most new outfits
+	Suggestions []Suggestion `json:"suggestions"`
+	Gaps        []Gap        `json:"gaps"`
+	// Items that don't go with anything, so can't be part of any outfit
+	OrphanItemIDs []string `json:"orphanItemIds"`
+}
+
+// Analyze works out how items combine into outfits for each of occasions
+// and seasons, which items matter most, what's missing and what could be
+// added. Only occasions at least one item is tagged with are analyzed.
+func Analyze(items []models.ClothingItem, occasions, seasons []string) Analysis {
+	seasons = slices.DeleteFunc(slices.Clone(seasons), func(s string) bool { return s == allSeasons })
+	seasons = seasons[:min(len(seasons), maxTags)]
+	result := Analysis{
+		ByOccasion:    map[string]int{},
+		BySeason:      map[string]int{},
+		KeyPieces:     []ItemOutfits{},
+		Suggestions:   []Suggestion{},
+		Gaps:          []Gap{},
+		OrphanItemIDs: []string{},
+	}
+
+	var used []string
+	for _, occasion := range occasions {
+		for _, item := range items {
+			if Suits(item, Conditions{Occasion: occasion}) {
+				used = append(used, occasion)
+				break
+			}
+		}
+	}
+	used = used[:min(len(used), maxTags)]
+	for _, season := range seasons {
+		result.BySeason[season] = 0
+	}
+
+	closet := newGroups(used, seasons)
+	for _, item := range items {
+		closet.add(item)
+	}
+	// Outerwear isn't part of an outfit, it goes with the outfits it's
+	// compatible with; those are counted per style of outerwear
+	var coatStyles []int
+	for _, coat := range closet.bySlot[SlotOuterwear] {
+		if !slices.Contains(coatStyles, coat.style) {
+			coatStyles = append(coatStyles, coat.style)
+		}
+	}
+	outfits := map[*group]int{} // Per item of the group
+	coatOutfits := map[int]int{}
+	closet.eachOutfit(func(outfit []*group, n int, occasionSet, seasonSet uint64) {
+		result.TotalOutfits += n
+		for i, occasion := range used {
+			if occasionSet&(1<<i) != 0 {
+				result.ByOccasion[occasion] += n
+			}
+		}
+		for i, season := range seasons {
+			if seasonSet&(1<<i) != 0 {
+				result.BySeason[season] += n
+			}
+		}
+		for _, g := range outfit {
+			outfits[g] += n / len(g.items)
+		}
+		for _, style := range coatStyles {
+			if closet.goesWith(style, outfit) {
+				coatOutfits[style] += n
+			}
+		}
+	})
+	for _, coat := range closet.bySlot[SlotOuterwear] {
+		outfits[coat] = coatOutfits[coat.style]
+	}
+
+	for _, item := range items {
+		g := closet.groupOf[item.ID]
+		if g == nil {
+			continue
+		}
+		if n := outfits[g]; n == 0 {
+			result.OrphanItemIDs = append(result.OrphanItemIDs, item.ID.Hex())
+		} else if SlotOf(item) != SlotOuterwear {
+			result.KeyPieces = append(result.KeyPieces, ItemOutfits{ItemID: item.ID.Hex(), Outfits: n})
+		}
+	}
+	sort.SliceStable(result.KeyPieces, func(i, j int) bool { return result.KeyPieces[i].Outfits > result.KeyPieces[j].Outfits })
+	if len(result.KeyPieces) > maxKeyPieces {
+		result.KeyPieces = result.KeyPieces[:maxKeyPieces]
+	}
+
+	result.Gaps = gaps(items, used, seasons, result)
+	result.Suggestions = suggestions(items, used)
+	return result
+}
+
+// gaps finds the categories the closet has nothing in, and the occasions and
+// seasons with few or no outfits along with the categories they're missing
+func gaps(items []models.ClothingItem, occasions, seasons []string, analysis Analysis) []Gap {
+	result := []Gap{}
+
+	have := map[string]bool{}
+	for _, item := range items {
+		have[SlotOf(item)] = true
+	}
+	for _, slot := range Slots {
+		if !have[slot] && slot != SlotOnePiece {
+			category := slotCategories[slot]
+			result = append(result, Gap{Kind: GapCategory, Value: category, Missing: []string{category}, Message: "No " + strings.ToLower(category)})
+		}
+	}
+
+	for _, occasion := range occasions {
+		n := analysis.ByOccasion[occasion]
+		if n >= thinOutfits {
+			continue
+		}
+		missing := missingCategories(items, Conditions{Occasion: occasion}, false)
+		result = append(result, Gap{Kind: GapOccasion, Value: occasion, Outfits: n, Missing: missing, Message: gapMessage(occasion, n, missing)})
+	}
+
+	for _, season := range seasons {
+		n := analysis.BySeason[season]
+		cold := slices.Contains(coldSeasons, season)
+		missing := missingCategories(items, Conditions{Seasons: []string{season}}, cold)
+		if n >= thinOutfits && !slices.Contains(missing, slotCategories[SlotOuterwear]) {
+			continue
+		}
+		result = append(result, Gap{Kind: GapSeason, Value: season, Outfits: n, Missing: missing, Message: gapMessage(season, n, missing)})
+	}
+	return result
+}
+
+// missingCategories lists the categories an outfit for cond needs but no item suits
+func missingCategories(items []models.ClothingItem, cond Conditions, outerwear bool) []string {
+	have := map[string]bool{}
+	for _, item := range items {
+		if Suits(item, cond) {
+			have[SlotOf(item)] = true
+		}
+	}
+	missing := []string{}
+	if !have[SlotOnePiece] {
+		for _, slot := range []string{SlotTop, SlotBottom} {
+			if !have[slot] {
+				missing = append(missing, slotCategories[slot])
+			}
+		}
+	}
+	if !have[SlotShoes] {
+		missing = append(missing, slotCategories[SlotShoes])
+	}
+	if outerwear && !have[SlotOuterwear] {
+		missing = append(missing, slotCategories[SlotOuterwear])
+	}
+	return missing
+}
+
+func gapMessage(value string, outfits int, missing []string) string {
+	if len(missing) > 0 {
+		lower := make([]string, len(missing))
+		for i, m := range missing {
+			lower[i] = strings.ToLower(m)
+		}
+		return fmt.Sprintf("No %s %s", value, strings.Join(lower, " or "))
+	}
+	if outfits == 0 {
+		return fmt.Sprintf("Nothing for %s goes together", value)
+	}
+	return fmt.Sprintf("Only %d %s outfit(s)", outfits, value)
+}
+
+// suggestions tries adding a plain item of each category, in each neutral
+// color and each color already in the closet, for each occasion. It returns
+// the best color for each category and occasion, for the ones that would
+// make the most new outfits.
+func suggestions(items []models.ClothingItem, occasions []string) []Suggestion {
+	colors := append([]string(nil), neutralColors...)
+	for _, item := range items {
+		for _, color := range boldColors(item) {
+			if !slices.Contains(colors, color) {
+				colors = append(colors, color)
+			}
+		}
+	}
+
+	result := []Suggestion{}
+	for _, slot := range []string{SlotTop, SlotBottom, SlotOnePiece, SlotShoes} {
+		for _, occasion := range occasions {
+			others := without(items, primitive.NilObjectID, slot, []string{occasion})
+			best := Suggestion{Category: slotCategories[slot], Occasion: occasion}
+			for _, color := range colors {
+				item := models.ClothingItem{Category: slotCategories[slot], Colors: []string{color}, Occasions: []string{occasion}}
+				if n := others.outfitsWith(others.newGroup(item), slot); n > best.NewOutfits {
+					best.Color, best.NewOutfits = color, n
+				}
+			}
+			if best.NewOutfits > 0 {
+				result = append(result, best)
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].NewOutfits > result[j].NewOutfits })
+	if len(result) > maxSuggestions {
+		result = result[:maxSuggestions]
+	}
+	return result
+}
+
+// NewOutfits returns how many outfits item would make with closet, across
+// the occasions it's tagged with. For outerwear, it's how many outfits it
+// could be layered over.
+func NewOutfits(closet []models.ClothingItem, item models.ClothingItem) int {
+	slot := SlotOf(item)
+	if slot == "" {
+		return 0
+	}
+	occasions := item.Occasions
+	if len(occasions) == 0 {
+		occasions = []string{DefaultOccasion}
+	}
+	others := without(closet, item.ID, slot, occasions)
+	return others.outfitsWith(others.newGroup(item), slot)
+}
@@ -0,0 +1,196 @@
+package capsule
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/exply/armoire/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxTags is how many occasions or seasons a group's tag sets can hold
+const maxTags = 64
+
+// group is a set of interchangeable items: they fill the same slot, suit the
+// same occasions and seasons, and go with exactly the same other items.
+// Outfits are counted per combination of groups by multiplying group sizes,
+// as building them one by one takes too long for big closets, which make
+// hundreds of thousands.
+type group struct {
+	items     []models.ClothingItem
+	style     int    // Index into groups.styles
+	occasions uint64 // Bit i is set if the items suit occasions[i]
+	seasons   uint64 // Bit i is set if the items suit seasons[i]
+}
+
+// groups sorts the items that fill a slot into groups. Only the first
+// maxTags occasions and seasons are told apart.
+type groups struct {
+	occasions []string
+	seasons   []string
+
+	bySlot  map[string][]*group
+	groupOf map[primitive.ObjectID]*group
+	byKey   map[string]*group
+
+	// What goes together only depends on pattern and bold colors, so it's
+	// worked out once per distinct style
+	styles     []models.ClothingItem
+	styleOf    map[string]int
+	compatible [][]bool
+}
+
+func newGroups(occasions, seasons []string) *groups {
+	return &groups{
+		occasions: occasions[:min(len(occasions), maxTags)],
+		seasons:   seasons[:min(len(seasons), maxTags)],
+		bySlot:    map[string][]*group{},
+		groupOf:   map[primitive.ObjectID]*group{},
+		byKey:     map[string]*group{},
+		styleOf:   map[string]int{},
+	}
+}
+
+// add puts item in its group and returns the group, or nil if the item
+// doesn't fill a slot
+func (gs *groups) add(item models.ClothingItem) *group {
+	slot := SlotOf(item)
+	if slot == "" {
+		return nil
+	}
+	g := gs.newGroup(item)
+	key := slot + "|" + strconv.Itoa(g.style) + "|" + strconv.FormatUint(g.occasions, 16) + "|" + strconv.FormatUint(g.seasons, 16)
+	if existing := gs.byKey[key]; existing != nil {
+		existing.items = append(existing.items, item)
+		g = existing
+	} else {
+		gs.byKey[key] = g
+		gs.bySlot[slot] = append(gs.bySlot[slot], g)
+	}
+	gs.groupOf[item.ID] = g
+	return g
+}
+
+// newGroup returns a group of just item, without adding it to gs
+func (gs *groups) newGroup(item models.ClothingItem) *group {
+	g := &group{items: []models.ClothingItem{item}, style: gs.styleFor(item)}
+	for i, occasion := range gs.occasions {
+		if Suits(item, Conditions{Occasion: occasion}) {
+			g.occasions |= 1 << i
+		}
+	}
+	for i, season := range gs.seasons {
+		if Suits(item, Conditions{Seasons: []string{season}}) {
+			g.seasons |= 1 << i
+		}
+	}
+	return g
+}
+
+// styleFor returns the index of item's style, adding it if it's new
+func (gs *groups) styleFor(item models.ClothingItem) int {
+	bold := boldColors(item)
+	sort.Strings(bold)
+	key := strconv.FormatBool(patterned(item)) + "|" + strings.Join(bold, ",")
+	if style, ok := gs.styleOf[key]; ok {
+		return style
+	}
+
+	style := len(gs.styles)
+	gs.styleOf[key] = style
+	gs.styles = append(gs.styles, item)
+	row := make([]bool, style+1)
+	for i, other := range gs.styles {
+		row[i] = Compatible(item, other)
+		if i < style {
+			gs.compatible[i] = append(gs.compatible[i], row[i])
+		}
+	}
+	gs.compatible = append(gs.compatible, row)
+	return style
+}
+
+// outfitsWith counts the outfits g's items would make, filling slot, with
+// the items in gs, which must have nothing else in slot. For outerwear, it's
+// how many outfits they could be layered over.
+func (gs *groups) outfitsWith(g *group, slot string) int {
+	n := 0
+	if slot == SlotOuterwear {
+		gs.eachOutfit(func(outfit []*group, count int, occasionSet, _ uint64) {
+			if occasionSet&g.occasions != 0 && gs.goesWith(g.style, outfit) {
+				n += count * len(g.items)
+			}
+		})
+		return n
+	}
+
+	gs.bySlot[slot] = []*group{g}
+	gs.eachOutfit(func(outfit []*group, count int, _, _ uint64) {
+		if slices.Contains(outfit, g) {
+			n += count
+		}
+	})
+	delete(gs.bySlot, slot)
+	return n
+}
+
+// without groups closet's items for occasions, leaving out the item with id
+// and any that fill slot, as an outfit can't have two items in one slot
+func without(closet []models.ClothingItem, id primitive.ObjectID, slot string, occasions []string) *groups {
+	gs := newGroups(occasions, nil)
+	for _, item := range closet {
+		if SlotOf(item) != slot && item.ID != id {
+			gs.add(item)
+		}
+	}
+	return gs
+}
+
+// eachOutfit calls fn with every combination of groups that makes outfits: a
+// top and a bottom, or a one-piece, with shoes, that go together and share an
+// occasion. n is how many outfits the combination stands for; occasionSet and
+// seasonSet are what they all suit. The slice passed to fn is reused.
+func (gs *groups) eachOutfit(fn func(outfit []*group, n int, occasionSet, seasonSet uint64)) {
+	var bases [][]*group
+	for _, top := range gs.bySlot[SlotTop] {
+		for _, bottom := range gs.bySlot[SlotBottom] {
+			if top.occasions&bottom.occasions != 0 && gs.compatible[top.style][bottom.style] {
+				bases = append(bases, []*group{top, bottom})
+			}
+		}
+	}
+	for _, onePiece := range gs.bySlot[SlotOnePiece] {
+		bases = append(bases, []*group{onePiece})
+	}
+
+	outfit := make([]*group, 0, 3)
+	for _, base := range bases {
+		for _, shoes := range gs.bySlot[SlotShoes] {
+			if !gs.goesWith(shoes.style, base) {
+				continue
+			}
+			outfit = append(append(outfit[:0], base...), shoes)
+			n, occasionSet, seasonSet := 1, ^uint64(0), ^uint64(0)
+			for _, g := range outfit {
+				n *= len(g.items)
+				occasionSet &= g.occasions
+				seasonSet &= g.seasons
+			}
+			if occasionSet != 0 {
+				fn(outfit, n, occasionSet, seasonSet)
+			}
+		}
+	}
+}
+
+// goesWith reports whether items of style go with every group in outfit
+func (gs *groups) goesWith(style int, outfit []*group) bool {
+	for _, other := range outfit {
+		if !gs.compatible[style][other.style] {
+			return false
+		}
+	}
+	return true
+}
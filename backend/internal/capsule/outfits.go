@@ -0,0 +1,183 @@
+package capsule
+
+import (
+	"slices"
+
+	"github.com/exply/armoire/internal/models"
+)
+
+// Slots an item can fill in an outfit
+const (
+	SlotTop       = "top"
+	SlotBottom    = "bottom"
+	SlotOnePiece  = "one_piece" // Dresses, jumpsuits and rompers
+	SlotShoes     = "shoes"
+	SlotOuterwear = "outerwear"
+)
+
+// Slots lists every slot in the order an outfit is put together
+var Slots = []string{SlotTop, SlotBottom, SlotOnePiece, SlotShoes, SlotOuterwear}
+
+// Accessories don't fill a slot; they go with anything
+var categorySlots = map[string]string{
+	"Tops":      SlotTop,
+	"Bottoms":   SlotBottom,
+	"Dresses":   SlotOnePiece,
+	"Shoes":     SlotShoes,
+	"Outerwear": SlotOuterwear,
+}
+
+// Colors that go with anything, most versatile first. Blue covers denim.
+var neutralColors = []string{"Black", "White", "Grey", "Beige", "Brown", "Blue", "Gold", "Silver"}
+
+// DefaultOccasion is assumed for items without occasion tags
+const DefaultOccasion = "Casual"
+
+const allSeasons = "All Season"
+
+// Conditions narrow down which items and outfits are suitable
+type Conditions struct {
+	Occasion string
+	// Items must be tagged with one of these, or with none at all; empty means any season
+	Seasons []string
+	// Whether outfits need outerwear on top
+	Outerwear bool
+}
+
+// SlotOf returns the slot an item fills, or "" if it doesn't fill one
+func SlotOf(item models.ClothingItem) string {
+	return categorySlots[item.Category]
+}
+
+// Suits reports whether an item can be part of an outfit for cond
+func Suits(item models.ClothingItem, cond Conditions) bool {
+	if SlotOf(item) == "" {
+		return false
+	}
+	if cond.Occasion != "" {
+		occasions := item.Occasions
+		if len(occasions) == 0 {
+			occasions = []string{DefaultOccasion}
+		}
+		if !slices.Contains(occasions, cond.Occasion) {
+			return false
+		}
+	}
+	if len(cond.Seasons) > 0 && len(item.Seasons) > 0 && !slices.Contains(item.Seasons, allSeasons) {
+		for _, season := range item.Seasons {
+			if slices.Contains(cond.Seasons, season) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Compatible reports whether two items can be worn together: at most one of
+// them is patterned, and any bold colors they both have are shared
+func Compatible(a, b models.ClothingItem) bool {
+	if patterned(a) && patterned(b) {
+		return false
+	}
+	boldA, boldB := boldColors(a), boldColors(b)
+	if len(boldA) == 0 || len(boldB) == 0 {
+		return true
+	}
+	for _, color := range boldA {
+		if slices.Contains(boldB, color) {
+			return true
+		}
+	}
+	return false
+}
+
+// Enumerate calls fn with every outfit that can be put together from items
+// for cond, until fn returns false. An outfit is a top and a bottom, or a
+// one-piece, with shoes, plus outerwear when cond asks for it. The slice
+// passed to fn is reused between calls.
+func Enumerate(items []models.ClothingItem, cond Conditions, fn func(outfit []models.ClothingItem) bool) {
+	bySlot := map[string][]models.ClothingItem{}
+	for _, item := range items {
+		if Suits(item, cond) {
+			slot := SlotOf(item)
+			bySlot[slot] = append(bySlot[slot], item)
+		}
+	}
+
+	var bases [][]models.ClothingItem
+	for _, top := range bySlot[SlotTop] {
+		for _, bottom := range bySlot[SlotBottom] {
+			if Compatible(top, bottom) {
+				bases = append(bases, []models.ClothingItem{top, bottom})
+			}
+		}
+	}
+	for _, onePiece := range bySlot[SlotOnePiece] {
+		bases = append(bases, []models.ClothingItem{onePiece})
+	}
+
+	layers := [][]models.ClothingItem{bySlot[SlotShoes]}
+	if cond.Outerwear {
+		layers = append(layers, bySlot[SlotOuterwear])
+	}
+
+	outfit := make([]models.ClothingItem, 0, 4)
+	var build func(layer int) bool
+	build = func(layer int) bool {
+		if layer == len(layers) {
+			return fn(outfit)
+		}
+		for _, item := range layers[layer] {
+			if !fitsWith(item, outfit) {
+				continue
+			}
+			outfit = append(outfit, item)
+			more := build(layer + 1)
+			outfit = outfit[:len(outfit)-1]
+			if !more {
+				return false
+			}
+		}
+		return true
+	}
+	for _, base := range bases {
+		outfit = append(outfit[:0], base...)
+		if !build(0) {
+			return
+		}
+	}
+}
+
+// Count returns how many outfits can be put together from items for cond
+func Count(items []models.ClothingItem, cond Conditions) int {
+	n := 0
+	Enumerate(items, cond, func([]models.ClothingItem) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func fitsWith(item models.ClothingItem, outfit []models.ClothingItem) bool {
+	for _, other := range outfit {
+		if !Compatible(item, other) {
+			return false
+		}
+	}
+	return true
+}
+
+func patterned(item models.ClothingItem) bool {
+	return item.Pattern != "" && item.Pattern != "Solid"
+}
+
+func boldColors(item models.ClothingItem) []string {
+	var bold []string
+	for _, color := range item.Colors {
+		if !slices.Contains(neutralColors, color) {
+			bold = append(bold, color)
+		}
+	}
+	return bold
+}
@@ -0,0 +1,228 @@
+package capsule
+
+import (
+	"sort"
+
+	"github.com/exply/armoire/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// After a week, outfits are worn again rather than packing more for new ones
+const maxDistinctOutfits = 7
+
+// Each slot is narrowed down to its most versatile items before combining,
+// which keeps the search small for big closets
+const maxPerSlot = 12
+
+// Packing is the capsule picked for a trip and what to wear on each day
+type Packing struct {
+	Items []models.ClothingItem
+	// The trip's days with their Outfits filled in
+	Days []models.PackingDay
+	// How many different outfits Items make, per occasion
+	Combinations map[string]int
+	// Occasions no outfit could be put together for
+	Uncovered []string
+}
+
+// SeasonsFor returns the seasons whose clothes suit the expected temperatures
+func SeasonsFor(weather models.TripWeather) []string {
+	var seasons []string
+	if weather.MinTemp < 8 {
+		seasons = append(seasons, "Winter")
+	}
+	if weather.MinTemp < 20 && weather.MaxTemp >= 8 {
+		seasons = append(seasons, "Spring", "Fall")
+	}
+	if weather.MaxTemp >= 20 {
+		seasons = append(seasons, "Summer")
+	}
+	return seasons
+}
+
+// NeedsOuterwear reports whether the weather calls for a jacket or coat
+func NeedsOuterwear(weather models.TripWeather) bool {
+	return weather.MinTemp < 15 || weather.Rain
+}
+
+// Pack picks as few items from closet as it can while still giving every
+// occasion of the trip a different outfit each day it comes up (up to a
+// week's worth), preferring items that suit several occasions and go with
+// many others. It then assigns outfits to days, spreading wear and avoiding
+// the same items two days running. weather may be nil if it isn't known.
+func Pack(closet []models.ClothingItem, days []models.PackingDay, weather *models.TripWeather) Packing {
+	var occasions []string
+	need := map[string]int{}
+	for _, day := range days {
+		for _, occasion := range day.Occasions {
+			if need[occasion] == 0 {
+				occasions = append(occasions, occasion)
+			}
+			need[occasion]++
+		}
+	}
+	conditions := map[string]Conditions{}
+	for _, occasion := range occasions {
+		cond := Conditions{Occasion: occasion}
+		if weather != nil {
+			cond.Seasons = SeasonsFor(*weather)
+			cond.Outerwear = NeedsOuterwear(*weather)
+		}
+		conditions[occasion] = cond
+		if need[occasion] > maxDistinctOutfits {
+			need[occasion] = maxDistinctOutfits
+		}
+	}
+
+	pool, score := candidates(closet, occasions, conditions)
+
+	// Occasions that come up most are filled first, so the rest can reuse their items
+	order := append([]string(nil), occasions...)
+	sort.SliceStable(order, func(i, j int) bool { return need[order[i]] > need[order[j]] })
+
+	selected := map[primitive.ObjectID]bool{}
+	var packed []models.ClothingItem
+	for _, occasion := range order {
+		cond := conditions[occasion]
+		for Count(packed, cond) < need[occasion] {
+			// The outfit that adds the fewest items, and the most versatile ones
+			var best []models.ClothingItem
+			bestNew, bestScore := 0, 0
+			Enumerate(pool, cond, func(outfit []models.ClothingItem) bool {
+				added, gain := 0, 0
+				for _, item := range outfit {
+					if !selected[item.ID] {
+						added++
+						gain += score[item.ID]
+					}
+				}
+				if added > 0 && (best == nil || added < bestNew || (added == bestNew && gain > bestScore)) {
+					best = append(best[:0], outfit...)
+					bestNew, bestScore = added, gain
+				}
+				return true
+			})
+			if best == nil {
+				break
+			}
+			for _, item := range best {
+				if !selected[item.ID] {
+					selected[item.ID] = true
+					packed = append(packed, item)
+				}
+			}
+		}
+	}
+
+	result := Packing{
+		Items:        packed,
+		Days:         make([]models.PackingDay, len(days)),
+		Combinations: map[string]int{},
+		Uncovered:    []string{},
+	}
+	looks := map[string][][]primitive.ObjectID{}
+	for _, occasion := range occasions {
+		Enumerate(packed, conditions[occasion], func(outfit []models.ClothingItem) bool {
+			ids := make([]primitive.ObjectID, len(outfit))
+			for i, item := range outfit {
+				ids[i] = item.ID
+			}
+			looks[occasion] = append(looks[occasion], ids)
+			return true
+		})
+		result.Combinations[occasion] = len(looks[occasion])
+		if len(looks[occasion]) == 0 {
+			result.Uncovered = append(result.Uncovered, occasion)
+		}
+	}
+
+	uses := map[string][]int{}
+	for occasion, list := range looks {
+		uses[occasion] = make([]int, len(list))
+	}
+	var yesterday map[primitive.ObjectID]bool
+	for i, day := range days {
+		result.Days[i] = models.PackingDay{Date: day.Date, Occasions: day.Occasions, Outfits: []models.PackingOutfit{}}
+		today := map[primitive.ObjectID]bool{}
+		for _, occasion := range day.Occasions {
+			pick, pickUses, pickRepeats := -1, 0, 0
+			for j, ids := range looks[occasion] {
+				repeats := 0
+				for _, id := range ids {
+					if yesterday[id] {
+						repeats++
+					}
+				}
+				n := uses[occasion][j]
+				if pick < 0 || n < pickUses || (n == pickUses && repeats < pickRepeats) {
+					pick, pickUses, pickRepeats = j, n, repeats
+				}
+			}
+			if pick < 0 {
+				continue
+			}
+			uses[occasion][pick]++
+			ids := looks[occasion][pick]
+			for _, id := range ids {
+				today[id] = true
+			}
+			result.Days[i].Outfits = append(result.Days[i].Outfits, models.PackingOutfit{Occasion: occasion, ItemIDs: ids})
+		}
+		yesterday = today
+	}
+
+	// Same order as they'd be laid out: tops first, shoes and outerwear last
+	slotRank := map[string]int{}
+	for i, slot := range Slots {
+		slotRank[slot] = i
+	}
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		a, b := result.Items[i], result.Items[j]
+		if SlotOf(a) != SlotOf(b) {
+			return slotRank[SlotOf(a)] < slotRank[SlotOf(b)]
+		}
+		return a.Name < b.Name
+	})
+	return result
+}
+
+// candidates narrows closet down to the most versatile items for the trip in
+// each slot, and scores them by how many of its occasions they suit and how
+// many other candidates they go with
+func candidates(closet []models.ClothingItem, occasions []string, conditions map[string]Conditions) ([]models.ClothingItem, map[primitive.ObjectID]int) {
+	suited := map[primitive.ObjectID]int{}
+	var pool []models.ClothingItem
+	for _, item := range closet {
+		for _, occasion := range occasions {
+			if Suits(item, conditions[occasion]) {
+				suited[item.ID]++
+			}
+		}
+		if suited[item.ID] > 0 {
+			pool = append(pool, item)
+		}
+	}
+
+	score := map[primitive.ObjectID]int{}
+	for _, item := range pool {
+		partners := 0
+		for _, other := range pool {
+			if SlotOf(other) != SlotOf(item) && Compatible(item, other) {
+				partners++
+			}
+		}
+		score[item.ID] = suited[item.ID]*1000 + partners
+	}
+
+	sort.SliceStable(pool, func(i, j int) bool { return score[pool[i].ID] > score[pool[j].ID] })
+	kept := map[string]int{}
+	narrowed := pool[:0]
+	for _, item := range pool {
+		slot := SlotOf(item)
+		if kept[slot] < maxPerSlot {
+			kept[slot]++
+			narrowed = append(narrowed, item)
+		}
+	}
+	return narrowed, score
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// uniqueIndexes stop concurrent upserts from inserting the same document
+// twice; the handlers treat the duplicate key error as "already there"
+var uniqueIndexes = []struct {
+	collection string
+	keys       bson.D
+}{
+	{"reactions", bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "emoji", Value: 1}}},
+	{"follows", bson.D{{Key: "follower_id", Value: 1}, {Key: "followee_id", Value: 1}}},
+	{"users", bson.D{{Key: "email", Value: 1}}},
+	{"ai_usage", bson.D{{Key: "user_id", Value: 1}, {Key: "period", Value: 1}, {Key: "operation", Value: 1}}},
+}
+
+// ensureIndexes creates any missing unique index. One that can't be built,
+// e.g. because duplicates already exist, is logged rather than fatal.
+func ensureIndexes() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	for _, index := range uniqueIndexes {
+		_, err := GetCollection(index.collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    index.keys,
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			log.Printf("Could not create unique index on %s: %v", index.collection, err)
+		}
+	}
+}
@@ -32,9 +32,27 @@ func InitDB(uri string) {
 
 	fmt.Println("Connected to MongoDB Atlas!")
 	Client = client
+
+	ensureIndexes()
 }
 
 // GetCollection returns a handle to a specific collection
 func GetCollection(collectionName string) *mongo.Collection {
 	return Client.Database("armoire-db").Collection(collectionName)
 }
+
+// WithTransaction runs fn in a transaction, retrying it on transient errors
+// such as a write conflict with a concurrent transaction. Transactions need
+// a replica set (Atlas clusters always are one).
+func WithTransaction(ctx context.Context, fn func(ctx mongo.SessionContext) error) error {
+	session, err := Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
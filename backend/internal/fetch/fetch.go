@@ -0,0 +1,153 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// DefaultMaxBytes caps how much of a response is read
+const DefaultMaxBytes = 10 << 20
+
+const maxRedirects = 5
+
+var (
+	ErrInvalidURL     = errors.New("only http and https URLs can be fetched")
+	ErrTooLarge       = errors.New("response is too large")
+	ErrPrivateAddress = errors.New("URL points at a private address")
+)
+
+// Fetcher downloads user-supplied URLs
+type Fetcher struct {
+	Client    *http.Client
+	MaxBytes  int64
+	UserAgent string
+}
+
+// Response is a fetched document
+type Response struct {
+	URL         string // After redirects
+	ContentType string
+	Body        []byte
+}
+
+// New returns a Fetcher whose client refuses to connect to loopback,
+// private, carrier-grade NAT and link-local addresses, so users can't make the server reach
+// internal services. The check runs on the resolved address, after any
+// redirect, so DNS tricks don't get around it.
+func New() *Fetcher {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: publicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Fetcher{
+		Client: &http.Client{
+			Timeout:   20 * time.Second,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return errors.New("too many redirects")
+				}
+				return checkScheme(req.URL)
+			},
+		},
+		MaxBytes:  DefaultMaxBytes,
+		UserAgent: "Armoire/1.0",
+	}
+}
+
+// Get fetches rawURL, failing on non-2xx statuses and bodies over MaxBytes
+func (f *Fetcher) Get(ctx context.Context, rawURL string) (*Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, ErrInvalidURL
+	}
+	if err := checkScheme(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrPrivateAddress) {
+			return nil, ErrPrivateAddress
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s returned %s", u.Host, resp.Status)
+	}
+
+	limit := f.MaxBytes
+	if limit <= 0 {
+		limit = DefaultMaxBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, ErrTooLarge
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	return &Response{URL: resp.Request.URL.String(), ContentType: contentType, Body: body}, nil
+}
+
+func checkScheme(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+// nonPublicNets are ranges net.IP has no predicate for
+var nonPublicNets = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // "This network"
+	mustParseCIDR("100.64.0.0/10"), // Carrier-grade NAT, also used by some clouds internally
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return ipNet
+}
+
+// publicOnly is a net.Dialer Control func that refuses non-public addresses
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return ErrPrivateAddress
+	}
+	for _, ipNet := range nonPublicNets {
+		if ipNet.Contains(ip) {
+			return ErrPrivateAddress
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestPublicOnly(t *testing.T) {
+	blocked := []string{
+		"127.0.0.1:80",
+		"127.8.9.10:80",
+		"[::1]:443",
+		"[::ffff:127.0.0.1]:80",
+		"10.0.0.1:80",
+		"172.16.5.4:80",
+		"172.31.255.255:80",
+		"192.168.1.1:80",
+		"169.254.169.254:80", // Cloud metadata
+		"100.64.0.1:80",
+		"100.127.255.254:80",
+		"0.0.0.0:80",
+		"0.1.2.3:80",
+		"[::]:80",
+		"[fc00::1]:80",
+		"[fe80::1]:80",
+		"224.0.0.1:80",
+	}
+	for _, address := range blocked {
+		if err := publicOnly("tcp", address, nil); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("publicOnly(%s) = %v, want ErrPrivateAddress", address, err)
+		}
+	}
+
+	allowed := []string{
+		"8.8.8.8:443",
+		"172.32.0.1:80",
+		"100.63.255.255:80",
+		"100.128.0.1:80",
+		"[2001:4860:4860::8888]:443",
+	}
+	for _, address := range allowed {
+		if err := publicOnly("tcp", address, nil); err != nil {
+			t.Errorf("publicOnly(%s) = %v, want nil", address, err)
+		}
+	}
+}
+
+func TestGetRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the fetcher connected to a loopback server")
+	}))
+	defer server.Close()
+
+	if _, err := New().Get(context.Background(), server.URL); !errors.Is(err, ErrPrivateAddress) {
+		t.Errorf("Get() error = %v, want ErrPrivateAddress", err)
+	}
+}
+
+func TestGetRefusesScheme(t *testing.T) {
+	for _, rawURL := range []string{"ftp://example.com/file", "file:///etc/passwd", "/relative", "http://"} {
+		if _, err := New().Get(context.Background(), rawURL); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("Get(%q) error = %v, want ErrInvalidURL", rawURL, err)
+		}
+	}
+}
+
+// trusting returns New() with the address of server treated as public, so
+// tests can see what happens after a public site answers
+func trusting(server *httptest.Server) *Fetcher {
+	public := server.Listener.Addr().String()
+	f := New()
+	dialer := &net.Dialer{Control: func(network, address string, c syscall.RawConn) error {
+		if address == public {
+			return nil
+		}
+		return publicOnly(network, address, c)
+	}}
+	f.Client.Transport.(*http.Transport).DialContext = dialer.DialContext
+	return f
+}
+
+func TestGetRefusesRedirectToPrivate(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the fetcher followed a redirect to a private address")
+	}))
+	defer internal.Close()
+
+	targets := map[string]string{
+		"loopback":   internal.URL + "/admin",
+		"metadata":   "http://169.254.169.254/latest/meta-data/",
+		"rfc1918":    "http://10.0.0.1/",
+		"cgnat":      "http://100.64.0.1/",
+		"ipv6 local": "http://[::1]:8080/",
+	}
+	for name, target := range targets {
+		t.Run(name, func(t *testing.T) {
+			public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, target, http.StatusFound)
+			}))
+			defer public.Close()
+
+			if _, err := trusting(public).Get(context.Background(), public.URL); !errors.Is(err, ErrPrivateAddress) {
+				t.Errorf("Get() error = %v, want ErrPrivateAddress", err)
+			}
+		})
+	}
+}
+
+func TestGetRefusesRedirectScheme(t *testing.T) {
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+	}))
+	defer public.Close()
+
+	if _, err := trusting(public).Get(context.Background(), public.URL); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("Get() error = %v, want ErrInvalidURL", err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/page", http.StatusMovedPermanently)
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>hello</html>"))
+		case "/big":
+			w.Write([]byte(strings.Repeat("x", 101)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer public.Close()
+	f := trusting(public)
+
+	resp, err := f.Get(context.Background(), public.URL+"/old")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if resp.URL != public.URL+"/page" || resp.ContentType != "text/html" || string(resp.Body) != "<html>hello</html>" {
+		t.Errorf("Get() = %+v", resp)
+	}
+
+	if _, err := f.Get(context.Background(), public.URL+"/missing"); err == nil {
+		t.Error("Get() of a 404 succeeded")
+	}
+
+	f.MaxBytes = 100
+	if _, err := f.Get(context.Background(), public.URL+"/big"); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Get() error = %v, want ErrTooLarge", err)
+	}
+}
@@ -0,0 +1,494 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/imageproc"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/notify"
+	"github.com/exply/armoire/internal/storage"
+	"github.com/exply/armoire/internal/usage"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const avatarSize = 256
+
+type UpdateProfileRequest struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email" binding:"omitempty,email"`
+	// public, followers or private
+	ProfileVisibility *string `json:"profileVisibility"`
+	// Required to change the email of an account that has a password
+	CurrentPassword string `json:"currentPassword"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword" binding:"required,min=6"`
+}
+
+type DeleteAccountRequest struct {
+	// Required for accounts that have a password; social-only accounts confirm with "DELETE"
+	Password string `json:"password"`
+	Confirm  string `json:"confirm"`
+}
+
+// PurgeReport lists everything removed when an account is deleted
+type PurgeReport struct {
+	Documents     map[string]int64 `json:"documents"` // Deleted documents per collection
+	Images        int              `json:"images"`    // Stored objects removed (photos, thumbnails, care labels, avatar)
+	ImageFailures []string         `json:"imageFailures,omitempty"`
+}
+
+// userOwnedCollections are purged, by the named user ID field, when an account is deleted
+var userOwnedCollections = []struct{ name, field string }{
+	{"clothing", "user_id"},
+	{"outfits", "user_id"},
+	{"collections", "user_id"},
+	{"wear_logs", "user_id"},
+	{"sessions", "user_id"},
+	{"user_tokens", "user_id"},
+	{"stream_tickets", "user_id"},
+	{"share_links", "user_id"},
+	{"follows", "follower_id"},
+	{"follows", "followee_id"},
+	{"borrow_requests", "owner_id"},
+	{"borrow_requests", "borrower_id"},
+	{"comments", "user_id"},
+	{"comments", "owner_id"},
+	{"reactions", "user_id"},
+	{"reactions", "owner_id"},
+	{"plan_entries", "user_id"},
+	{"packing_lists", "user_id"},
+	{"wishlist", "user_id"},
+	{notify.Collection, "user_id"},
+	{notify.Collection, "actor_id"},
+	{usage.Collection, "user_id"},
+}
+
+func loadUser(c *gin.Context) (*models.User, bool) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return nil, false
+	}
+	objectID, err := primitive.ObjectIDFromHex(userIDVal.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID format"})
+		return nil, false
+	}
+
+	var user models.User
+	if err := database.GetCollection("users").FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&user); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	return &user, true
+}
+
+// @Summary Update profile
+// @Description Change the authenticated user's name, email or profile visibility. Changing the email needs currentPassword (unless the account is social-only); the new address must be re-verified, a verification link is sent to it and the old address is told about the change. Making a profile public accepts all pending follow requests.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateProfileRequest true "Fields to change"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 401 {object} map[string]string "Current password is incorrect"
+// @Failure 409 {object} map[string]string "Email already registered"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /user/profile [patch]
+func UpdateProfileHandler(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := loadUser(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	collection := database.GetCollection("users")
+	set := bson.M{}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+			return
+		}
+		set["name"] = name
+		user.Name = name
+	}
+
+	emailChanged := false
+	oldEmail := user.Email
+	if req.Email != nil {
+		email := models.NormalizeEmail(*req.Email)
+		if email != user.Email {
+			// A stolen session alone mustn't be enough to take over the account
+			if user.Password != "" {
+				if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+					return
+				}
+			}
+			count, _ := collection.CountDocuments(ctx, bson.M{"email": email, "_id": bson.M{"$ne": user.ID}})
+			if count > 0 {
+				c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
+				return
+			}
+			emailChanged = true
+		}
+		set["email"] = email
+		user.Email = email
+	}
+
+	if req.ProfileVisibility != nil {
+		if !validVisibility(*req.ProfileVisibility) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "profileVisibility must be public, followers or private"})
+			return
+		}
+		set["profile_visibility"] = *req.ProfileVisibility
+		user.ProfileVisibility = *req.ProfileVisibility
+	}
+
+	if len(set) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	update := bson.M{"$set": set}
+	if emailChanged {
+		set["email_verified"] = false
+		update["$unset"] = bson.M{"email_verified_at": ""}
+		user.EmailVerified = false
+		user.EmailVerifiedAt = nil
+	}
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": user.ID}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+		return
+	}
+
+	if emailChanged {
+		// Outstanding links were issued for the old address
+		if _, err := database.GetCollection("user_tokens").DeleteMany(ctx, bson.M{"user_id": user.ID.Hex()}); err != nil {
+			log.Printf("Could not clear tokens after email change for %s: %v", user.ID.Hex(), err)
+		}
+		if err := sendVerificationEmail(ctx, *user); err != nil {
+			log.Printf("Verification email failed for user %s: %v", user.ID.Hex(), err)
+		}
+		if err := sendEmailChangedNotice(ctx, *user, oldEmail); err != nil {
+			log.Printf("Email change notice failed for user %s: %v", user.ID.Hex(), err)
+		}
+	}
+	if user.Visibility() == models.ProfilePublic {
+		if err := acceptPendingFollows(ctx, user.ID.Hex()); err != nil {
+			log.Printf("Could not accept pending follows for %s: %v", user.ID.Hex(), err)
+		}
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Upload avatar
+// @Description Upload a profile picture. It is resized and replaces any previous avatar.
+// @Tags user
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param avatar formData file true "Avatar image"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string "Invalid file"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /user/avatar [post]
+func UploadAvatarHandler(c *gin.Context) {
+	user, ok := loadUser(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+		return
+	}
+	if !strings.HasPrefix(http.DetectContentType(data), "image/") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Avatar must be an image"})
+		return
+	}
+
+	filename := user.ID.Hex() + "_avatar_" + primitive.NewObjectID().Hex() + filepath.Ext(fileHeader.Filename)
+	if resized, err := imageproc.CreateThumbnail(data, avatarSize); err == nil {
+		data = resized
+		filename = strings.TrimSuffix(filename, filepath.Ext(filename)) + ".png"
+	}
+
+	gcsClient, _ := storage.NewStorageClient("armoire-bucket")
+	gcsURI, err := gcsClient.UploadFile(bytes.NewReader(data), filename)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload to GCS"})
+		return
+	}
+	publicURL := "https://storage.googleapis.com/armoire-bucket/" + filename
+
+	_, err = database.GetCollection("users").UpdateOne(c.Request.Context(),
+		bson.M{"_id": user.ID},
+		bson.M{"$set": bson.M{"avatar_url": publicURL, "avatar_gcs_uri": gcsURI}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+		return
+	}
+
+	if user.AvatarGCSURI != "" {
+		if err := gcsClient.DeleteFile(user.AvatarGCSURI); err != nil {
+			fmt.Printf("Warning: Failed to delete old avatar from GCS: %v\n", err)
+		}
+	}
+
+	user.AvatarURL = publicURL
+	user.AvatarGCSURI = gcsURI
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Change password
+// @Description Change the password after confirming the current one. Every other session is signed out. Social-only accounts can set a first password without a current one.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 401 {object} map[string]string "Current password is incorrect"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /user/password [put]
+func ChangePasswordHandler(c *gin.Context) {
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := loadUser(c)
+	if !ok {
+		return
+	}
+
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+			return
+		}
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	_, err = database.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": user.ID},
+		bson.M{"$set": bson.M{"password": string(hashedPassword)}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not change password"})
+		return
+	}
+
+	// Keep the caller signed in, sign everyone else out
+	filter := bson.M{"user_id": user.ID.Hex()}
+	if sessionID, ok := c.Get("sessionID"); ok {
+		if sid, err := primitive.ObjectIDFromHex(sessionID.(string)); err == nil {
+			filter["_id"] = bson.M{"$ne": sid}
+		}
+	}
+	if _, err := revokeSessions(ctx, filter); err != nil {
+		log.Printf("Could not revoke sessions after password change for %s: %v", user.ID.Hex(), err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed"})
+}
+
+// @Summary Delete account
+// @Description Permanently delete the account with all of its clothing, outfits, collections, share links, wear logs, sessions and stored images, and report what was removed
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteAccountRequest true "Password (or confirm: \"DELETE\" for social-only accounts)"
+// @Success 200 {object} handlers.PurgeReport
+// @Failure 400 {object} map[string]string "Confirmation missing"
+// @Failure 401 {object} map[string]string "Password is incorrect"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /user [delete]
+func DeleteAccountHandler(c *gin.Context) {
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, ok := loadUser(c)
+	if !ok {
+		return
+	}
+
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect"})
+			return
+		}
+	} else if req.Confirm != "DELETE" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": `Send "confirm": "DELETE" to delete this account`})
+		return
+	}
+
+	report, err := purgeUserData(c.Request.Context(), *user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete account: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// purgeUserData removes the user and everything they own. Stored images are
+// deleted first; failures there are reported rather than aborting the purge.
+func purgeUserData(ctx context.Context, user models.User) (*PurgeReport, error) {
+	userID := user.ID.Hex()
+	report := &PurgeReport{Documents: map[string]int64{}}
+
+	cursor, err := database.GetCollection("clothing").Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	var items []models.ClothingItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	cursor, err = database.GetCollection("wishlist").Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	var wishes []models.WishlistItem
+	if err := cursor.All(ctx, &wishes); err != nil {
+		return nil, err
+	}
+
+	gcsClient, err := storage.NewStorageClient("armoire-bucket")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, uri := range userObjects(gcsClient, user, items, wishes) {
+		if err := gcsClient.DeleteFile(uri); err != nil {
+			fmt.Printf("Warning: Failed to delete %s from GCS: %v\n", uri, err)
+			report.ImageFailures = append(report.ImageFailures, uri)
+			continue
+		}
+		report.Images++
+	}
+
+	// Items lent to this user go back to their owners' closets
+	cursor, err = database.GetCollection("borrow_requests").Find(ctx, bson.M{"borrower_id": userID, "status": models.BorrowActive})
+	if err != nil {
+		return nil, err
+	}
+	var loans []models.BorrowRequest
+	if err := cursor.All(ctx, &loans); err != nil {
+		return nil, err
+	}
+	for _, loan := range loans {
+		if err := returnItem(ctx, loan, time.Now()); err != nil {
+			log.Printf("Could not return item %s lent to deleted user %s: %v", loan.ItemID.Hex(), userID, err)
+		}
+	}
+
+	if err := retractEngagement(ctx, userID); err != nil {
+		return nil, fmt.Errorf("engagement counters: %w", err)
+	}
+
+	for _, owned := range userOwnedCollections {
+		result, err := database.GetCollection(owned.name).DeleteMany(ctx, bson.M{owned.field: userID})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", owned.name, err)
+		}
+		report.Documents[owned.name] += result.DeletedCount
+	}
+
+	result, err := database.GetCollection("users").DeleteOne(ctx, bson.M{"_id": user.ID})
+	if err != nil {
+		return nil, fmt.Errorf("users: %w", err)
+	}
+	report.Documents["users"] = result.DeletedCount
+
+	return report, nil
+}
+
+// userObjects lists the gs:// URIs of every stored object belonging to the user and their items
+func userObjects(gcsClient *storage.StorageClient, user models.User, items []models.ClothingItem, wishes []models.WishlistItem) []string {
+	// Thumbnails fall back to the full image URL, so dedupe
+	seen := map[string]bool{}
+	var uris []string
+	add := func(uri string) {
+		if uri != "" && !seen[uri] {
+			seen[uri] = true
+			uris = append(uris, uri)
+		}
+	}
+	for _, item := range items {
+		add(item.GCSURI)
+		if uri, ok := gcsClient.URIFromPublicURL(item.ThumbnailURL); ok {
+			add(uri)
+		}
+		add(item.CareLabelGCSURI)
+	}
+	for _, wish := range wishes {
+		add(wish.GCSURI)
+		if uri, ok := gcsClient.URIFromPublicURL(wish.ThumbnailURL); ok {
+			add(uri)
+		}
+	}
+	add(user.AvatarGCSURI)
+	return uris
+}
@@ -0,0 +1,501 @@
+package handlers
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/exply/armoire/internal/ai"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/storage"
+	"github.com/exply/armoire/internal/usage"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	adminPageSize    = 50
+	adminMaxPageSize = 200
+	storageWorkers   = 8 // Concurrent metadata lookups when sizing a user's storage
+)
+
+// AdminUser is a user as listed to admins. The embedded models.User already
+// keeps the password hash and identity subjects out of JSON.
+type AdminUser struct {
+	models.User
+	ItemCount int64 `json:"itemCount"`
+}
+
+type AdminUserList struct {
+	Users []AdminUser `json:"users"`
+	Total int64       `json:"total"`
+	Page  int         `json:"page"`
+	Limit int         `json:"limit"`
+}
+
+type StorageUsage struct {
+	Objects int64    `json:"objects"`
+	Bytes   int64    `json:"bytes"`
+	Missing []string `json:"missing,omitempty"` // Referenced but not found in the bucket
+}
+
+type AdminUserDetail struct {
+	AdminUser
+	OutfitCount    int64        `json:"outfitCount"`
+	ActiveSessions int64        `json:"activeSessions"`
+	Storage        StorageUsage `json:"storage"`
+}
+
+type DisableUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// @Summary List users
+// @Description Admin only. Page through users, optionally searching name and email and filtering by role or disabled state.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Matches name or email (case-insensitive)"
+// @Param role query string false "user or admin"
+// @Param disabled query bool false "Only disabled (true) or enabled (false) accounts"
+// @Param page query int false "Page number, from 1"
+// @Param limit query int false "Page size (max 200)"
+// @Success 200 {object} handlers.AdminUserList
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/users [get]
+func ListUsersHandler(c *gin.Context) {
+	filter := bson.M{}
+	if q := c.Query("q"); q != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(q), Options: "i"}
+		filter["$or"] = []bson.M{{"name": pattern}, {"email": pattern}}
+	}
+	switch c.Query("role") {
+	case "":
+	case models.RoleUser:
+		filter["role"] = bson.M{"$in": []interface{}{models.RoleUser, nil}}
+	case models.RoleAdmin:
+		filter["role"] = models.RoleAdmin
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown role"})
+		return
+	}
+	if disabled, err := strconv.ParseBool(c.Query("disabled")); err == nil {
+		if disabled {
+			filter["disabled"] = true
+		} else {
+			filter["disabled"] = bson.M{"$ne": true}
+		}
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	page = max(page, 1)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(adminPageSize)))
+	if limit < 1 {
+		limit = adminPageSize
+	}
+	limit = min(limit, adminMaxPageSize)
+
+	ctx := c.Request.Context()
+	collection := database.GetCollection("users")
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count users"})
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := collection.Find(ctx, filter, opts)
+	var users []models.User
+	if err == nil {
+		err = cursor.All(ctx, &users)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	// Item counts for the page in one query
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID.Hex()
+	}
+	counts := map[string]int64{}
+	cursor, err = database.GetCollection("clothing").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": bson.M{"$in": ids}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$user_id", "count": bson.M{"$sum": 1}}}},
+	})
+	if err == nil {
+		var rows []struct {
+			UserID string `bson:"_id"`
+			Count  int64  `bson:"count"`
+		}
+		if err := cursor.All(ctx, &rows); err == nil {
+			for _, row := range rows {
+				counts[row.UserID] = row.Count
+			}
+		}
+	}
+
+	result := AdminUserList{Users: []AdminUser{}, Total: total, Page: page, Limit: limit}
+	for _, u := range users {
+		result.Users = append(result.Users, AdminUser{User: u, ItemCount: counts[u.ID.Hex()]})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// adminTargetUser loads the user named by the :id path parameter
+func adminTargetUser(c *gin.Context) (*models.User, bool) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+	var user models.User
+	err = database.GetCollection("users").FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return nil, false
+	}
+	return &user, true
+}
+
+// @Summary Get a user
+// @Description Admin only. A user with item, outfit and active session counts and the storage their images take up.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} handlers.AdminUserDetail
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/users/{id} [get]
+func GetUserDetailHandler(c *gin.Context) {
+	user, ok := adminTargetUser(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	userID := user.ID.Hex()
+
+	var items []models.ClothingItem
+	cursor, err := database.GetCollection("clothing").Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetProjection(bson.M{"embedding": 0}))
+	if err == nil {
+		err = cursor.All(ctx, &items)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+	var wishes []models.WishlistItem
+	cursor, err = database.GetCollection("wishlist").Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetProjection(bson.M{"embedding": 0}))
+	if err == nil {
+		err = cursor.All(ctx, &wishes)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch wishlist"})
+		return
+	}
+
+	outfits, _ := database.GetCollection("outfits").CountDocuments(ctx, bson.M{"user_id": userID})
+	sessions, _ := database.GetCollection("sessions").CountDocuments(ctx, bson.M{
+		"user_id":    userID,
+		"revoked_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": time.Now()},
+	})
+
+	gcsClient, err := storage.NewStorageClient("armoire-bucket")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Storage unavailable"})
+		return
+	}
+
+	c.JSON(http.StatusOK, AdminUserDetail{
+		AdminUser:      AdminUser{User: *user, ItemCount: int64(len(items))},
+		OutfitCount:    outfits,
+		ActiveSessions: sessions,
+		Storage:        storageUsage(gcsClient, userObjects(gcsClient, *user, items, wishes)),
+	})
+}
+
+// storageUsage sizes every object, a few at a time
+func storageUsage(gcsClient *storage.StorageClient, uris []string) StorageUsage {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		result StorageUsage
+	)
+	sem := make(chan struct{}, storageWorkers)
+	for _, uri := range uris {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(uri string) {
+			defer func() { <-sem; wg.Done() }()
+			size, err := gcsClient.Size(uri)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Missing = append(result.Missing, uri)
+				return
+			}
+			result.Objects++
+			result.Bytes += size
+		}(uri)
+	}
+	wg.Wait()
+	return result
+}
+
+// @Summary Disable a user
+// @Description Admin only. The user is signed out everywhere and can't sign in again until re-enabled. Their data is kept.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body DisableUserRequest false "Reason, shown to other admins"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string "Admins can't disable themselves"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /admin/users/{id}/disable [post]
+func DisableUserHandler(c *gin.Context) {
+	var req DisableUserRequest
+	c.ShouldBindJSON(&req) // The body is optional
+
+	user, ok := adminTargetUser(c)
+	if !ok {
+		return
+	}
+	if user.ID.Hex() == c.GetString("userID") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can't disable your own account"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	now := time.Now()
+	_, err := database.GetCollection("users").UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{
+		"$set": bson.M{"disabled": true, "disabled_at": now, "disabled_reason": req.Reason},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable user"})
+		return
+	}
+
+	// AuthMiddleware checks the session on every request, so this locks them out immediately
+	if _, err := revokeSessions(ctx, bson.M{"user_id": user.ID.Hex()}); err != nil {
+		log.Printf("Could not revoke sessions of disabled user %s: %v", user.ID.Hex(), err)
+	}
+
+	user.Disabled = true
+	user.DisabledAt = &now
+	user.DisabledReason = req.Reason
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Re-enable a user
+// @Description Admin only. Lets a disabled user sign in again.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /admin/users/{id}/enable [post]
+func EnableUserHandler(c *gin.Context) {
+	user, ok := adminTargetUser(c)
+	if !ok {
+		return
+	}
+
+	_, err := database.GetCollection("users").UpdateOne(c.Request.Context(), bson.M{"_id": user.ID}, bson.M{
+		"$unset": bson.M{"disabled": "", "disabled_at": "", "disabled_reason": ""},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enable user"})
+		return
+	}
+
+	user.Disabled = false
+	user.DisabledAt = nil
+	user.DisabledReason = ""
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Change a user's role
+// @Description Admin only. Promote a user to admin or demote them back to user.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body SetRoleRequest true "New role"
+// @Success 200 {object} models.User
+// @Failure 400 {object} map[string]string "Unknown role, or demoting yourself"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /admin/users/{id}/role [put]
+func SetUserRoleHandler(c *gin.Context) {
+	var req SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !slices.Contains(models.Roles, req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown role"})
+		return
+	}
+
+	user, ok := adminTargetUser(c)
+	if !ok {
+		return
+	}
+	// Keeps the last admin from locking everyone out of the admin API
+	if user.ID.Hex() == c.GetString("userID") && req.Role != models.RoleAdmin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can't remove your own admin role"})
+		return
+	}
+
+	_, err := database.GetCollection("users").UpdateOne(c.Request.Context(), bson.M{"_id": user.ID}, bson.M{
+		"$set": bson.M{"role": req.Role},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+
+	user.Role = req.Role
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Re-process a clothing item
+// @Description Admin only. Re-run AI tagging, care label reading and the embedding on an item's stored images, e.g. after a taxonomy or model change. Manual edits to tagged fields are overwritten. Usage is booked to the admin.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clothing item ID"
+// @Success 200 {object} models.ClothingItem
+// @Failure 400 {object} map[string]string "Invalid clothing ID"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 404 {object} map[string]string "Clothing item not found"
+// @Failure 409 {object} map[string]string "Item has no photo yet"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/clothing/{id}/reprocess [post]
+func ReprocessClothingHandler(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clothing ID"})
+		return
+	}
+
+	ctx := ai.WithUsageRecorder(c.Request.Context(), usage.ForUser(c.GetString("userID")))
+	collection := database.GetCollection("clothing")
+
+	var item models.ClothingItem
+	err = collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Clothing item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing item"})
+		return
+	}
+	// Drafts from receipts have nothing to re-process yet
+	if item.Draft || item.GCSURI == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Item has no photo yet"})
+		return
+	}
+
+	gcsClient, _ := storage.NewStorageClient("armoire-bucket")
+	imageBytes, err := gcsClient.ReadFile(item.GCSURI)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read image from storage"})
+		return
+	}
+
+	aiClient, _ := ai.NewAIClient(ctx)
+	analysis, err := aiClient.AnalyzeImage(ctx, bytes.NewReader(imageBytes), http.DetectContentType(imageBytes))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "AI Analysis Failed: " + err.Error()})
+		return
+	}
+
+	embedText := analysis.Description
+	if embedText == "" {
+		embedText = analysis.Name
+	}
+	vector, err := aiClient.GetEmbedding(ctx, embedText)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Vector Embedding Failed"})
+		return
+	}
+
+	set := bson.M{
+		"name":          analysis.Name,
+		"category":      analysis.Category,
+		"sub_category":  analysis.SubCategory,
+		"description":   analysis.Description,
+		"colors":        analysis.Colors,
+		"seasons":       analysis.Seasons,
+		"occasions":     analysis.Occasions,
+		"materials":     analysis.Materials,
+		"pattern":       analysis.Pattern,
+		"fit":           analysis.Fit,
+		"neckline":      analysis.Neckline,
+		"sleeve_length": analysis.SleeveLength,
+		"brand_text":    analysis.Brand,
+		"size_label":    analysis.SizeLabel,
+		"needs_review":  analysis.NeedsReview,
+		"review_notes":  analysis.ReviewNotes,
+		"embedding":     vector,
+		"updated_at":    time.Now(),
+	}
+
+	// A label that can't be re-read keeps its previous instructions
+	if item.CareLabelGCSURI != "" {
+		if labelBytes, err := gcsClient.ReadFile(item.CareLabelGCSURI); err == nil {
+			if care, err := aiClient.AnalyzeCareLabel(ctx, bytes.NewReader(labelBytes), http.DetectContentType(labelBytes)); err == nil {
+				set["care"] = care
+			} else {
+				log.Printf("Reprocess %s: care label analysis failed: %v", item.ID.Hex(), err)
+			}
+		}
+	}
+
+	var updated models.ClothingItem
+	err = collection.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updated)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save clothing item"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/exply/armoire/internal/capsule"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/taxonomy"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ClosetAnalysisResponse is a capsule analysis of the closet
+type ClosetAnalysisResponse struct {
+	capsule.Analysis
+	TotalItems int `json:"totalItems"`
+	// Details of the key pieces and orphans
+	Clothing []SharedItem `json:"clothing"`
+}
+
+// analyzeCloset runs a capsule analysis over the user's items, or only the
+// ones available right now
+func analyzeCloset(ctx context.Context, userID string, availableOnly bool) ([]models.ClothingItem, capsule.Analysis, error) {
+	// Drafts have no occasions or seasons to match on until their photo is added
+	filter := bson.M{"user_id": userID, "draft": bson.M{"$ne": true}}
+	if availableOnly {
+		filter["availability"] = availableFilter()
+	}
+	cursor, err := database.GetCollection("clothing").Find(ctx, filter,
+		options.Find().SetProjection(bson.M{"embedding": 0, "availability_history": 0}))
+	if err != nil {
+		return nil, capsule.Analysis{}, err
+	}
+	var items []models.ClothingItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, capsule.Analysis{}, err
+	}
+	return items, capsule.Analyze(items, taxonomy.Occasions, taxonomy.Seasons), nil
+}
+
+// @Summary Analyze the closet as a capsule wardrobe
+// @Description Work out how many outfits (a top and bottom, or a dress, with shoes, that go together and share an occasion) the closet makes, overall and per occasion and season. Lists the key pieces in the most outfits, orphans that go with nothing, under-served occasions, seasons and categories (e.g. no Formal shoes), and the single items that would add the most new outfits.
+// @Tags clothing
+// @Produce json
+// @Security BearerAuth
+// @Param available query bool false "Only count items that are available right now"
+// @Success 200 {object} handlers.ClosetAnalysisResponse
+// @Failure 500 {object} map[string]string "Failed to fetch clothing items"
+// @Router /clothing/analysis [get]
+func GetClosetAnalysisHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	ctx := c.Request.Context()
+	items, analysis, err := analyzeCloset(ctx, userID, c.Query("available") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+
+	byID := map[string]models.ClothingItem{}
+	for _, item := range items {
+		byID[item.ID.Hex()] = item
+	}
+	response := ClosetAnalysisResponse{Analysis: analysis, TotalItems: len(items), Clothing: []SharedItem{}}
+	listed := map[string]bool{}
+	add := func(id string) {
+		if item, ok := byID[id]; ok && !listed[id] {
+			listed[id] = true
+			response.Clothing = append(response.Clothing, sharedItem(item))
+		}
+	}
+	for _, piece := range analysis.KeyPieces {
+		add(piece.ItemID)
+	}
+	for _, id := range analysis.OrphanItemIDs {
+		add(id)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
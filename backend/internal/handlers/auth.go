@@ -2,16 +2,18 @@ package handlers
 
 import (
 	"context"
+	"log"
+	"math"
 	"net/http"
-	"os"
+	"strconv"
 	"time"
 
 	"github.com/exply/armoire/internal/database"
 	"github.com/exply/armoire/internal/models"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -29,7 +31,7 @@ type LoginRequest struct {
 }
 
 // @Summary Register a new user
-// @Description Create a new user account with name, email and password
+// @Description Create a new user account with name, email and password. A verification link is emailed to the address.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -46,6 +48,7 @@ func RegisterHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	req.Email = models.NormalizeEmail(req.Email)
 
 	collection := database.GetCollection("users")
 	ctx := context.Background()
@@ -71,26 +74,39 @@ func RegisterHandler(c *gin.Context) {
 		Email:     req.Email,
 		Password:  string(hashedPassword), // Store the hash!
 		CreatedAt: time.Now(),
+		Role:      models.RoleUser,
 	}
 
 	_, err = collection.InsertOne(ctx, newUser)
+	if mongo.IsDuplicateKeyError(err) {
+		// Registered by a parallel request since the check above
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already registered"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create user"})
 		return
 	}
 
+	// The account works right away; verification can be re-requested if this email is lost
+	if err := sendVerificationEmail(ctx, newUser); err != nil {
+		log.Printf("Verification email failed for user %s: %v", newUser.ID.Hex(), err)
+	}
+
 	if req.ReturnToken {
-		// Generate JWT token
-		token, err := GenerateJWT(newUser.ID.Hex())
+		// Start a session and hand back its tokens
+		tokens, err := startSession(c, newUser.ID.Hex())
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
 			return
 		}
 
 		c.JSON(http.StatusCreated, gin.H{
-			"message": "User created successfully",
-			"token":   token,
-			"user":    newUser,
+			"message":      "User created successfully",
+			"token":        tokens.Token,
+			"refreshToken": tokens.RefreshToken,
+			"expiresIn":    tokens.ExpiresIn,
+			"user":         newUser,
 		})
 		return
 	}
@@ -99,7 +115,7 @@ func RegisterHandler(c *gin.Context) {
 }
 
 // @Summary Log into an account
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return a short-lived JWT access token plus a refresh token
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -107,6 +123,8 @@ func RegisterHandler(c *gin.Context) {
 // @Success 200 {object} map[string]interface{} "Login successful with token and user data"
 // @Failure 400 {object} map[string]string "Invalid request body"
 // @Failure 401 {object} map[string]string "Invalid email or password"
+// @Failure 403 {object} map[string]string "Account disabled"
+// @Failure 429 {object} map[string]string "Too many failed attempts for this email, temporarily locked"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /auth/login [post]
 func LoginHandler(c *gin.Context) {
@@ -115,43 +133,79 @@ func LoginHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 		return
 	}
+	req.Email = models.NormalizeEmail(req.Email)
 
-	collection := database.GetCollection("users")
-	var user models.User
+	ctx := c.Request.Context()
 
-	// Find user by email
-	err := collection.FindOne(context.Background(), bson.M{"email": req.Email}).Decode(&user)
+	// Failures are counted per email, known or not, so the lockout doesn't
+	// tell anyone which addresses have an account
+	failures, err := loginFailures(ctx, req.Email)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		log.Printf("Could not read failed logins: %v", err)
+	}
+
+	// Refuse to even check the password while locked, or the lockout is pointless
+	if wait := lockedFor(failures); wait > 0 {
+		lockedResponse(c, wait)
 		return
 	}
 
-	// Compare sent password with stored hash
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
-	if err != nil {
+	var user models.User
+	err = database.GetCollection("users").FindOne(ctx, bson.M{"email": req.Email}).Decode(&user)
+	if err != nil && err != mongo.ErrNoDocuments {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	// Unknown emails and passwordless accounts still pay for a bcrypt
+	// comparison, so they answer as slowly as a wrong password
+	hasPassword := err == nil && user.Password != ""
+	hash := dummyHash
+	if hasPassword {
+		hash = []byte(user.Password)
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(req.Password)); err != nil || !hasPassword {
+		lockout, err := recordFailedLogin(ctx, req.Email)
+		if err != nil {
+			log.Printf("Could not record failed login: %v", err)
+		}
+		if lockout > 0 {
+			lockedResponse(c, lockout)
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
 		return
 	}
 
-	// Generate JWT (Same function as before)
-	token, err := GenerateJWT(user.ID.Hex())
+	// Only tell a disabled user once they've proven who they are
+	if user.Disabled {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This account has been disabled"})
+		return
+	}
+
+	if err := clearFailedLogins(ctx, failures); err != nil {
+		log.Printf("Could not reset failed logins for %s: %v", user.ID.Hex(), err)
+	}
+
+	// Start a session (short-lived access token + rotating refresh token)
+	tokens, err := startSession(c, user.ID.Hex())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"token": token,
-		"user":  user, // user.Password is hidden by json:"-"
+		"token":        tokens.Token,
+		"refreshToken": tokens.RefreshToken,
+		"expiresIn":    tokens.ExpiresIn,
+		"user":         user, // user.Password is hidden by json:"-"
 	})
 }
 
-// Helper to generate JWT (Reusable)
-func GenerateJWT(userID string) (string, error) {
-	claims := jwt.MapClaims{
-		"sub": userID,
-		"exp": time.Now().Add(time.Hour * 72).Unix(), // 3 days
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+func lockedResponse(c *gin.Context, wait time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":      "Too many failed login attempts, try again later",
+		"retryAfter": int(math.Ceil(wait.Seconds())),
+	})
 }
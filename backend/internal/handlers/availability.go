@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AvailabilityUpdateRequest moves a set of items to a new availability status
+type AvailabilityUpdateRequest struct {
+	ItemIDs []string `json:"itemIds" binding:"required,min=1"`
+	Status  string   `json:"status" binding:"required" example:"in_laundry"`
+	Note    string   `json:"note"` // e.g. "Lent to Sam"
+}
+
+// AvailabilityUpdateResponse reports how many items changed status
+type AvailabilityUpdateResponse struct {
+	Matched  int64 `json:"matched"`
+	Modified int64 `json:"modified"` // Items already in the requested status are left untouched
+}
+
+// availableFilter matches items that can be recommended. Items that predate
+// availability tracking have no status and count as available.
+func availableFilter() bson.M {
+	return bson.M{"$nin": models.UnavailableStatuses}
+}
+
+// @Summary Bulk update item availability
+// @Description Set the availability status (available, in_laundry, at_dry_cleaner, lent, in_storage, needs_repair) of several items at once, recording the transition time
+// @Tags clothing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body handlers.AvailabilityUpdateRequest true "Items and their new status"
+// @Success 200 {object} handlers.AvailabilityUpdateResponse
+// @Failure 400 {string} string "Invalid request body, clothing ID or status"
+// @Failure 500 {string} string "Failed to update availability"
+// @Router /clothing/availability [patch]
+func BulkUpdateAvailabilityHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req AvailabilityUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if !models.IsAvailabilityStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status", "allowed": models.AvailabilityStatuses})
+		return
+	}
+
+	objectIDs := make([]primitive.ObjectID, 0, len(req.ItemIDs))
+	for _, id := range req.ItemIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clothing ID: " + id})
+			return
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	matched, modified, err := setAvailability(c.Request.Context(), userID, objectIDs, req.Status, req.Note)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, AvailabilityUpdateResponse{Matched: matched, Modified: modified})
+}
+
+// setAvailability moves the user's items to status, appending to each item's history
+func setAvailability(ctx context.Context, userID string, itemIDs []primitive.ObjectID, status, note string) (int64, int64, error) {
+	now := time.Now()
+	change := models.AvailabilityChange{Status: status, Note: note, ChangedAt: now}
+
+	collection := database.GetCollection("clothing")
+	filter := bson.M{
+		"_id":          bson.M{"$in": itemIDs},
+		"user_id":      userID,
+		"availability": bson.M{"$ne": status},
+	}
+	// Legacy items without a status are already "available"
+	if status == models.AvailabilityAvailable {
+		filter["availability"] = bson.M{"$in": models.UnavailableStatuses}
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"availability":            status,
+			"availability_changed_at": now,
+			"updated_at":              now,
+		},
+		"$push": bson.M{
+			"availability_history": bson.M{
+				"$each":  []models.AvailabilityChange{change},
+				"$slice": -models.MaxAvailabilityHistory,
+			},
+		},
+	}
+
+	result, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Count every owned item as matched, including ones already in that status
+	matched, err := collection.CountDocuments(ctx, bson.M{"_id": bson.M{"$in": itemIDs}, "user_id": userID})
+	if err != nil {
+		return 0, 0, err
+	}
+	return matched, result.ModifiedCount, nil
+}
@@ -0,0 +1,720 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	borrowDateLayout = "2006-01-02"
+	maxLoanDays      = 90
+)
+
+var (
+	errLoanOverlap     = errors.New("the item is already requested or lent for some of those days")
+	errBorrowNotFound  = errors.New("borrow request not found")
+	errBorrowItemGone  = errors.New("clothing item not found")
+	errBorrowWrongStep = errors.New("borrow request can't be changed from its current status")
+	errItemUnavailable = errors.New("the item isn't available right now")
+)
+
+type CreateBorrowRequest struct {
+	ItemID    string `json:"itemId" binding:"required"`
+	StartDate string `json:"startDate" binding:"required" example:"2026-05-01"` // First day, inclusive
+	EndDate   string `json:"endDate" binding:"required" example:"2026-05-03"`   // Last day, inclusive
+	Message   string `json:"message"`
+}
+
+// BorrowRequestView is a borrow request with the item and both people filled in
+type BorrowRequestView struct {
+	models.BorrowRequest
+	Item     *SharedItem `json:"item,omitempty"` // Missing if the item has been deleted
+	Owner    UserSummary `json:"owner"`
+	Borrower UserSummary `json:"borrower"`
+}
+
+// parseLoanDates checks a requested period and returns its first and last day
+func parseLoanDates(start, end string) (time.Time, time.Time, error) {
+	startDate, err := time.Parse(borrowDateLayout, start)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("startDate must be a YYYY-MM-DD date")
+	}
+	endDate, err := time.Parse(borrowDateLayout, end)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("endDate must be a YYYY-MM-DD date")
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	switch {
+	case startDate.Before(today):
+		return time.Time{}, time.Time{}, errors.New("startDate can't be in the past")
+	case endDate.Before(startDate):
+		return time.Time{}, time.Time{}, errors.New("endDate can't be before startDate")
+	case endDate.Sub(startDate) >= maxLoanDays*24*time.Hour:
+		return time.Time{}, time.Time{}, errors.New("loans can't be longer than 90 days")
+	}
+	return startDate, endDate, nil
+}
+
+// claimLoanItem bumps the item's loan_seq so that two transactions booking the
+// same item write-conflict and one of them is retried against the other's result
+func claimLoanItem(ctx mongo.SessionContext, itemID primitive.ObjectID, ownerID string) error {
+	result, err := database.GetCollection("clothing").UpdateOne(ctx,
+		bson.M{"_id": itemID, "user_id": ownerID},
+		bson.M{"$inc": bson.M{"loan_seq": 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errBorrowItemGone
+	}
+	return nil
+}
+
+// loanOverlaps reports whether another request in statuses holds any day of [start, end]
+func loanOverlaps(ctx mongo.SessionContext, itemID, excludeID primitive.ObjectID, start, end time.Time, statuses []string) (bool, error) {
+	count, err := database.GetCollection("borrow_requests").CountDocuments(ctx, bson.M{
+		"_id":        bson.M{"$ne": excludeID},
+		"item_id":    itemID,
+		"status":     bson.M{"$in": statuses},
+		"start_date": bson.M{"$lte": end},
+		"end_date":   bson.M{"$gte": start},
+	}, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// lendItem moves an available item to lent; the note names the borrower. It
+// returns errItemUnavailable if the owner has the item marked otherwise.
+func lendItem(ctx context.Context, request models.BorrowRequest, now time.Time) error {
+	note := "Lent"
+	if summaries, err := userSummaries(ctx, []string{request.BorrowerID}); err == nil {
+		if borrower, ok := summaries[request.BorrowerID]; ok {
+			note = "Lent to " + borrower.Name
+		}
+	}
+	// Items saved before statuses existed have none and count as available
+	moved, err := moveLoanItem(ctx, request, bson.M{"$nin": models.UnavailableStatuses}, models.AvailabilityLent, note, now)
+	if err == nil && !moved {
+		return errItemUnavailable
+	}
+	return err
+}
+
+// returnItem puts a lent item back to available, unless the owner has since
+// changed its status to something else
+func returnItem(ctx context.Context, request models.BorrowRequest, now time.Time) error {
+	_, err := moveLoanItem(ctx, request, models.AvailabilityLent, models.AvailabilityAvailable, "Returned", now)
+	return err
+}
+
+// moveLoanItem sets the item's availability to status if its current one
+// matches from, and reports whether it did
+func moveLoanItem(ctx context.Context, request models.BorrowRequest, from interface{}, status, note string, now time.Time) (bool, error) {
+	filter := bson.M{"_id": request.ItemID, "user_id": request.OwnerID, "availability": from}
+	change := models.AvailabilityChange{Status: status, Note: note, ChangedAt: now}
+	result, err := database.GetCollection("clothing").UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"availability":            status,
+			"availability_changed_at": now,
+			"updated_at":              now,
+		},
+		"$push": bson.M{
+			"availability_history": bson.M{
+				"$each":  []models.AvailabilityChange{change},
+				"$slice": -models.MaxAvailabilityHistory,
+			},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// startLoan lends the item of a loan the sweep has just made active. If the
+// owner has the item marked unavailable, the loan goes back to approved and
+// is retried on the next sweep; both sides are told the first time. A loan
+// whose period runs out while waiting is cancelled.
+func startLoan(ctx context.Context, request models.BorrowRequest, now time.Time) error {
+	collection := database.GetCollection("borrow_requests")
+	err := lendItem(ctx, request, now)
+	if err == nil && request.StartBlockedAt != nil {
+		_, err = collection.UpdateOne(ctx, bson.M{"_id": request.ID}, bson.M{"$unset": bson.M{"start_blocked_at": ""}})
+	}
+	if !errors.Is(err, errItemUnavailable) {
+		return err
+	}
+
+	if !request.Ends().After(now) {
+		_, err = collection.UpdateOne(ctx,
+			bson.M{"_id": request.ID, "status": models.BorrowActive},
+			bson.M{"$set": bson.M{"status": models.BorrowCancelled, "updated_at": now}, "$unset": bson.M{"lent_at": ""}},
+		)
+		if err == nil {
+			notifyBorrow(request.OwnerID, request.BorrowerID, models.NotifyBorrowCancelled, request, "didn't make %s available during your loan, so it was cancelled")
+			notifyBorrow(request.BorrowerID, request.OwnerID, models.NotifyBorrowCancelled, request, "didn't get to borrow %s: it wasn't available during the loan, so it was cancelled")
+		}
+		return err
+	}
+
+	_, err = collection.UpdateOne(ctx,
+		bson.M{"_id": request.ID, "status": models.BorrowActive},
+		bson.M{"$set": bson.M{"status": models.BorrowApproved, "start_blocked_at": now}, "$unset": bson.M{"lent_at": ""}},
+	)
+	if err == nil && request.StartBlockedAt == nil {
+		notifyBorrow(request.OwnerID, request.BorrowerID, models.NotifyBorrowDelayed, request, "hasn't made %s available yet; your loan starts once they do")
+		notifyBorrow(request.BorrowerID, request.OwnerID, models.NotifyBorrowDelayed, request, "is due to borrow %s; mark it available to start the loan")
+	}
+	return err
+}
+
+// borrowViews fills in items and users for a page of requests
+func borrowViews(ctx context.Context, requests []models.BorrowRequest) ([]BorrowRequestView, error) {
+	var userIDs []string
+	var itemIDs []primitive.ObjectID
+	for _, r := range requests {
+		userIDs = append(userIDs, r.OwnerID, r.BorrowerID)
+		itemIDs = append(itemIDs, r.ItemID)
+	}
+	users, err := userSummaries(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := database.GetCollection("clothing").Find(ctx, bson.M{"_id": bson.M{"$in": itemIDs}},
+		options.Find().SetProjection(bson.M{"embedding": 0}))
+	if err != nil {
+		return nil, err
+	}
+	var items []models.ClothingItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	byID := map[primitive.ObjectID]SharedItem{}
+	for _, item := range items {
+		byID[item.ID] = sharedItem(item)
+	}
+
+	views := make([]BorrowRequestView, len(requests))
+	for i, r := range requests {
+		views[i] = BorrowRequestView{BorrowRequest: r, Owner: users[r.OwnerID], Borrower: users[r.BorrowerID]}
+		if item, ok := byID[r.ItemID]; ok {
+			views[i].Item = &item
+		}
+	}
+	return views, nil
+}
+
+// respondBorrow writes the response for a single request after a change
+func respondBorrow(c *gin.Context, status int, request models.BorrowRequest) {
+	views, err := borrowViews(c.Request.Context(), []models.BorrowRequest{request})
+	if err != nil {
+		c.JSON(status, BorrowRequestView{BorrowRequest: request})
+		return
+	}
+	c.JSON(status, views[0])
+}
+
+// borrowError maps the errors from borrow transactions to responses
+func borrowError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, errLoanOverlap), errors.Is(err, errBorrowWrongStep), errors.Is(err, errItemUnavailable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, errBorrowNotFound), errors.Is(err, errBorrowItemGone):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// @Summary Request to borrow an item
+// @Description Ask to borrow another user's public item for a range of days. Requests that overlap a pending, approved or ongoing loan of the same item are rejected.
+// @Tags borrowing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateBorrowRequest true "Item and dates"
+// @Success 201 {object} handlers.BorrowRequestView
+// @Failure 400 {object} map[string]string "Invalid request body or dates"
+// @Failure 403 {object} map[string]string "Item can't be borrowed"
+// @Failure 404 {object} map[string]string "Clothing item not found"
+// @Failure 409 {object} map[string]string "Dates overlap another loan"
+// @Router /borrow-requests [post]
+func CreateBorrowRequestHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req CreateBorrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	itemID, err := primitive.ObjectIDFromHex(req.ItemID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clothing ID"})
+		return
+	}
+	startDate, endDate, err := parseLoanDates(req.StartDate, req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var item models.ClothingItem
+	err = database.GetCollection("clothing").FindOne(ctx, bson.M{"_id": itemID}).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Clothing item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing item"})
+		return
+	}
+	if item.UserID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can't borrow your own item"})
+		return
+	}
+	if !item.IsPublic || !itemOwnerVisible(ctx, userID, item.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This item can't be borrowed"})
+		return
+	}
+
+	now := time.Now()
+	request := models.BorrowRequest{
+		ID:         primitive.NewObjectID(),
+		ItemID:     itemID,
+		OwnerID:    item.UserID,
+		BorrowerID: userID,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Message:    strings.TrimSpace(req.Message),
+		Status:     models.BorrowPending,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	err = database.WithTransaction(ctx, func(sc mongo.SessionContext) error {
+		if err := claimLoanItem(sc, itemID, item.UserID); err != nil {
+			return err
+		}
+		overlap, err := loanOverlaps(sc, itemID, request.ID, startDate, endDate, models.BorrowBlockingStatuses)
+		if err != nil {
+			return err
+		}
+		if overlap {
+			return errLoanOverlap
+		}
+		_, err = database.GetCollection("borrow_requests").InsertOne(sc, request)
+		return err
+	})
+	if err != nil {
+		borrowError(c, err, "Failed to save borrow request")
+		return
+	}
+	notifyBorrow(userID, request.OwnerID, models.NotifyBorrowRequest, request, "asked to borrow %s")
+
+	respondBorrow(c, http.StatusCreated, request)
+}
+
+// @Summary List borrow requests
+// @Description The authenticated user's borrowing history: requests they made and requests for their items, newest first
+// @Tags borrowing
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "borrower (requests you made) or owner (requests for your items); both if omitted"
+// @Param status query string false "Only requests in this status"
+// @Param itemId query string false "Only requests for this item"
+// @Success 200 {array} handlers.BorrowRequestView
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /borrow-requests [get]
+func ListBorrowRequestsHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var filter bson.M
+	switch c.Query("role") {
+	case "borrower":
+		filter = bson.M{"borrower_id": userID}
+	case "owner":
+		filter = bson.M{"owner_id": userID}
+	case "":
+		filter = bson.M{"$or": []bson.M{{"borrower_id": userID}, {"owner_id": userID}}}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be borrower or owner"})
+		return
+	}
+	if status := c.Query("status"); status != "" {
+		if !isBorrowStatus(status) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+		filter["status"] = status
+	}
+	if itemID := c.Query("itemId"); itemID != "" {
+		objectID, err := primitive.ObjectIDFromHex(itemID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clothing ID"})
+			return
+		}
+		filter["item_id"] = objectID
+	}
+
+	ctx := c.Request.Context()
+	cursor, err := database.GetCollection("borrow_requests").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	requests := []models.BorrowRequest{}
+	if err == nil {
+		err = cursor.All(ctx, &requests)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch borrow requests"})
+		return
+	}
+
+	views, err := borrowViews(ctx, requests)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch borrow requests"})
+		return
+	}
+	c.JSON(http.StatusOK, views)
+}
+
+// @Summary Get a borrow request
+// @Tags borrowing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Borrow request ID"
+// @Success 200 {object} handlers.BorrowRequestView
+// @Failure 400 {object} map[string]string "Invalid borrow request ID"
+// @Failure 404 {object} map[string]string "Borrow request not found"
+// @Router /borrow-requests/{id} [get]
+func GetBorrowRequestHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid borrow request ID"})
+		return
+	}
+
+	var request models.BorrowRequest
+	err = database.GetCollection("borrow_requests").FindOne(c.Request.Context(), bson.M{
+		"_id": objectID,
+		"$or": []bson.M{{"borrower_id": userID}, {"owner_id": userID}},
+	}).Decode(&request)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Borrow request not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch borrow request"})
+		return
+	}
+
+	respondBorrow(c, http.StatusOK, request)
+}
+
+// transitionBorrow moves a request the user is party to (as field) from one of
+// the from statuses to next
+func transitionBorrow(c *gin.Context, field string, from []string, next string) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid borrow request ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	collection := database.GetCollection("borrow_requests")
+	now := time.Now()
+	set := bson.M{"status": next, "updated_at": now}
+	switch next {
+	case models.BorrowDeclined:
+		set["responded_at"] = now
+	case models.BorrowReturned:
+		set["returned_at"] = now
+	}
+
+	var request models.BorrowRequest
+	err = collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, field: userID, "status": bson.M{"$in": from}},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&request)
+	if err == mongo.ErrNoDocuments {
+		// Tell "not yours" apart from "wrong status"
+		count, _ := collection.CountDocuments(ctx, bson.M{"_id": objectID, field: userID})
+		if count > 0 {
+			borrowError(c, errBorrowWrongStep, "")
+		} else {
+			borrowError(c, errBorrowNotFound, "")
+		}
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update borrow request"})
+		return
+	}
+
+	switch next {
+	case models.BorrowReturned:
+		if err := returnItem(ctx, request, now); err != nil {
+			log.Printf("Could not mark item %s available after loan %s: %v", request.ItemID.Hex(), request.ID.Hex(), err)
+		}
+		notifyBorrow(userID, request.BorrowerID, models.NotifyBorrowReturned, request, "marked %s as returned")
+	case models.BorrowDeclined:
+		notifyBorrow(userID, request.BorrowerID, models.NotifyBorrowDeclined, request, "can't lend you %s")
+	case models.BorrowCancelled:
+		notifyBorrow(userID, request.OwnerID, models.NotifyBorrowCancelled, request, "no longer needs to borrow %s")
+	}
+
+	respondBorrow(c, http.StatusOK, request)
+}
+
+// @Summary Approve a borrow request
+// @Description Approve a pending request for one of your items. If the loan period has started the item is marked lent straight away; otherwise it is marked lent when the period starts.
+// @Tags borrowing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Borrow request ID"
+// @Success 200 {object} handlers.BorrowRequestView
+// @Failure 404 {object} map[string]string "Borrow request not found"
+// @Failure 409 {object} map[string]string "Not pending, dates overlap an approved loan, or the period has started and the item isn't available"
+// @Router /borrow-requests/{id}/approve [post]
+func ApproveBorrowRequestHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid borrow request ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	collection := database.GetCollection("borrow_requests")
+	var request models.BorrowRequest
+	err = database.WithTransaction(ctx, func(sc mongo.SessionContext) error {
+		request = models.BorrowRequest{} // Start clean if the transaction is retried
+		err := collection.FindOne(sc, bson.M{"_id": objectID, "owner_id": userID}).Decode(&request)
+		if err == mongo.ErrNoDocuments {
+			return errBorrowNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if request.Status != models.BorrowPending {
+			return errBorrowWrongStep
+		}
+		if err := claimLoanItem(sc, request.ItemID, userID); err != nil {
+			return err
+		}
+		overlap, err := loanOverlaps(sc, request.ItemID, request.ID, request.StartDate, request.EndDate,
+			[]string{models.BorrowApproved, models.BorrowActive})
+		if err != nil {
+			return err
+		}
+		if overlap {
+			return errLoanOverlap
+		}
+
+		now := time.Now()
+		request.Status = models.BorrowApproved
+		request.RespondedAt = &now
+		request.UpdatedAt = now
+		set := bson.M{"status": request.Status, "responded_at": now, "updated_at": now}
+		if !request.StartDate.After(now) {
+			request.Status = models.BorrowActive
+			request.LentAt = &now
+			set["status"] = request.Status
+			set["lent_at"] = now
+			if err := lendItem(sc, request, now); err != nil {
+				return err
+			}
+		}
+		_, err = collection.UpdateOne(sc, bson.M{"_id": objectID}, bson.M{"$set": set})
+		return err
+	})
+	if err != nil {
+		borrowError(c, err, "Failed to approve borrow request")
+		return
+	}
+	notifyBorrow(userID, request.BorrowerID, models.NotifyBorrowApproved, request, "agreed to lend you %s")
+
+	respondBorrow(c, http.StatusOK, request)
+}
+
+// @Summary Decline a borrow request
+// @Tags borrowing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Borrow request ID"
+// @Success 200 {object} handlers.BorrowRequestView
+// @Failure 404 {object} map[string]string "Borrow request not found"
+// @Failure 409 {object} map[string]string "Not pending"
+// @Router /borrow-requests/{id}/decline [post]
+func DeclineBorrowRequestHandler(c *gin.Context) {
+	transitionBorrow(c, "owner_id", []string{models.BorrowPending}, models.BorrowDeclined)
+}
+
+// @Summary Cancel a borrow request
+// @Description Withdraw a request you made, as long as the loan hasn't started
+// @Tags borrowing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Borrow request ID"
+// @Success 200 {object} handlers.BorrowRequestView
+// @Failure 404 {object} map[string]string "Borrow request not found"
+// @Failure 409 {object} map[string]string "Loan already started or finished"
+// @Router /borrow-requests/{id}/cancel [post]
+func CancelBorrowRequestHandler(c *gin.Context) {
+	transitionBorrow(c, "borrower_id", []string{models.BorrowPending, models.BorrowApproved}, models.BorrowCancelled)
+}
+
+// @Summary Mark a loan returned
+// @Description The owner marks a lent item as back, ending the loan early. The item becomes available again.
+// @Tags borrowing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Borrow request ID"
+// @Success 200 {object} handlers.BorrowRequestView
+// @Failure 404 {object} map[string]string "Borrow request not found"
+// @Failure 409 {object} map[string]string "Item isn't lent out"
+// @Router /borrow-requests/{id}/return [post]
+func ReturnBorrowedItemHandler(c *gin.Context) {
+	transitionBorrow(c, "owner_id", []string{models.BorrowActive}, models.BorrowReturned)
+}
+
+// notifyBorrow tells the other side of a borrow request about a change; format gets the item's name
+func notifyBorrow(actorID, recipientID, kind string, request models.BorrowRequest, format string) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	notifyFrom(actorID, models.Notification{
+		UserID:     recipientID,
+		Type:       kind,
+		TargetType: "borrow_request",
+		TargetID:   request.ID.Hex(),
+	}, format, targetName(ctx, itemTarget, request.ItemID))
+}
+
+// cancelItemLoans cancels open requests for an item that is being deleted
+func cancelItemLoans(ctx context.Context, itemID primitive.ObjectID) {
+	now := time.Now()
+	_, err := database.GetCollection("borrow_requests").UpdateMany(ctx,
+		bson.M{"item_id": itemID, "status": bson.M{"$in": models.BorrowBlockingStatuses}},
+		bson.M{"$set": bson.M{"status": models.BorrowCancelled, "updated_at": now}},
+	)
+	if err != nil {
+		log.Printf("Could not cancel borrow requests for item %s: %v", itemID.Hex(), err)
+	}
+}
+
+// SweepLoans starts approved loans whose period has begun and returns active
+// loans whose period is over, updating the items' availability. Loans whose
+// item isn't available at the start wait for it, see startLoan.
+func SweepLoans(ctx context.Context) error {
+	collection := database.GetCollection("borrow_requests")
+	now := time.Now()
+
+	steps := []struct {
+		filter bson.M
+		set    bson.M
+		item   func(context.Context, models.BorrowRequest, time.Time) error
+	}{
+		{
+			// Loans put back by startLoan during this sweep wait for the next one
+			bson.M{"status": models.BorrowApproved, "start_date": bson.M{"$lte": now}, "$or": []bson.M{
+				{"start_blocked_at": nil},
+				{"start_blocked_at": bson.M{"$lt": now}},
+			}},
+			bson.M{"status": models.BorrowActive, "lent_at": now, "updated_at": now},
+			startLoan,
+		},
+		{
+			bson.M{"status": models.BorrowActive, "end_date": bson.M{"$lte": now.AddDate(0, 0, -1)}},
+			bson.M{"status": models.BorrowReturned, "returned_at": now, "updated_at": now},
+			returnItem,
+		},
+	}
+
+	var errs []error
+	for _, step := range steps {
+		// Claim one request at a time so concurrent sweepers never process the same loan twice
+		for {
+			var request models.BorrowRequest
+			err := collection.FindOneAndUpdate(ctx, step.filter, bson.M{"$set": step.set}).Decode(&request)
+			if err == mongo.ErrNoDocuments {
+				break
+			}
+			if err != nil {
+				errs = append(errs, err)
+				break
+			}
+			if err := step.item(ctx, request, now); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunLoanSweeper calls SweepLoans every interval until ctx is done
+func RunLoanSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := SweepLoans(ctx); err != nil {
+			log.Printf("Loan sweep failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isBorrowStatus reports whether status is a known borrow request status
+func isBorrowStatus(status string) bool {
+	return slices.Contains([]string{
+		models.BorrowPending, models.BorrowApproved, models.BorrowActive,
+		models.BorrowReturned, models.BorrowDeclined, models.BorrowCancelled,
+	}, status)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/exply/armoire/internal/ai"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/storage"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// careLabel is a stored care label photo together with what was read off it
+type careLabel struct {
+	URL    string
+	GCSURI string
+	Care   *models.CareInstructions
+}
+
+// processCareLabel uploads a care label photo next to the item's image and reads it with Gemini
+func processCareLabel(ctx context.Context, fileHeader *multipart.FileHeader, baseName string, gcsClient *storage.StorageClient, aiClient *ai.AIClient) (*careLabel, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	labelBytes, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := baseName + "_care" + filepath.Ext(fileHeader.Filename)
+	gcsURI, err := gcsClient.UploadFile(bytes.NewReader(labelBytes), filename)
+	if err != nil {
+		return nil, err
+	}
+
+	care, err := aiClient.AnalyzeCareLabel(ctx, bytes.NewReader(labelBytes), http.DetectContentType(labelBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	return &careLabel{
+		URL:    "https://storage.googleapis.com/armoire-bucket/" + filename,
+		GCSURI: gcsURI,
+		Care:   care,
+	}, nil
+}
+
+// @Summary Upload a care label photo
+// @Description Attach a photo of an item's care label, extracting fiber composition and care symbols with AI. Replaces any previous care label.
+// @Tags clothing
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clothing item ID"
+// @Param care_label formData file true "Care label photo"
+// @Success 200 {object} models.ClothingItem
+// @Failure 400 {string} string "Invalid clothing ID or file"
+// @Failure 404 {string} string "Clothing item not found"
+// @Failure 500 {string} string "Failed to process care label"
+// @Router /clothing/{id}/care-label [post]
+func UploadCareLabelHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clothing ID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("care_label")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file"})
+		return
+	}
+
+	collection := database.GetCollection("clothing")
+	ctx := c.Request.Context()
+	filter := bson.M{"_id": objectID, "user_id": userID}
+
+	var item models.ClothingItem
+	err = collection.FindOne(ctx, filter).Decode(&item)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Clothing item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing item"})
+		return
+	}
+
+	gcsClient, _ := storage.NewStorageClient("armoire-bucket")
+	aiClient, _ := ai.NewAIClient(ctx)
+
+	// A fresh suffix keeps CDN caches from serving the previous label
+	label, err := processCareLabel(ctx, fileHeader, item.ID.Hex()+"_"+primitive.NewObjectID().Hex(), gcsClient, aiClient)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process care label: " + err.Error()})
+		return
+	}
+
+	update := bson.M{"$set": bson.M{
+		"care_label_url":     label.URL,
+		"care_label_gcs_uri": label.GCSURI,
+		"care":               label.Care,
+		"updated_at":         time.Now(),
+	}}
+
+	var updatedItem models.ClothingItem
+	err = collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&updatedItem)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save care label"})
+		return
+	}
+
+	// Clean up the label this one replaced
+	if item.CareLabelGCSURI != "" {
+		if err := gcsClient.DeleteFile(item.CareLabelGCSURI); err != nil {
+			fmt.Printf("Warning: Failed to delete old care label from GCS: %v\n", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, updatedItem)
+}
@@ -1,18 +1,16 @@
 package handlers
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"net/http"
-	"path/filepath"
-	"strings"
+	"regexp"
 	"time"
 
 	"github.com/exply/armoire/internal/ai"
 	"github.com/exply/armoire/internal/database"
-	"github.com/exply/armoire/internal/imageproc"
 	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/notify"
 	"github.com/exply/armoire/internal/storage"
 	"github.com/exply/armoire/internal/taxonomy"
 	"github.com/gin-gonic/gin"
@@ -27,6 +25,43 @@ type SearchRequest struct {
 	AISearch   bool     `json:"aiSearch"`   // Toggle between Regex match vs Vector Match
 	Categories []string `json:"categories"` // Hard filter
 	Colors     []string `json:"colors"`     // Hard filter
+
+	// Garment attribute hard filters
+	Materials     []string `json:"materials"`
+	Patterns      []string `json:"patterns"`
+	Fits          []string `json:"fits"`
+	Necklines     []string `json:"necklines"`
+	SleeveLengths []string `json:"sleeveLengths"`
+	Brand         string   `json:"brand"` // Partial match on the brand text
+
+	// Items in the laundry, lent out, etc. are hidden unless this is set
+	IncludeUnavailable bool `json:"includeUnavailable"`
+
+	// Only return drafts from receipts that are still waiting for a photo
+	Drafts bool `json:"drafts"`
+}
+
+// applyHardFilters adds the request's exact-match filters to a Mongo filter
+func (req SearchRequest) applyHardFilters(filter bson.M) {
+	for field, values := range map[string][]string{
+		"category":      req.Categories,
+		"colors":        req.Colors,
+		"materials":     req.Materials,
+		"pattern":       req.Patterns,
+		"fit":           req.Fits,
+		"neckline":      req.Necklines,
+		"sleeve_length": req.SleeveLengths,
+	} {
+		if len(values) > 0 {
+			filter[field] = bson.M{"$in": values}
+		}
+	}
+	if !req.IncludeUnavailable {
+		filter["availability"] = availableFilter()
+	}
+	if req.Drafts {
+		filter["draft"] = true
+	}
 }
 
 // @Summary Search clothing items
@@ -66,15 +101,9 @@ func SearchClothingHandler(c *gin.Context) {
 		}
 
 		// 2. Build Filter for Vector Search
-		// Note: fields must be indexed as "filter" in Atlas
+		// Note: fields (including "availability") must be indexed as "filter" in Atlas
 		filter := bson.M{"user_id": userID}
-
-		if len(req.Categories) > 0 {
-			filter["category"] = bson.M{"$in": req.Categories}
-		}
-		if len(req.Colors) > 0 {
-			filter["colors"] = bson.M{"$in": req.Colors}
-		}
+		req.applyHardFilters(filter)
 
 		// 3. Build Aggregation Pipeline
 		pipeline := mongo.Pipeline{
@@ -90,6 +119,13 @@ func SearchClothingHandler(c *gin.Context) {
 			{{Key: "$project", Value: bson.D{{Key: "embedding", Value: 0}}}},
 		}
 
+		// $vectorSearch filters only support exact matches, so the brand is matched afterwards
+		if req.Brand != "" {
+			pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{
+				"brand_text": bson.M{"$regex": primitive.Regex{Pattern: regexp.QuoteMeta(req.Brand), Options: "i"}},
+			}}})
+		}
+
 		collection.Aggregate(ctx, pipeline)
 		cursor, err := collection.Aggregate(ctx, pipeline)
 		if err != nil {
@@ -111,11 +147,11 @@ func SearchClothingHandler(c *gin.Context) {
 		}
 
 		// Exact Filters
-		if len(req.Categories) > 0 {
-			filter["category"] = bson.M{"$in": req.Categories}
-		}
-		if len(req.Colors) > 0 {
-			filter["colors"] = bson.M{"$in": req.Colors}
+		req.applyHardFilters(filter)
+
+		// Brand is free text read off the label, so match it loosely
+		if req.Brand != "" {
+			filter["brand_text"] = bson.M{"$regex": primitive.Regex{Pattern: regexp.QuoteMeta(req.Brand), Options: "i"}}
 		}
 
 		cursor, err := collection.Find(ctx, filter)
@@ -144,6 +180,7 @@ func SearchClothingHandler(c *gin.Context) {
 // @Produce json
 // @Security     BearerAuth
 // @Param image formData file true "Clothing item image (max 10MB)"
+// @Param care_label formData file false "Optional photo of the care label"
 // @Success 200 {object} models.ClothingItem "Successfully uploaded and processed clothing item"
 // @Failure 400 {string} string "Invalid file"
 // @Failure 500 {string} string "Failed to upload to GCS / AI Analysis Failed / Vector Embedding Failed / Database Save Failed"
@@ -179,87 +216,31 @@ func UploadClothingHandler(c *gin.Context) {
 		return
 	}
 
-	finalBytes := originalBytes
-	finalMimeType := http.DetectContentType(originalBytes)
-	baseName := primitive.NewObjectID().Hex()
-	finalFilename := baseName + filepath.Ext(fileHeader.Filename)
-
-	processedBytes, err := ai.RemoveBackground(originalBytes, fileHeader.Filename)
-
-	if err == nil {
-		fmt.Println("Background removed successfully!")
-		finalBytes = processedBytes
-		finalMimeType = "image/png"
-		finalFilename = baseName + ".png" // Force extension to .png
-	} else {
-		fmt.Println("Background removal failed (using original):", err)
-	}
-
 	gcsClient, _ := storage.NewStorageClient("armoire-bucket")
+	aiClient, _ := ai.NewAIClient(c.Request.Context()) // Initialize AI Client
 
-	// Pass finalBytes to GCS
-	gcsURI, err := gcsClient.UploadFile(bytes.NewReader(finalBytes), finalFilename)
+	// 2. Remove the background, store, tag and embed
+	image, err := processImage(c.Request.Context(), gcsClient, aiClient, originalBytes, fileHeader.Filename)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload to GCS"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	publicURL := "https://storage.googleapis.com/armoire-bucket/" + finalFilename
-
-	thumbBytes, err := imageproc.CreateThumbnail(finalBytes, 300)
-	var thumbURL string
-
-	if err == nil {
-		// 3. Create a filename like "abc12345_thumb.png"
-		// This splits the extension to insert "_thumb"
-		ext := filepath.Ext(finalFilename)
-		nameWithoutExt := strings.TrimSuffix(finalFilename, ext)
-		thumbFilename := nameWithoutExt + "_thumb" + ext
 
-		// 4. Upload Thumbnail to GCS
-		_, err := gcsClient.UploadFile(bytes.NewReader(thumbBytes), thumbFilename)
-		if err == nil {
-			thumbURL = "https://storage.googleapis.com/armoire-bucket/" + thumbFilename
+	// Read the optional care label photo; a bad label shouldn't sink the whole upload
+	var label *careLabel
+	if careHeader, err := c.FormFile("care_label"); err == nil {
+		label, err = processCareLabel(c.Request.Context(), careHeader, image.BaseName, gcsClient, aiClient)
+		if err != nil {
+			fmt.Println("Care label processing failed (skipping):", err)
 		}
-	} else {
-		// Fallback: If thumbnail gen fails, just use the full URL so the app doesn't break
-		thumbURL = publicURL
 	}
 
-	// 3. Analyze with Gemini (Auto-Tagging)
-	aiClient, _ := ai.NewAIClient(c.Request.Context()) // Initialize AI Client
-
-	analysis, err := aiClient.AnalyzeImage(c.Request.Context(), bytes.NewReader(finalBytes), finalMimeType)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "AI Analysis Failed: " + err.Error()})
-		return
-	}
-
-	// 4. Generate Vector Embedding for "Vibe Search"
-	// We embed the description Gemini just wrote for us
-	vector, err := aiClient.GetEmbedding(c.Request.Context(), analysis.Description)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Vector Embedding Failed"})
-		return
-	}
-
-	// 5. Save to MongoDB
-	newItem := models.ClothingItem{
-		ID:           primitive.NewObjectID(),
-		UserID:       userID,
-		ImageURL:     publicURL,
-		GCSURI:       gcsURI,
-		ThumbnailURL: thumbURL,
-		Name:         analysis.Name,
-		Category:     analysis.Category,
-		SubCategory:  analysis.SubCategory,
-		Description:  analysis.Description,
-		Colors:       analysis.Colors,
-		Seasons:      analysis.Seasons,
-		Occasions:    analysis.Occasions,
-		Embedding:    vector,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
-		IsPublic:     false,
+	// 3. Save to MongoDB
+	newItem := newClothingItem(userID, image)
+	if label != nil {
+		newItem.CareLabelURL = label.URL
+		newItem.CareLabelGCSURI = label.GCSURI
+		newItem.Care = label.Care
 	}
 
 	collection := database.GetCollection("clothing")
@@ -269,7 +250,16 @@ func UploadClothingHandler(c *gin.Context) {
 		return
 	}
 
-	// 6. Return Success Response
+	// Other open tabs and devices learn about the new item without polling
+	notify.Send(c.Request.Context(), models.Notification{
+		UserID:     userID,
+		Type:       models.NotifyUploadComplete,
+		TargetType: models.ShareItem,
+		TargetID:   newItem.ID.Hex(),
+		Text:       newItem.Name + " was added to your closet",
+	})
+
+	// 4. Return Success Response
 	c.JSON(http.StatusOK, newItem)
 }
 
@@ -318,10 +308,13 @@ func GetClothingByIDHandler(c *gin.Context) {
 		return
 	}
 
-	// Only return the item if the user is the owner or if it's public
-	if item.UserID != userID && !item.IsPublic {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this item"})
-		return
+	// Only return the item if the user is the owner, or if it's public and
+	// the owner's profile visibility lets this user see it
+	if item.UserID != userID {
+		if !item.IsPublic || !itemOwnerVisible(ctx, userID, item.UserID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this item"})
+			return
+		}
 	}
 
 	c.JSON(http.StatusOK, item)
@@ -336,7 +329,7 @@ func GetClothingByIDHandler(c *gin.Context) {
 // @Param id path string true "Clothing item ID"
 // @Param clothing body models.ClothingItem true "Updated clothing item data"
 // @Success 200 {object} models.ClothingItem
-// @Failure 400 {string} string "Invalid clothing ID or request body"
+// @Failure 400 {object} map[string]interface{} "Invalid clothing ID, request body or taxonomy values"
 // @Failure 404 {string} string "Clothing item not found"
 // @Failure 500 {string} string "Failed to update clothing item"
 // @Router /clothing/{id} [patch]
@@ -366,31 +359,37 @@ func UpdateClothingHandler(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	// Build update document with only the fields that are present in the request
-	updateFields := bson.M{}
+	updateFields, fieldErrs := parseClothingUpdate(rawData)
+
+	// Category and sub-category must stay consistent, even when only one of them changes
+	_, hasCategory := updateFields["category"]
+	_, hasSubCategory := updateFields["sub_category"]
+	if len(fieldErrs) == 0 && hasCategory != hasSubCategory {
+		var current models.ClothingItem
+		err = collection.FindOne(ctx, bson.M{"_id": objectID, "user_id": userID}).Decode(&current)
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Clothing item not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing item"})
+			return
+		}
 
-	if name, ok := rawData["name"]; ok {
-		updateFields["name"] = name
-	}
-	if category, ok := rawData["category"]; ok {
-		updateFields["category"] = category
-	}
-	if subCategory, ok := rawData["sub_category"]; ok {
-		updateFields["sub_category"] = subCategory
-	}
-	if description, ok := rawData["description"]; ok {
-		updateFields["description"] = description
-	}
-	if colors, ok := rawData["colors"]; ok {
-		updateFields["colors"] = colors
-	}
-	if seasons, ok := rawData["seasons"]; ok {
-		updateFields["seasons"] = seasons
-	}
-	if occasions, ok := rawData["occasions"]; ok {
-		updateFields["occasions"] = occasions
+		category, subCategory := current.Category, current.SubCategory
+		if hasCategory {
+			category = updateFields["category"].(string)
+		} else {
+			subCategory = updateFields["sub_category"].(string)
+		}
+		if fieldErr := taxonomy.CheckSubCategory(category, subCategory); fieldErr != nil {
+			fieldErrs = append(fieldErrs, *fieldErr)
+		}
 	}
-	if isPublic, ok := rawData["is_public"]; ok {
-		updateFields["is_public"] = isPublic
+
+	if len(fieldErrs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clothing update", "fields": fieldErrs})
+		return
 	}
 
 	// Always update the timestamp
@@ -489,6 +488,10 @@ func DeleteClothingHandler(c *gin.Context) {
 		return
 	}
 
+	revokeShareLinks(ctx, models.ShareItem, objectID)
+	cancelItemLoans(ctx, objectID)
+	deleteEngagement(ctx, models.ShareItem, objectID)
+
 	// Delete image from GCS
 	if item.GCSURI != "" {
 		gcsClient, _ := storage.NewStorageClient("armoire-bucket")
@@ -498,6 +501,12 @@ func DeleteClothingHandler(c *gin.Context) {
 			fmt.Printf("Warning: Failed to delete image from GCS: %v\n", err)
 		}
 	}
+	if item.CareLabelGCSURI != "" {
+		gcsClient, _ := storage.NewStorageClient("armoire-bucket")
+		if err := gcsClient.DeleteFile(item.CareLabelGCSURI); err != nil {
+			fmt.Printf("Warning: Failed to delete care label from GCS: %v\n", err)
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Clothing item deleted successfully"})
 }
@@ -507,10 +516,14 @@ type UserStatsResponse struct {
 	TotalItems     int            `json:"totalItems"`
 	ColorCounts    map[string]int `json:"colorCounts"`
 	CategoryCounts map[string]int `json:"categoryCounts"`
+
+	// Sub-category counts grouped by their parent category
+	SubCategoryCounts map[string]map[string]int `json:"subCategoryCounts"`
+	TaxonomyVersion   string                    `json:"taxonomyVersion"`
 }
 
 // @Summary Get user clothing statistics
-// @Description Get statistics about a user's clothing collection including total count, color distribution, and category distribution
+// @Description Get statistics about a user's clothing collection including total count, color distribution, category distribution and sub-category breakdown within each category
 // @Tags clothing
 // @Produce json
 // @Security BearerAuth
@@ -549,8 +562,13 @@ func GetUserStatsHandler(c *gin.Context) {
 	}
 
 	categoryCounts := make(map[string]int)
+	subCategoryCounts := make(map[string]map[string]int)
 	for _, category := range taxonomy.Categories {
 		categoryCounts[category] = 0
+		subCategoryCounts[category] = make(map[string]int)
+		for _, subCategory := range taxonomy.SubCategoriesByCategory[category] {
+			subCategoryCounts[category][subCategory] = 0
+		}
 	}
 
 	// Count occurrences
@@ -566,19 +584,27 @@ func GetUserStatsHandler(c *gin.Context) {
 		if _, exists := categoryCounts[item.Category]; exists {
 			categoryCounts[item.Category]++
 		}
+
+		// Count sub-category within its category
+		if _, exists := subCategoryCounts[item.Category][item.SubCategory]; exists {
+			subCategoryCounts[item.Category][item.SubCategory]++
+		}
 	}
 
 	response := UserStatsResponse{
 		TotalItems:     len(items),
 		ColorCounts:    colorCounts,
 		CategoryCounts: categoryCounts,
+
+		SubCategoryCounts: subCategoryCounts,
+		TaxonomyVersion:   taxonomy.Current().Version,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 // @Summary Get clothing item owner name
-// @Description Get the name of the owner of a specific clothing item by its ID
+// @Description Get the name of the owner of a specific clothing item by its ID. The owner's user ID, e.g. to link to their profile, is only included for public items.
 // @Tags clothing
 // @Produce json
 // @Param id path string true "Clothing item ID"
@@ -629,5 +655,10 @@ func GetClothingOwnerNameHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"ownerName": user.Name})
+	// Anyone can ask, so only public items lead back to the owner's profile
+	response := gin.H{"ownerName": user.Name}
+	if item.IsPublic {
+		response["ownerId"] = user.ID.Hex()
+	}
+	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/exply/armoire/internal/taxonomy"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	maxNameLength  = 100
+	maxLabelLength = 60
+)
+
+// updateKeys maps every accepted request key onto its document field.
+// Both the bson names and the JSON names of models.ClothingItem are accepted.
+var updateKeys = map[string]string{
+	"name":         "name",
+	"description":  "description",
+	"category":     "category",
+	"sub_category": "sub_category",
+	"subCategory":  "sub_category",
+	"colors":       "colors",
+	"seasons":      "seasons",
+	"occasions":    "occasions",
+	"is_public":    "is_public",
+	"isPublic":     "is_public",
+	"needs_review": "needs_review",
+	"needsReview":  "needs_review",
+
+	"materials":     "materials",
+	"pattern":       "pattern",
+	"fit":           "fit",
+	"neckline":      "neckline",
+	"sleeve_length": "sleeve_length",
+	"sleeveLength":  "sleeve_length",
+	"brand_text":    "brand_text",
+	"brandText":     "brand_text",
+	"size_label":    "size_label",
+	"sizeLabel":     "size_label",
+}
+
+// attributeFields maps single-valued attribute fields onto their taxonomy attribute key
+var attributeFields = map[string]string{
+	"pattern":       taxonomy.AttrPattern,
+	"fit":           taxonomy.AttrFit,
+	"neckline":      taxonomy.AttrNeckline,
+	"sleeve_length": taxonomy.AttrSleeveLength,
+}
+
+// parseClothingUpdate validates a PATCH body against the taxonomy and returns
+// the normalized fields to $set. Keys that are not editable are ignored.
+func parseClothingUpdate(rawData map[string]interface{}) (bson.M, taxonomy.ValidationErrors) {
+	updateFields := bson.M{}
+	var errs taxonomy.ValidationErrors
+
+	// Walk keys in a stable order so field errors are reported deterministically
+	keys := make([]string, 0, len(rawData))
+	for key := range rawData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := rawData[key]
+		field, ok := updateKeys[key]
+		if !ok {
+			continue
+		}
+
+		switch field {
+		case "name", "description":
+			str, ok := value.(string)
+			if !ok {
+				errs = append(errs, taxonomy.FieldError{Field: field, Message: "must be a string"})
+				continue
+			}
+			str = strings.TrimSpace(str)
+			if field == "name" && (str == "" || len(str) > maxNameLength) {
+				errs = append(errs, taxonomy.FieldError{Field: field, Value: str, Message: "must be between 1 and 100 characters"})
+				continue
+			}
+			updateFields[field] = str
+
+		case "category", "sub_category":
+			allowed := taxonomy.Categories
+			if field == "sub_category" {
+				allowed = taxonomy.SubCategories
+			}
+			str, ok := value.(string)
+			if !ok {
+				errs = append(errs, taxonomy.FieldError{Field: field, Message: "must be a string"})
+				continue
+			}
+			canonical, ok := taxonomy.Normalize(str, allowed)
+			if !ok {
+				errs = append(errs, taxonomy.FieldError{Field: field, Value: str, Message: "unknown value"})
+				continue
+			}
+			updateFields[field] = canonical
+
+		case "colors", "seasons", "occasions", "materials":
+			allowed := map[string][]string{
+				"colors":    taxonomy.Colors,
+				"seasons":   taxonomy.Seasons,
+				"occasions": taxonomy.Occasions,
+				"materials": taxonomy.AttributeValues[taxonomy.AttrMaterial],
+			}[field]
+			values, ok := toStringSlice(value)
+			if !ok {
+				errs = append(errs, taxonomy.FieldError{Field: field, Message: "must be a list of strings"})
+				continue
+			}
+			normalized, listErrs := taxonomy.NormalizeList(field, values, allowed)
+			if len(listErrs) > 0 {
+				errs = append(errs, listErrs...)
+				continue
+			}
+			updateFields[field] = normalized
+
+		case "pattern", "fit", "neckline", "sleeve_length":
+			str, ok := value.(string)
+			if !ok {
+				errs = append(errs, taxonomy.FieldError{Field: field, Message: "must be a string"})
+				continue
+			}
+			// An empty value clears the attribute
+			if strings.TrimSpace(str) == "" {
+				updateFields[field] = ""
+				continue
+			}
+			canonical, ok := taxonomy.Normalize(str, taxonomy.AttributeValues[attributeFields[field]])
+			if !ok {
+				errs = append(errs, taxonomy.FieldError{Field: field, Value: str, Message: "unknown value"})
+				continue
+			}
+			updateFields[field] = canonical
+
+		case "brand_text", "size_label":
+			str, ok := value.(string)
+			if !ok {
+				errs = append(errs, taxonomy.FieldError{Field: field, Message: "must be a string"})
+				continue
+			}
+			str = strings.TrimSpace(str)
+			if len([]rune(str)) > maxLabelLength {
+				errs = append(errs, taxonomy.FieldError{Field: field, Value: str, Message: "must be at most 60 characters"})
+				continue
+			}
+			updateFields[field] = str
+
+		case "is_public", "needs_review":
+			flag, ok := value.(bool)
+			if !ok {
+				errs = append(errs, taxonomy.FieldError{Field: field, Message: "must be a boolean"})
+				continue
+			}
+			updateFields[field] = flag
+		}
+	}
+
+	if category, ok := updateFields["category"].(string); ok {
+		if subCategory, ok := updateFields["sub_category"].(string); ok {
+			if fieldErr := taxonomy.CheckSubCategory(category, subCategory); fieldErr != nil {
+				errs = append(errs, *fieldErr)
+			}
+		}
+	}
+
+	return updateFields, errs
+}
+
+// toStringSlice converts a decoded JSON array into a []string
+func toStringSlice(value interface{}) ([]string, bool) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		result = append(result, str)
+	}
+	return result, true
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CreateCollectionRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	ItemIDs     []string `json:"itemIds"`
+}
+
+// UpdateCollectionRequest changes only the fields that are sent
+type UpdateCollectionRequest struct {
+	Name        *string  `json:"name"`
+	Description *string  `json:"description"`
+	ItemIDs     []string `json:"itemIds"`
+}
+
+// @Summary Create a collection
+// @Description Group some of the user's clothing items under a name, e.g. a capsule or a trip
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateCollectionRequest true "Collection"
+// @Success 201 {object} models.Collection
+// @Failure 400 {object} map[string]string "Invalid request body or items"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /collections [post]
+func CreateCollectionHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	itemIDs, err := ownedItemIDs(ctx, userID, req.ItemIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	collection := models.Collection{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		ItemIDs:     itemIDs,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := database.GetCollection("collections").InsertOne(ctx, collection); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save collection"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
+
+// @Summary List collections
+// @Description Get all of the user's collections, newest first
+// @Tags collections
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Collection
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /collections [get]
+func ListCollectionsHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	ctx := c.Request.Context()
+	cursor, err := database.GetCollection("collections").Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	collections := []models.Collection{}
+	if err == nil {
+		err = cursor.All(ctx, &collections)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collections"})
+		return
+	}
+
+	c.JSON(http.StatusOK, collections)
+}
+
+// @Summary Get a collection
+// @Description Get one of the user's collections by ID
+// @Tags collections
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Collection ID"
+// @Success 200 {object} models.Collection
+// @Failure 400 {object} map[string]string "Invalid collection ID"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Router /collections/{id} [get]
+func GetCollectionHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	var collection models.Collection
+	err = database.GetCollection("collections").FindOne(c.Request.Context(), bson.M{"_id": objectID, "user_id": userID}).Decode(&collection)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// @Summary Update a collection
+// @Description Rename a collection, change its description or replace its items
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Collection ID"
+// @Param request body UpdateCollectionRequest true "Fields to change"
+// @Success 200 {object} models.Collection
+// @Failure 400 {object} map[string]string "Invalid request body or items"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Router /collections/{id} [patch]
+func UpdateCollectionHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	var req UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	set := bson.M{"updated_at": time.Now()}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+			return
+		}
+		set["name"] = name
+	}
+	if req.Description != nil {
+		set["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.ItemIDs != nil {
+		itemIDs, err := ownedItemIDs(ctx, userID, req.ItemIDs)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		set["item_ids"] = itemIDs
+	}
+
+	var collection models.Collection
+	err = database.GetCollection("collections").FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, "user_id": userID},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&collection)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update collection"})
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// @Summary Delete a collection
+// @Description Delete one of the user's collections. The clothing items are kept; share links to the collection are revoked.
+// @Tags collections
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Collection ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid collection ID"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Router /collections/{id} [delete]
+func DeleteCollectionHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid collection ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := database.GetCollection("collections").DeleteOne(ctx, bson.M{"_id": objectID, "user_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete collection"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return
+	}
+	revokeShareLinks(ctx, models.ShareCollection, objectID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted successfully"})
+}
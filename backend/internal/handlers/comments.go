@@ -0,0 +1,781 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxCommentLength    = 1000
+	commentPageSize     = 20
+	commentMaxPageSize  = 100
+	errCommentsDisabled = "Comments are turned off"
+)
+
+var errCommentsOff = errors.New(errCommentsDisabled)
+
+// engagementTarget is a kind of thing that can be commented on and reacted to
+type engagementTarget struct {
+	kind       string // models.ShareItem or models.ShareOutfit
+	collection string
+	label      string // For error messages
+}
+
+var (
+	itemTarget   = engagementTarget{models.ShareItem, "clothing", "Clothing item"}
+	outfitTarget = engagementTarget{models.ShareOutfit, "outfits", "Outfit"}
+)
+
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type CommentSettingsRequest struct {
+	Disabled *bool `json:"disabled" binding:"required"`
+}
+
+type ReactionRequest struct {
+	Emoji string `json:"emoji" binding:"required" example:"🔥"`
+}
+
+type CommentView struct {
+	models.Comment
+	Author UserSummary `json:"author"`
+}
+
+type CommentPage struct {
+	Comments []CommentView `json:"comments"`
+	// Pass as ?before= and ?beforeId= to get older comments; empty on the last page
+	NextBefore   *time.Time `json:"nextBefore,omitempty"`
+	NextBeforeID string     `json:"nextBeforeId,omitempty"`
+}
+
+type ReactionSummary struct {
+	Counts map[string]int64 `json:"counts"`
+	Mine   []string         `json:"mine"` // Emojis the viewer has left
+}
+
+// engagementDoc is the part of an item or outfit that engagement needs
+type engagementDoc struct {
+	ID                primitive.ObjectID `bson:"_id"`
+	UserID            string             `bson:"user_id"`
+	IsPublic          bool               `bson:"is_public"`
+	models.Engagement `bson:",inline"`
+}
+
+// loadEngagementTarget fetches the target named by :id, answering 404 unless
+// the viewer owns it or it is public and its owner's profile is visible to them
+func loadEngagementTarget(c *gin.Context, t engagementTarget, viewerID string) (*engagementDoc, bool) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + strings.ToLower(t.label) + " ID"})
+		return nil, false
+	}
+
+	ctx := c.Request.Context()
+	var doc engagementDoc
+	err = database.GetCollection(t.collection).FindOne(ctx, bson.M{"_id": objectID},
+		options.FindOne().SetProjection(bson.M{"embedding": 0})).Decode(&doc)
+	if err != nil && err != mongo.ErrNoDocuments {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + strings.ToLower(t.label)})
+		return nil, false
+	}
+	if err == mongo.ErrNoDocuments || (doc.UserID != viewerID && (!doc.IsPublic || !itemOwnerVisible(ctx, viewerID, doc.UserID))) {
+		c.JSON(http.StatusNotFound, gin.H{"error": t.label + " not found"})
+		return nil, false
+	}
+	return &doc, true
+}
+
+// reactionSummary combines the target's counters with the viewer's own reactions
+func reactionSummary(ctx context.Context, t engagementTarget, targetID primitive.ObjectID, viewerID string) (ReactionSummary, error) {
+	var doc engagementDoc
+	err := database.GetCollection(t.collection).FindOne(ctx, bson.M{"_id": targetID},
+		options.FindOne().SetProjection(bson.M{"reaction_counts": 1})).Decode(&doc)
+	if err != nil {
+		return ReactionSummary{}, err
+	}
+	summary := ReactionSummary{Counts: map[string]int64{}, Mine: []string{}}
+	for emoji, count := range doc.ReactionCounts {
+		if count > 0 {
+			summary.Counts[emoji] = count
+		}
+	}
+
+	cursor, err := database.GetCollection("reactions").Find(ctx, bson.M{"target_type": t.kind, "target_id": targetID, "user_id": viewerID})
+	if err != nil {
+		return ReactionSummary{}, err
+	}
+	var mine []models.Reaction
+	if err := cursor.All(ctx, &mine); err != nil {
+		return ReactionSummary{}, err
+	}
+	for _, r := range mine {
+		summary.Mine = append(summary.Mine, r.Emoji)
+	}
+	return summary, nil
+}
+
+func listComments(c *gin.Context, t engagementTarget) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	target, ok := loadEngagementTarget(c, t, userID)
+	if !ok {
+		return
+	}
+
+	before := time.Now()
+	if raw := c.Query("before"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be an RFC 3339 timestamp"})
+			return
+		}
+		before = parsed
+	}
+	var beforeID primitive.ObjectID
+	if raw := c.Query("beforeId"); raw != "" {
+		parsed, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "beforeId must be a comment ID"})
+			return
+		}
+		beforeID = parsed
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(commentPageSize)))
+	if limit < 1 {
+		limit = commentPageSize
+	}
+	limit = min(limit, commentMaxPageSize)
+
+	// Ordered by (created_at, _id) so comments posted at the same instant
+	// aren't skipped at a page boundary
+	filter := bson.M{"target_type": t.kind, "target_id": target.ID, "created_at": bson.M{"$lt": before}}
+	if !beforeID.IsZero() {
+		delete(filter, "created_at")
+		filter["$or"] = []bson.M{
+			{"created_at": bson.M{"$lt": before}},
+			{"created_at": before, "_id": bson.M{"$lt": beforeID}},
+		}
+	}
+
+	ctx := c.Request.Context()
+	cursor, err := database.GetCollection("comments").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit)))
+	var comments []models.Comment
+	if err == nil {
+		err = cursor.All(ctx, &comments)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	authorIDs := make([]string, len(comments))
+	for i, comment := range comments {
+		authorIDs[i] = comment.UserID
+	}
+	authors, err := userSummaries(ctx, authorIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comment authors"})
+		return
+	}
+
+	page := CommentPage{Comments: []CommentView{}}
+	for _, comment := range comments {
+		page.Comments = append(page.Comments, CommentView{Comment: comment, Author: authors[comment.UserID]})
+	}
+	if len(comments) == limit {
+		last := comments[len(comments)-1]
+		page.NextBefore = &last.CreatedAt
+		page.NextBeforeID = last.ID.Hex()
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+func createComment(c *gin.Context, t engagementTarget) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment cannot be empty"})
+		return
+	}
+	if len([]rune(body)) > maxCommentLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comments can be at most 1000 characters"})
+		return
+	}
+
+	target, ok := loadEngagementTarget(c, t, userID)
+	if !ok {
+		return
+	}
+	if target.CommentsDisabled {
+		c.JSON(http.StatusForbidden, gin.H{"error": errCommentsDisabled})
+		return
+	}
+
+	comment := models.Comment{
+		ID:         primitive.NewObjectID(),
+		TargetType: t.kind,
+		TargetID:   target.ID,
+		OwnerID:    target.UserID,
+		UserID:     userID,
+		Body:       body,
+		CreatedAt:  time.Now(),
+	}
+	ctx := c.Request.Context()
+	err := database.WithTransaction(ctx, func(sc mongo.SessionContext) error {
+		// The counter update also re-checks that comments are still on
+		result, err := database.GetCollection(t.collection).UpdateOne(sc,
+			bson.M{"_id": target.ID, "comments_disabled": bson.M{"$ne": true}},
+			bson.M{"$inc": bson.M{"comment_count": 1}})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return errCommentsOff
+		}
+		_, err = database.GetCollection("comments").InsertOne(sc, comment)
+		return err
+	})
+	if errors.Is(err, errCommentsOff) {
+		c.JSON(http.StatusForbidden, gin.H{"error": errCommentsDisabled})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save comment"})
+		return
+	}
+
+	notifyFrom(userID, models.Notification{
+		UserID:     target.UserID,
+		Type:       models.NotifyComment,
+		TargetType: t.kind,
+		TargetID:   target.ID.Hex(),
+	}, "commented on %s", targetName(ctx, t, target.ID))
+
+	authors, _ := userSummaries(ctx, []string{userID})
+	c.JSON(http.StatusCreated, CommentView{Comment: comment, Author: authors[userID]})
+}
+
+// deleteComment lets the author, or the owner of what was commented on, remove a comment
+func deleteComment(c *gin.Context, t engagementTarget) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	targetID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + strings.ToLower(t.label) + " ID"})
+		return
+	}
+	commentID, err := primitive.ObjectIDFromHex(c.Param("commentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	deleted := false
+	err = database.WithTransaction(c.Request.Context(), func(sc mongo.SessionContext) error {
+		result, err := database.GetCollection("comments").DeleteOne(sc, bson.M{
+			"_id":         commentID,
+			"target_type": t.kind,
+			"target_id":   targetID,
+			"$or":         []bson.M{{"user_id": userID}, {"owner_id": userID}},
+		})
+		if err != nil {
+			return err
+		}
+		deleted = result.DeletedCount > 0
+		if !deleted {
+			return nil
+		}
+		_, err = database.GetCollection(t.collection).UpdateOne(sc, bson.M{"_id": targetID},
+			bson.M{"$inc": bson.M{"comment_count": -1}})
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted"})
+}
+
+func setCommentSettings(c *gin.Context, t engagementTarget) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + strings.ToLower(t.label) + " ID"})
+		return
+	}
+	var req CommentSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := database.GetCollection(t.collection).UpdateOne(c.Request.Context(),
+		bson.M{"_id": objectID, "user_id": userID},
+		bson.M{"$set": bson.M{"comments_disabled": *req.Disabled}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment settings"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": t.label + " not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"commentsDisabled": *req.Disabled})
+}
+
+func getReactions(c *gin.Context, t engagementTarget) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	target, ok := loadEngagementTarget(c, t, userID)
+	if !ok {
+		return
+	}
+	summary, err := reactionSummary(c.Request.Context(), t, target.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reactions"})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+func addReaction(c *gin.Context, t engagementTarget) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !slices.Contains(models.ReactionEmojis, req.Emoji) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported reaction", "allowed": models.ReactionEmojis})
+		return
+	}
+
+	target, ok := loadEngagementTarget(c, t, userID)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	added := false
+	err := database.WithTransaction(ctx, func(sc mongo.SessionContext) error {
+		// Reacting twice with the same emoji is a no-op
+		result, err := database.GetCollection("reactions").UpdateOne(sc,
+			bson.M{"target_type": t.kind, "target_id": target.ID, "user_id": userID, "emoji": req.Emoji},
+			bson.M{"$setOnInsert": models.Reaction{
+				ID:         primitive.NewObjectID(),
+				TargetType: t.kind,
+				TargetID:   target.ID,
+				OwnerID:    target.UserID,
+				UserID:     userID,
+				Emoji:      req.Emoji,
+				CreatedAt:  time.Now(),
+			}},
+			options.Update().SetUpsert(true))
+		if err != nil || result.UpsertedCount == 0 {
+			return err
+		}
+		_, err = database.GetCollection(t.collection).UpdateOne(sc, bson.M{"_id": target.ID},
+			bson.M{"$inc": bson.M{"reaction_counts." + req.Emoji: 1}})
+		added = err == nil
+		return err
+	})
+	// A concurrent request won the race to the unique index; it already reacted
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save reaction"})
+		return
+	}
+	if added {
+		notifyFrom(userID, models.Notification{
+			UserID:     target.UserID,
+			Type:       models.NotifyReaction,
+			TargetType: t.kind,
+			TargetID:   target.ID.Hex(),
+		}, "reacted %s to %s", req.Emoji, targetName(ctx, t, target.ID))
+	}
+
+	summary, err := reactionSummary(ctx, t, target.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reactions"})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+func removeReaction(c *gin.Context, t engagementTarget) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	targetID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + strings.ToLower(t.label) + " ID"})
+		return
+	}
+	emoji := c.Query("emoji")
+	if !slices.Contains(models.ReactionEmojis, emoji) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported reaction", "allowed": models.ReactionEmojis})
+		return
+	}
+
+	ctx := c.Request.Context()
+	err = database.WithTransaction(ctx, func(sc mongo.SessionContext) error {
+		result, err := database.GetCollection("reactions").DeleteOne(sc,
+			bson.M{"target_type": t.kind, "target_id": targetID, "user_id": userID, "emoji": emoji})
+		if err != nil || result.DeletedCount == 0 {
+			return err
+		}
+		return decrementReaction(sc, t.collection, targetID, emoji, 1)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove reaction"})
+		return
+	}
+
+	summary, err := reactionSummary(ctx, t, targetID, userID)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": t.label + " not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reactions"})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// decrementReaction lowers an emoji's counter, dropping it once it reaches zero
+func decrementReaction(ctx context.Context, collection string, targetID primitive.ObjectID, emoji string, by int64) error {
+	key := "reaction_counts." + emoji
+	targets := database.GetCollection(collection)
+	if _, err := targets.UpdateOne(ctx, bson.M{"_id": targetID}, bson.M{"$inc": bson.M{key: -by}}); err != nil {
+		return err
+	}
+	_, err := targets.UpdateOne(ctx, bson.M{"_id": targetID, key: bson.M{"$lte": 0}}, bson.M{"$unset": bson.M{key: ""}})
+	return err
+}
+
+// deleteEngagement removes the comments and reactions on a deleted item or outfit
+func deleteEngagement(ctx context.Context, kind string, targetID primitive.ObjectID) {
+	for _, name := range []string{"comments", "reactions"} {
+		if _, err := database.GetCollection(name).DeleteMany(ctx, bson.M{"target_type": kind, "target_id": targetID}); err != nil {
+			log.Printf("Could not delete %s on %s %s: %v", name, kind, targetID.Hex(), err)
+		}
+	}
+}
+
+// retractEngagement takes a departing user's comments and reactions off the
+// counters of other people's items and outfits. The documents themselves are
+// deleted with the rest of the account.
+func retractEngagement(ctx context.Context, userID string) error {
+	collections := map[string]string{itemTarget.kind: itemTarget.collection, outfitTarget.kind: outfitTarget.collection}
+	match := bson.M{"$match": bson.M{"user_id": userID, "owner_id": bson.M{"$ne": userID}}}
+
+	cursor, err := database.GetCollection("comments").Aggregate(ctx, []bson.M{
+		match,
+		{"$group": bson.M{"_id": bson.M{"type": "$target_type", "target": "$target_id"}, "count": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	var commentCounts []struct {
+		ID struct {
+			Type   string             `bson:"type"`
+			Target primitive.ObjectID `bson:"target"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &commentCounts); err != nil {
+		return err
+	}
+	for _, group := range commentCounts {
+		if _, err := database.GetCollection(collections[group.ID.Type]).UpdateOne(ctx, bson.M{"_id": group.ID.Target},
+			bson.M{"$inc": bson.M{"comment_count": -group.Count}}); err != nil {
+			return err
+		}
+	}
+
+	cursor, err = database.GetCollection("reactions").Find(ctx, match["$match"])
+	if err != nil {
+		return err
+	}
+	var reactions []models.Reaction
+	if err := cursor.All(ctx, &reactions); err != nil {
+		return err
+	}
+	for _, r := range reactions {
+		if err := decrementReaction(ctx, collections[r.TargetType], r.TargetID, r.Emoji, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// @Summary List comments on an item
+// @Description Comments on a public clothing item, newest first. Page with ?before= and ?beforeId= set to the previous page's nextBefore and nextBeforeId.
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clothing item ID"
+// @Param before query string false "RFC 3339 timestamp; only comments posted before it"
+// @Param beforeId query string false "ID of the last comment of the previous page; with before, also returns later comments posted at the same time"
+// @Param limit query int false "Comments per page (max 100)"
+// @Success 200 {object} handlers.CommentPage
+// @Failure 404 {object} map[string]string "Clothing item not found"
+// @Router /clothing/{id}/comments [get]
+func ListItemCommentsHandler(c *gin.Context) {
+	listComments(c, itemTarget)
+}
+
+// @Summary Comment on an item
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clothing item ID"
+// @Param request body CreateCommentRequest true "Comment"
+// @Success 201 {object} handlers.CommentView
+// @Failure 400 {object} map[string]string "Empty or too long"
+// @Failure 403 {object} map[string]string "Comments are turned off"
+// @Failure 404 {object} map[string]string "Clothing item not found"
+// @Router /clothing/{id}/comments [post]
+func CreateItemCommentHandler(c *gin.Context) {
+	createComment(c, itemTarget)
+}
+
+// @Summary Delete a comment on an item
+// @Description The comment's author or the item's owner can delete it
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clothing item ID"
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "Comment not found"
+// @Router /clothing/{id}/comments/{commentId} [delete]
+func DeleteItemCommentHandler(c *gin.Context) {
+	deleteComment(c, itemTarget)
+}
+
+// @Summary Turn comments on an item on or off
+// @Description Owner only. Existing comments stay visible; no new ones can be posted while comments are off.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clothing item ID"
+// @Param request body CommentSettingsRequest true "Settings"
+// @Success 200 {object} map[string]bool
+// @Failure 404 {object} map[string]string "Clothing item not found"
+// @Router /clothing/{id}/comment-settings [put]
+func SetItemCommentSettingsHandler(c *gin.Context) {
+	setCommentSettings(c, itemTarget)
+}
+
+// @Summary Get reactions on an item
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clothing item ID"
+// @Success 200 {object} handlers.ReactionSummary
+// @Failure 404 {object} map[string]string "Clothing item not found"
+// @Router /clothing/{id}/reactions [get]
+func GetItemReactionsHandler(c *gin.Context) {
+	getReactions(c, itemTarget)
+}
+
+// @Summary React to an item
+// @Description Leave an emoji reaction (one of ❤️ 🔥 😍 👍 😂 👀 ✨ 💯). Reacting again with the same emoji does nothing.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clothing item ID"
+// @Param request body ReactionRequest true "Reaction"
+// @Success 200 {object} handlers.ReactionSummary
+// @Failure 400 {object} map[string]string "Unsupported reaction"
+// @Failure 404 {object} map[string]string "Clothing item not found"
+// @Router /clothing/{id}/reactions [post]
+func AddItemReactionHandler(c *gin.Context) {
+	addReaction(c, itemTarget)
+}
+
+// @Summary Remove a reaction from an item
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clothing item ID"
+// @Param emoji query string true "Reaction to remove"
+// @Success 200 {object} handlers.ReactionSummary
+// @Failure 400 {object} map[string]string "Unsupported reaction"
+// @Failure 404 {object} map[string]string "Clothing item not found"
+// @Router /clothing/{id}/reactions [delete]
+func RemoveItemReactionHandler(c *gin.Context) {
+	removeReaction(c, itemTarget)
+}
+
+// @Summary List comments on an outfit
+// @Description Comments on a public outfit, newest first. Page with ?before= and ?beforeId= set to the previous page's nextBefore and nextBeforeId.
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outfit ID"
+// @Param before query string false "RFC 3339 timestamp; only comments posted before it"
+// @Param beforeId query string false "ID of the last comment of the previous page; with before, also returns later comments posted at the same time"
+// @Param limit query int false "Comments per page (max 100)"
+// @Success 200 {object} handlers.CommentPage
+// @Failure 404 {object} map[string]string "Outfit not found"
+// @Router /outfits/{id}/comments [get]
+func ListOutfitCommentsHandler(c *gin.Context) {
+	listComments(c, outfitTarget)
+}
+
+// @Summary Comment on an outfit
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outfit ID"
+// @Param request body CreateCommentRequest true "Comment"
+// @Success 201 {object} handlers.CommentView
+// @Failure 400 {object} map[string]string "Empty or too long"
+// @Failure 403 {object} map[string]string "Comments are turned off"
+// @Failure 404 {object} map[string]string "Outfit not found"
+// @Router /outfits/{id}/comments [post]
+func CreateOutfitCommentHandler(c *gin.Context) {
+	createComment(c, outfitTarget)
+}
+
+// @Summary Delete a comment on an outfit
+// @Description The comment's author or the outfit's owner can delete it
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outfit ID"
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "Comment not found"
+// @Router /outfits/{id}/comments/{commentId} [delete]
+func DeleteOutfitCommentHandler(c *gin.Context) {
+	deleteComment(c, outfitTarget)
+}
+
+// @Summary Turn comments on an outfit on or off
+// @Description Owner only. Existing comments stay visible; no new ones can be posted while comments are off.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outfit ID"
+// @Param request body CommentSettingsRequest true "Settings"
+// @Success 200 {object} map[string]bool
+// @Failure 404 {object} map[string]string "Outfit not found"
+// @Router /outfits/{id}/comment-settings [put]
+func SetOutfitCommentSettingsHandler(c *gin.Context) {
+	setCommentSettings(c, outfitTarget)
+}
+
+// @Summary Get reactions on an outfit
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outfit ID"
+// @Success 200 {object} handlers.ReactionSummary
+// @Failure 404 {object} map[string]string "Outfit not found"
+// @Router /outfits/{id}/reactions [get]
+func GetOutfitReactionsHandler(c *gin.Context) {
+	getReactions(c, outfitTarget)
+}
+
+// @Summary React to an outfit
+// @Description Leave an emoji reaction (one of ❤️ 🔥 😍 👍 😂 👀 ✨ 💯). Reacting again with the same emoji does nothing.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outfit ID"
+// @Param request body ReactionRequest true "Reaction"
+// @Success 200 {object} handlers.ReactionSummary
+// @Failure 400 {object} map[string]string "Unsupported reaction"
+// @Failure 404 {object} map[string]string "Outfit not found"
+// @Router /outfits/{id}/reactions [post]
+func AddOutfitReactionHandler(c *gin.Context) {
+	addReaction(c, outfitTarget)
+}
+
+// @Summary Remove a reaction from an outfit
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outfit ID"
+// @Param emoji query string true "Reaction to remove"
+// @Success 200 {object} handlers.ReactionSummary
+// @Failure 400 {object} map[string]string "Unsupported reaction"
+// @Failure 404 {object} map[string]string "Outfit not found"
+// @Router /outfits/{id}/reactions [delete]
+func RemoveOutfitReactionHandler(c *gin.Context) {
+	removeReaction(c, outfitTarget)
+}
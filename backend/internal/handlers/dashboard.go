@@ -29,7 +29,13 @@ func GetStylistMessageHandler(c *gin.Context) {
 	// Helper to count field
 	countField := func(field string) map[string]int {
 		pipeline := mongo.Pipeline{
-			{{Key: "$match", Value: bson.D{{Key: "user_id", Value: userID}}}},
+			// Only recommend from what's actually in the closet today;
+			// drafts from receipts have no tags yet
+			{{Key: "$match", Value: bson.D{
+				{Key: "user_id", Value: userID},
+				{Key: "availability", Value: availableFilter()},
+				{Key: "draft", Value: bson.M{"$ne": true}},
+			}}},
 			{{Key: "$unwind", Value: "$" + field}}, // Unwind arrays like colors
 			{{Key: "$group", Value: bson.D{
 				{Key: "_id", Value: "$" + field},
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/ai"
+	"github.com/exply/armoire/internal/fetch"
+	"github.com/exply/armoire/internal/imageproc"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// urlFetcher downloads user-supplied URLs. It can't reach private addresses;
+// swap it for one with a plain client to test against a local server.
+var urlFetcher = fetch.New()
+
+// processedImage is a garment photo that has been through the upload
+// pipeline: background removed, stored with a thumbnail, tagged and embedded
+type processedImage struct {
+	BaseName     string // Stored file name without extension, for related files
+	PublicURL    string
+	GCSURI       string
+	ThumbnailURL string
+	Analysis     *ai.ClothingAnalysis
+	Embedding    []float32
+}
+
+// processImage runs a garment photo through background removal, storage,
+// thumbnailing, AI tagging and embedding. The error messages are meant for the client.
+func processImage(ctx context.Context, gcsClient *storage.StorageClient, aiClient *ai.AIClient, originalBytes []byte, filename string) (*processedImage, error) {
+	finalBytes := originalBytes
+	finalMimeType := http.DetectContentType(originalBytes)
+	baseName := primitive.NewObjectID().Hex()
+	finalFilename := baseName + filepath.Ext(filename)
+
+	processedBytes, err := ai.RemoveBackground(ctx, originalBytes, filename)
+
+	if err == nil {
+		fmt.Println("Background removed successfully!")
+		finalBytes = processedBytes
+		finalMimeType = "image/png"
+		finalFilename = baseName + ".png" // Force extension to .png
+	} else {
+		fmt.Println("Background removal failed (using original):", err)
+	}
+
+	// Pass finalBytes to GCS
+	gcsURI, err := gcsClient.UploadFile(bytes.NewReader(finalBytes), finalFilename)
+	if err != nil {
+		return nil, errors.New("Failed to upload to GCS")
+	}
+	publicURL := "https://storage.googleapis.com/armoire-bucket/" + finalFilename
+
+	thumbBytes, err := imageproc.CreateThumbnail(finalBytes, 300)
+	var thumbURL string
+
+	if err == nil {
+		// Create a filename like "abc12345_thumb.png"
+		// This splits the extension to insert "_thumb"
+		ext := filepath.Ext(finalFilename)
+		nameWithoutExt := strings.TrimSuffix(finalFilename, ext)
+		thumbFilename := nameWithoutExt + "_thumb" + ext
+
+		// Upload Thumbnail to GCS
+		_, err := gcsClient.UploadFile(bytes.NewReader(thumbBytes), thumbFilename)
+		if err == nil {
+			thumbURL = "https://storage.googleapis.com/armoire-bucket/" + thumbFilename
+		}
+	} else {
+		// Fallback: If thumbnail gen fails, just use the full URL so the app doesn't break
+		thumbURL = publicURL
+	}
+
+	// Analyze with Gemini (Auto-Tagging)
+	analysis, err := aiClient.AnalyzeImage(ctx, bytes.NewReader(finalBytes), finalMimeType)
+	if err != nil {
+		return nil, errors.New("AI Analysis Failed: " + err.Error())
+	}
+
+	// Generate Vector Embedding for "Vibe Search"
+	// We embed the description Gemini just wrote for us
+	embedText := analysis.Description
+	if embedText == "" {
+		embedText = analysis.Name
+	}
+	vector, err := aiClient.GetEmbedding(ctx, embedText)
+	if err != nil {
+		return nil, errors.New("Vector Embedding Failed")
+	}
+
+	return &processedImage{
+		BaseName:     baseName,
+		PublicURL:    publicURL,
+		GCSURI:       gcsURI,
+		ThumbnailURL: thumbURL,
+		Analysis:     analysis,
+		Embedding:    vector,
+	}, nil
+}
+
+// newClothingItem builds a closet item from a processed photo and its AI tags
+func newClothingItem(userID string, image *processedImage) models.ClothingItem {
+	analysis := image.Analysis
+	return models.ClothingItem{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		ImageURL:     image.PublicURL,
+		GCSURI:       image.GCSURI,
+		ThumbnailURL: image.ThumbnailURL,
+		Name:         analysis.Name,
+		Category:     analysis.Category,
+		SubCategory:  analysis.SubCategory,
+		Description:  analysis.Description,
+		Colors:       analysis.Colors,
+		Seasons:      analysis.Seasons,
+		Occasions:    analysis.Occasions,
+		Materials:    analysis.Materials,
+		Pattern:      analysis.Pattern,
+		Fit:          analysis.Fit,
+		Neckline:     analysis.Neckline,
+		SleeveLength: analysis.SleeveLength,
+		BrandText:    analysis.Brand,
+		SizeLabel:    analysis.SizeLabel,
+		Availability: models.AvailabilityAvailable,
+		NeedsReview:  analysis.NeedsReview,
+		ReviewNotes:  analysis.ReviewNotes,
+		Embedding:    image.Embedding,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		IsPublic:     false,
+
+		AvailabilityChangedAt: time.Now(),
+	}
+}
+
+// deleteStoredImage removes an image and its thumbnail from GCS, logging failures
+func deleteStoredImage(gcsURI, thumbnailURL string) {
+	if gcsURI == "" {
+		return
+	}
+	gcsClient, err := storage.NewStorageClient("armoire-bucket")
+	if err != nil {
+		fmt.Printf("Warning: Failed to delete image from GCS: %v\n", err)
+		return
+	}
+	if err := gcsClient.DeleteFile(gcsURI); err != nil {
+		fmt.Printf("Warning: Failed to delete image from GCS: %v\n", err)
+	}
+	if uri, ok := gcsClient.URIFromPublicURL(thumbnailURL); ok && uri != gcsURI {
+		if err := gcsClient.DeleteFile(uri); err != nil {
+			fmt.Printf("Warning: Failed to delete thumbnail from GCS: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/exply/armoire/internal/ai"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/fetch"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/notify"
+	"github.com/exply/armoire/internal/productpage"
+	"github.com/exply/armoire/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// ImportFromURLRequest names the shop page to import
+type ImportFromURLRequest struct {
+	URL string `json:"url" binding:"required"`
+}
+
+// fetchErrorStatus is the status for a failed fetch of a user-supplied URL:
+// the user's fault for bad or private URLs, the other site's otherwise
+func fetchErrorStatus(err error) int {
+	if errors.Is(err, fetch.ErrInvalidURL) || errors.Is(err, fetch.ErrPrivateAddress) {
+		return http.StatusBadRequest
+	}
+	return http.StatusBadGateway
+}
+
+// sourceURL is the page's canonical URL if it's a web link, else the URL
+// that was fetched; the canonical one comes from the page and could be anything
+func sourceURL(canonical, fetched string) string {
+	if u, err := url.Parse(canonical); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return canonical
+	}
+	return fetched
+}
+
+// @Summary Import a clothing item from a product page
+// @Description Fetch a shop's product page, read its title, brand, price and main image from the JSON-LD Product or OpenGraph metadata, and add it to the closet through the normal upload pipeline (background removal, thumbnail, AI tagging and embedding). The page's title and brand win over the AI's guesses.
+// @Tags clothing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body handlers.ImportFromURLRequest true "Product page URL"
+// @Success 201 {object} models.ClothingItem
+// @Failure 400 {object} map[string]string "Invalid URL"
+// @Failure 401 {object} map[string]string "User ID not found in context"
+// @Failure 422 {object} map[string]string "No product or product image found on the page"
+// @Failure 500 {object} map[string]string "Failed to upload to GCS / AI Analysis Failed / Vector Embedding Failed / Database Save Failed"
+// @Failure 502 {object} map[string]string "Could not fetch the page or image"
+// @Router /clothing/import-url [post]
+func ImportClothingFromURLHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req ImportFromURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// 1. Fetch and parse the product page
+	page, err := urlFetcher.Get(ctx, strings.TrimSpace(req.URL))
+	if err != nil {
+		c.JSON(fetchErrorStatus(err), gin.H{"error": "Could not fetch the page: " + err.Error()})
+		return
+	}
+	if !strings.Contains(page.ContentType, "html") {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The URL is not a web page"})
+		return
+	}
+	product, err := productpage.Parse(page.Body, page.URL)
+	if errors.Is(err, productpage.ErrNoProduct) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No product found on the page"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Could not read the page"})
+		return
+	}
+	if product.ImageURL == "" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The page has no product image"})
+		return
+	}
+
+	// 2. Fetch the product image
+	img, err := urlFetcher.Get(ctx, product.ImageURL)
+	if err != nil {
+		c.JSON(fetchErrorStatus(err), gin.H{"error": "Could not fetch the product image: " + err.Error()})
+		return
+	}
+	if !strings.HasPrefix(img.ContentType, "image/") {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "The product image is not an image"})
+		return
+	}
+	filename := "image"
+	if u, err := url.Parse(img.URL); err == nil && path.Base(u.Path) != "/" {
+		filename = path.Base(u.Path)
+	}
+
+	// 3. Remove the background, store, tag and embed, as for an upload
+	gcsClient, _ := storage.NewStorageClient("armoire-bucket")
+	aiClient, err := ai.NewAIClient(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "AI client unavailable"})
+		return
+	}
+	image, err := processImage(ctx, gcsClient, aiClient, img.Body, filename)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 4. The shop knows the name and brand better than the AI does
+	newItem := newClothingItem(userID, image)
+	if product.Title != "" {
+		newItem.Name = product.Title
+	}
+	if product.Brand != "" {
+		newItem.BrandText = product.Brand
+	}
+	newItem.Price = product.Price
+	newItem.Currency = product.Currency
+	newItem.SourceURL = sourceURL(product.URL, page.URL)
+
+	collection := database.GetCollection("clothing")
+	if _, err := collection.InsertOne(ctx, newItem); err != nil {
+		deleteStoredImage(newItem.GCSURI, newItem.ThumbnailURL)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database Save Failed"})
+		return
+	}
+
+	notify.Send(ctx, models.Notification{
+		UserID:     userID,
+		Type:       models.NotifyUploadComplete,
+		TargetType: models.ShareItem,
+		TargetID:   newItem.ID.Hex(),
+		Text:       fmt.Sprintf("%s was imported into your closet", newItem.Name),
+	})
+
+	c.JSON(http.StatusCreated, newItem)
+}
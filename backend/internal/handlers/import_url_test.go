@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/exply/armoire/internal/fetch"
+	"github.com/gin-gonic/gin"
+)
+
+// shopServer serves product pages for the import tests
+func shopServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	page := func(head string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html><head>" + head + "</head><body></body></html>"))
+		}
+	}
+	mux.HandleFunc("/article", page(`<meta property="og:type" content="article"><meta property="og:image" content="/img/header.jpg">`))
+	mux.HandleFunc("/no-image", page(`<meta property="og:type" content="product"><meta property="og:title" content="Coat">`))
+	mux.HandleFunc("/bad-image", page(`<meta property="og:type" content="product"><meta property="og:image" content="/not-an-image.txt">`))
+	mux.HandleFunc("/missing-image", page(`<meta property="og:type" content="product"><meta property="og:image" content="/gone.jpg">`))
+	mux.HandleFunc("/file.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"@type": "Product"}`))
+	})
+	mux.HandleFunc("/not-an-image.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("hello"))
+	})
+	mux.HandleFunc("/gone.jpg", http.NotFound)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// postImport calls ImportClothingFromURLHandler as a signed-in user
+func postImport(t *testing.T, pageURL string) (int, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/clothing/import-url", func(c *gin.Context) {
+		c.Set("userID", "user-1")
+	}, ImportClothingFromURLHandler)
+
+	body, _ := json.Marshal(ImportFromURLRequest{URL: pageURL})
+	req := httptest.NewRequest(http.MethodPost, "/clothing/import-url", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp map[string]string
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp["error"]
+}
+
+func TestImportClothingFromURLRejects(t *testing.T) {
+	server := shopServer(t)
+
+	// The real fetcher refuses the test server's loopback address
+	original := urlFetcher
+	urlFetcher = &fetch.Fetcher{Client: server.Client()}
+	t.Cleanup(func() { urlFetcher = original })
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantError  string
+	}{
+		{"not http", "ftp://shop.example/coat", http.StatusBadRequest, "Could not fetch the page"},
+		{"not a web page", server.URL + "/file.json", http.StatusUnprocessableEntity, "The URL is not a web page"},
+		{"no product", server.URL + "/article", http.StatusUnprocessableEntity, "No product found on the page"},
+		{"no product image", server.URL + "/no-image", http.StatusUnprocessableEntity, "The page has no product image"},
+		{"image is not an image", server.URL + "/bad-image", http.StatusUnprocessableEntity, "The product image is not an image"},
+		{"image is missing", server.URL + "/missing-image", http.StatusBadGateway, "Could not fetch the product image"},
+		{"page is missing", server.URL + "/nowhere", http.StatusBadGateway, "Could not fetch the page"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, errMsg := postImport(t, tt.url)
+			if status != tt.wantStatus || !strings.HasPrefix(errMsg, tt.wantError) {
+				t.Errorf("status %d %q, want %d %q", status, errMsg, tt.wantStatus, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestImportClothingFromURLPrivateAddress(t *testing.T) {
+	server := shopServer(t)
+
+	status, errMsg := postImport(t, server.URL+"/no-image")
+	if status != http.StatusBadRequest || !strings.Contains(errMsg, fetch.ErrPrivateAddress.Error()) {
+		t.Errorf("status %d %q, want 400 with %q", status, errMsg, fetch.ErrPrivateAddress)
+	}
+}
+
+func TestSourceURL(t *testing.T) {
+	fetched := "https://shop.example/p/1?utm_source=x"
+	tests := map[string]string{
+		"https://shop.example/p/1": "https://shop.example/p/1",
+		"http://shop.example/p/1":  "http://shop.example/p/1",
+		"javascript:alert(1)":      fetched,
+		"data:text/html,hi":        fetched,
+		"//shop.example/p/1":       fetched,
+		"":                         fetched,
+	}
+	for canonical, want := range tests {
+		if got := sourceURL(canonical, fetched); got != want {
+			t.Errorf("sourceURL(%q) = %q, want %q", canonical, got, want)
+		}
+	}
+}
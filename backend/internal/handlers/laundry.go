@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/laundry"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LaundryLoadsRequest lists the items to sort into loads.
+// When empty, every item currently marked as in the laundry is used.
+type LaundryLoadsRequest struct {
+	ItemIDs []string `json:"itemIds"`
+}
+
+// LaundryLoadsResponse is the result of grouping items by care requirements
+type LaundryLoadsResponse struct {
+	Loads []laundry.Load `json:"loads"`
+	// IDs that were not found in the user's closet
+	MissingItemIDs []string `json:"missingItemIds"`
+}
+
+// @Summary Group items into laundry loads
+// @Description Sort a set of clothing items into loads with compatible care requirements (method, colors, cycle and temperature). Defaults to the items marked as in the laundry.
+// @Tags laundry
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body handlers.LaundryLoadsRequest true "Items to wash"
+// @Success 200 {object} handlers.LaundryLoadsResponse
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 500 {string} string "Failed to fetch clothing items"
+// @Router /laundry/loads [post]
+func GetLaundryLoadsHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req LaundryLoadsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	objectIDs := make([]primitive.ObjectID, 0, len(req.ItemIDs))
+	for _, id := range req.ItemIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clothing ID: " + id})
+			return
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	collection := database.GetCollection("clothing")
+	ctx := c.Request.Context()
+
+	filter := bson.M{"_id": bson.M{"$in": objectIDs}, "user_id": userID}
+	if len(objectIDs) == 0 {
+		filter = bson.M{"user_id": userID, "availability": models.AvailabilityInLaundry}
+	}
+	cursor, err := collection.Find(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+
+	var items []models.ClothingItem
+	if err = cursor.All(ctx, &items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode clothing items"})
+		return
+	}
+
+	found := make(map[string]bool)
+	for _, item := range items {
+		found[item.ID.Hex()] = true
+	}
+	missing := []string{}
+	for _, id := range req.ItemIDs {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	c.JSON(http.StatusOK, LaundryLoadsResponse{
+		Loads:          laundry.GroupLoads(items),
+		MissingItemIDs: missing,
+	})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	lockoutThreshold = 5                // Failed logins before the account locks
+	lockoutBase      = 30 * time.Second // First lockout, doubled for every further failure
+	lockoutMax       = time.Hour
+
+	// Failures further apart than this start the count over, so the odd
+	// typo weeks apart never adds up to a lockout
+	failedLoginWindow = 24 * time.Hour
+)
+
+// loginFailuresCollection holds one models.LoginFailures per email address
+// that recently failed a login
+const loginFailuresCollection = "login_failures"
+
+// dummyHash stands in for the password hash of an unknown email or a
+// passwordless account, so those logins take as long as a wrong password
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+
+// loginFailures returns the failed logins recorded for email, zero if none
+func loginFailures(ctx context.Context, email string) (models.LoginFailures, error) {
+	var failures models.LoginFailures
+	err := database.GetCollection(loginFailuresCollection).FindOne(ctx, bson.M{"_id": email}).Decode(&failures)
+	if err == mongo.ErrNoDocuments {
+		return models.LoginFailures{Email: email}, nil
+	}
+	return failures, err
+}
+
+// lockedFor is how much of the email's lockout is left, or 0
+func lockedFor(failures models.LoginFailures) time.Duration {
+	if failures.LockedUntil == nil {
+		return 0
+	}
+	return max(time.Until(*failures.LockedUntil), 0)
+}
+
+// recordFailedLogin counts a failed attempt for email and locks it once past
+// the threshold, for lockoutBase doubled per further failure up to lockoutMax.
+// Counting and locking are one atomic update, so parallel attempts can't slip
+// past the threshold. It returns the resulting lockout, 0 if none.
+func recordFailedLogin(ctx context.Context, email string) (time.Duration, error) {
+	collection := database.GetCollection(loginFailuresCollection)
+	now := time.Now()
+
+	count := bson.M{"$cond": bson.A{
+		bson.M{"$gt": bson.A{"$last_failure", now.Add(-failedLoginWindow)}},
+		bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$count", 0}}, 1}},
+		1,
+	}}
+	lockout := bson.M{"$min": bson.A{
+		bson.M{"$multiply": bson.A{
+			lockoutBase.Milliseconds(),
+			bson.M{"$pow": bson.A{2, bson.M{"$subtract": bson.A{"$count", lockoutThreshold}}}},
+		}},
+		lockoutMax.Milliseconds(),
+	}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"count": count, "last_failure": now}}},
+		{{Key: "$set", Value: bson.M{"locked_until": bson.M{"$cond": bson.A{
+			bson.M{"$gte": bson.A{"$count", lockoutThreshold}},
+			bson.M{"$add": bson.A{now, lockout}},
+			"$locked_until",
+		}}}}},
+	}
+
+	// Attempts that raced past a lockout set by a parallel one don't count
+	// again; their upsert then collides with the locked document
+	filter := bson.M{"_id": email, "$or": bson.A{
+		bson.M{"locked_until": nil},
+		bson.M{"locked_until": bson.M{"$lte": now}},
+	}}
+
+	var updated models.LoginFailures
+	err := collection.FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&updated)
+	if mongo.IsDuplicateKeyError(err) {
+		updated, err = loginFailures(ctx, email)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return lockedFor(updated), nil
+}
+
+// clearFailedLogins resets the counter after a successful login or password reset
+func clearFailedLogins(ctx context.Context, failures models.LoginFailures) error {
+	if failures.Count == 0 && failures.LockedUntil == nil {
+		return nil
+	}
+	_, err := database.GetCollection(loginFailuresCollection).DeleteOne(ctx, bson.M{"_id": failures.Email})
+	return err
+}
@@ -0,0 +1,425 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/notify"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	notificationPageSize    = 30
+	notificationMaxPageSize = 100
+	streamHeartbeat         = 25 * time.Second
+	// Streams are closed after this long so the client reconnects with a fresh token
+	streamMaxAge  = time.Hour
+	streamReplay  = 100 // Most notifications replayed after Last-Event-ID
+	notifyTimeout = 5 * time.Second
+	// A ticket only has to survive until the EventSource connects
+	streamTicketTTL = time.Minute
+)
+
+type StreamTicketResponse struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type NotificationPage struct {
+	Notifications []models.Notification `json:"notifications"`
+	UnreadCount   int64                 `json:"unreadCount"`
+	// Pass as ?before= and ?beforeId= to get older notifications; empty on the last page
+	NextBefore   *time.Time `json:"nextBefore,omitempty"`
+	NextBeforeID string     `json:"nextBeforeId,omitempty"`
+}
+
+// notifyFrom sends n on behalf of actorID; text follows the actor's name, e.g.
+// "commented on Denim Jacket". It doesn't use the request's context, so it
+// still goes out if the client disconnects.
+func notifyFrom(actorID string, n models.Notification, format string, args ...interface{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	n.ActorID = actorID
+	n.ActorName = "Someone"
+	if summaries, err := userSummaries(ctx, []string{actorID}); err == nil {
+		if actor, ok := summaries[actorID]; ok && actor.Name != "" {
+			n.ActorName = actor.Name
+		}
+	}
+	n.Text = n.ActorName + " " + fmt.Sprintf(format, args...)
+	notify.Send(ctx, n)
+}
+
+// targetName is the name of an item or outfit for notification text
+func targetName(ctx context.Context, t engagementTarget, id primitive.ObjectID) string {
+	var doc struct {
+		Name string `bson:"name"`
+	}
+	err := database.GetCollection(t.collection).FindOne(ctx, bson.M{"_id": id},
+		options.FindOne().SetProjection(bson.M{"name": 1})).Decode(&doc)
+	if err != nil || doc.Name == "" {
+		return "your " + t.kind
+	}
+	return doc.Name
+}
+
+// @Summary List notifications
+// @Description The authenticated user's notifications, newest first, with the number still unread. Page with ?before= and ?beforeId= set to the previous page's nextBefore and nextBeforeId.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param before query string false "RFC 3339 timestamp; only notifications created before it"
+// @Param beforeId query string false "ID of the last notification of the previous page; with before, also returns later notifications created at the same time"
+// @Param limit query int false "Notifications per page (max 100)"
+// @Param unread query bool false "Only unread notifications"
+// @Success 200 {object} handlers.NotificationPage
+// @Failure 400 {object} map[string]string "Invalid cursor"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notifications [get]
+func ListNotificationsHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	before := time.Now()
+	if raw := c.Query("before"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be an RFC 3339 timestamp"})
+			return
+		}
+		before = parsed
+	}
+	var beforeID primitive.ObjectID
+	if raw := c.Query("beforeId"); raw != "" {
+		parsed, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "beforeId must be a notification ID"})
+			return
+		}
+		beforeID = parsed
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(notificationPageSize)))
+	if limit < 1 {
+		limit = notificationPageSize
+	}
+	limit = min(limit, notificationMaxPageSize)
+
+	// Ordered by (created_at, _id) so notifications created at the same
+	// instant, e.g. by one bulk action, aren't skipped at a page boundary
+	filter := bson.M{"user_id": userID, "created_at": bson.M{"$lt": before}}
+	if !beforeID.IsZero() {
+		delete(filter, "created_at")
+		filter["$or"] = []bson.M{
+			{"created_at": bson.M{"$lt": before}},
+			{"created_at": before, "_id": bson.M{"$lt": beforeID}},
+		}
+	}
+	if c.Query("unread") == "true" {
+		filter["read_at"] = bson.M{"$exists": false}
+	}
+
+	ctx := c.Request.Context()
+	collection := database.GetCollection(notify.Collection)
+	page := NotificationPage{Notifications: []models.Notification{}}
+	cursor, err := collection.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit)))
+	if err == nil {
+		err = cursor.All(ctx, &page.Notifications)
+	}
+	if err == nil {
+		page.UnreadCount, err = collection.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}})
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+	if len(page.Notifications) == limit {
+		last := page.Notifications[len(page.Notifications)-1]
+		page.NextBefore = &last.CreatedAt
+		page.NextBeforeID = last.ID.Hex()
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary Mark a notification read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid notification ID"
+// @Failure 404 {object} map[string]string "Notification not found"
+// @Router /notifications/{id}/read [post]
+func MarkNotificationReadHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	// Already-read notifications keep their original read time
+	collection := database.GetCollection(notify.Collection)
+	ctx := c.Request.Context()
+	if _, err := collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "user_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": time.Now()}}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+	if count, _ := collection.CountDocuments(ctx, bson.M{"_id": objectID, "user_id": userID}); count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked read"})
+}
+
+// @Summary Mark all notifications read
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]int64
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notifications/read-all [post]
+func MarkAllNotificationsReadHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	result, err := database.GetCollection(notify.Collection).UpdateMany(c.Request.Context(),
+		bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": time.Now()}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": result.ModifiedCount})
+}
+
+// @Summary Delete a notification
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid notification ID"
+// @Failure 404 {object} map[string]string "Notification not found"
+// @Router /notifications/{id} [delete]
+func DeleteNotificationHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	result, err := database.GetCollection(notify.Collection).DeleteOne(c.Request.Context(), bson.M{"_id": objectID, "user_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification deleted"})
+}
+
+// @Summary Get a notification stream ticket
+// @Description Issue a single-use ticket that opens GET /notifications/stream without an Authorization header, for browsers' EventSource. It expires after a minute; get a new one for every (re)connect.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} handlers.StreamTicketResponse
+// @Failure 401 {object} map[string]string "User ID not found in context"
+// @Failure 500 {object} map[string]string "Failed to create ticket"
+// @Router /notifications/stream-ticket [post]
+func CreateStreamTicketHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+	sessionID := c.GetString("sessionID")
+
+	ticket, err := newRefreshSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ticket"})
+		return
+	}
+	ctx := c.Request.Context()
+	collection := database.GetCollection("stream_tickets")
+	now := time.Now()
+
+	// Tickets that were never used would otherwise pile up
+	if _, err := collection.DeleteMany(ctx, bson.M{"user_id": userID, "expires_at": bson.M{"$lte": now}}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ticket"})
+		return
+	}
+
+	expiresAt := now.Add(streamTicketTTL)
+	_, err = collection.InsertOne(ctx, models.StreamTicket{
+		ID:         primitive.NewObjectID(),
+		TicketHash: hashToken(ticket),
+		UserID:     userID,
+		SessionID:  sessionID,
+		ExpiresAt:  expiresAt,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create ticket"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, StreamTicketResponse{Ticket: ticket, ExpiresAt: expiresAt})
+}
+
+// @Summary Stream notifications
+// @Description Server-sent events: one "notification" event per new notification, with the notification's ID as the event ID. Reconnect with Last-Event-ID (or ?lastEventId=) to receive what was missed. Browsers' EventSource can't send headers, so it authenticates with ?ticket= from POST /notifications/stream-ticket instead; a ticket works once, so get a new one before reconnecting. Streams close after an hour or when the session is revoked.
+// @Tags notifications
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param ticket query string false "Stream ticket, if no Authorization header is sent"
+// @Param lastEventId query string false "Replay notifications after this one"
+// @Success 200 {string} string "Event stream"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /notifications/stream [get]
+func NotificationStreamHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+	sessionID, _ := c.Get("sessionID")
+
+	// Subscribe before replaying so nothing slips in between
+	events, cancel := notify.Default.Subscribe(userID)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Keep proxies from buffering events
+	c.Status(http.StatusOK)
+
+	write := func(n models.Notification) bool {
+		data, err := json.Marshal(n)
+		if err != nil {
+			return true
+		}
+		if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: notification\ndata: %s\n\n", n.ID.Hex(), data); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+
+	ctx := c.Request.Context()
+	sent := map[primitive.ObjectID]bool{}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+	if after, err := primitive.ObjectIDFromHex(lastEventID); err == nil {
+		cursor, err := database.GetCollection(notify.Collection).Find(ctx,
+			bson.M{"user_id": userID, "_id": bson.M{"$gt": after}},
+			options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(streamReplay))
+		var missed []models.Notification
+		if err == nil {
+			err = cursor.All(ctx, &missed)
+		}
+		if err != nil {
+			log.Printf("Could not replay notifications for %s: %v", userID, err)
+		}
+		for _, n := range missed {
+			sent[n.ID] = true
+			if !write(n) {
+				return
+			}
+		}
+	}
+	fmt.Fprint(c.Writer, ": connected\n\n")
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(streamMaxAge)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			return
+		case n, ok := <-events:
+			if !ok {
+				return
+			}
+			if sent[n.ID] {
+				continue
+			}
+			if !write(n) {
+				return
+			}
+		case <-heartbeat.C:
+			if !streamSessionActive(ctx, sessionID) {
+				return
+			}
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// streamSessionActive re-checks the stream's session, so logging out or being
+// disabled ends open streams too
+func streamSessionActive(ctx context.Context, sessionID interface{}) bool {
+	id, _ := sessionID.(string)
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false
+	}
+	var session models.Session
+	if err := database.GetCollection("sessions").FindOne(ctx, bson.M{"_id": objectID}).Decode(&session); err != nil {
+		return false
+	}
+	return session.Active()
+}
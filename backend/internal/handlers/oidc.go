@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/oidc"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// loginStateTTL is how long a user has to finish logging in at the provider
+const loginStateTTL = 10 * time.Minute
+
+var (
+	errEmailNotVerified  = errors.New("an account with this email already exists, but the provider has not verified the email")
+	errAccountUnverified = errors.New("an account with this email already exists; sign in with its password and verify the email before linking")
+	errNoEmail           = errors.New("the provider did not share an email address")
+)
+
+// OIDCProvider is a login option shown on the login page
+type OIDCProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+// @Summary List social login providers
+// @Description List the configured OpenID Connect providers
+// @Tags auth
+// @Produce json
+// @Success 200 {array} handlers.OIDCProvider
+// @Router /auth/oidc/providers [get]
+func ListOIDCProvidersHandler(c *gin.Context) {
+	result := []OIDCProvider{}
+	for _, config := range oidc.List() {
+		result = append(result, OIDCProvider{Name: config.Name, DisplayName: config.DisplayName})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Start a social login
+// @Description Redirect to the provider's authorization page (authorization code flow with PKCE)
+// @Tags auth
+// @Param provider path string true "Provider name"
+// @Success 302
+// @Failure 404 {object} map[string]string "Unknown provider"
+// @Failure 502 {object} map[string]string "Provider unavailable"
+// @Router /auth/oidc/{provider}/login [get]
+func OIDCLoginHandler(c *gin.Context) {
+	provider, ok := oidc.Get(c.Param("provider"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown provider"})
+		return
+	}
+
+	state, err1 := oidc.RandomString()
+	nonce, err2 := oidc.RandomString()
+	verifier, err3 := oidc.RandomString()
+	if err1 != nil || err2 != nil || err3 != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start login"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Provider unavailable", "details": err.Error()})
+		return
+	}
+
+	_, err = database.GetCollection("login_states").InsertOne(ctx, models.LoginState{
+		State:        state,
+		Provider:     provider.Config.Name,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(loginStateTTL),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not start login"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// @Summary Finish a social login
+// @Description Provider callback. Exchanges the code, links or creates the account by verified email and starts a session. Redirects to OIDC_SUCCESS_REDIRECT with the tokens in the URL fragment when configured, otherwise returns JSON.
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the login redirect"
+// @Success 200 {object} map[string]interface{} "Tokens and user data"
+// @Failure 401 {object} map[string]string "Login failed"
+// @Failure 409 {object} map[string]string "Email belongs to an existing account, but the provider or the account has not verified it"
+// @Router /auth/oidc/{provider}/callback [get]
+func OIDCCallbackHandler(c *gin.Context) {
+	provider, ok := oidc.Get(c.Param("provider"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown provider"})
+		return
+	}
+
+	if providerErr := c.Query("error"); providerErr != "" {
+		oidcFail(c, http.StatusUnauthorized, "Login was cancelled or denied: "+providerErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// States are single use: take it out of the collection as we read it
+	var loginState models.LoginState
+	err := database.GetCollection("login_states").FindOneAndDelete(ctx, bson.M{
+		"_id":        c.Query("state"),
+		"provider":   provider.Config.Name,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&loginState)
+	if err != nil {
+		oidcFail(c, http.StatusUnauthorized, "Login expired, please try again")
+		return
+	}
+
+	claims, err := provider.Exchange(ctx, c.Query("code"), loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		oidcFail(c, http.StatusUnauthorized, "Could not verify login with provider: "+err.Error())
+		return
+	}
+
+	user, err := findOrLinkOIDCUser(ctx, provider.Config.Name, claims)
+	if errors.Is(err, errEmailNotVerified) || errors.Is(err, errAccountUnverified) {
+		oidcFail(c, http.StatusConflict, err.Error())
+		return
+	}
+	if errors.Is(err, errNoEmail) {
+		oidcFail(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		oidcFail(c, http.StatusInternalServerError, "Could not sign in")
+		return
+	}
+	if user.Disabled {
+		oidcFail(c, http.StatusForbidden, "This account has been disabled")
+		return
+	}
+
+	tokens, err := startSession(c, user.ID.Hex())
+	if err != nil {
+		oidcFail(c, http.StatusInternalServerError, "Could not generate token")
+		return
+	}
+
+	// Browsers land back on the frontend; tokens go in the fragment so they never reach server logs
+	if redirect := os.Getenv("OIDC_SUCCESS_REDIRECT"); redirect != "" {
+		fragment := url.Values{
+			"token":        {tokens.Token},
+			"refreshToken": {tokens.RefreshToken},
+			"expiresIn":    {strconv.Itoa(tokens.ExpiresIn)},
+		}
+		c.Redirect(http.StatusFound, redirect+"#"+fragment.Encode())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":        tokens.Token,
+		"refreshToken": tokens.RefreshToken,
+		"expiresIn":    tokens.ExpiresIn,
+		"user":         user,
+	})
+}
+
+// oidcFail reports a login error either to the frontend (as a fragment) or as JSON
+func oidcFail(c *gin.Context, status int, message string) {
+	if redirect := os.Getenv("OIDC_SUCCESS_REDIRECT"); redirect != "" {
+		c.Redirect(http.StatusFound, redirect+"#"+url.Values{"error": {message}}.Encode())
+		return
+	}
+	c.JSON(status, gin.H{"error": message})
+}
+
+// findOrLinkOIDCUser resolves the Armoire account for a provider identity:
+// an already-linked account, else an existing account whose email both sides have verified, else a new account.
+func findOrLinkOIDCUser(ctx context.Context, provider string, claims *oidc.IDClaims) (*models.User, error) {
+	collection := database.GetCollection("users")
+
+	var user models.User
+	err := collection.FindOne(ctx, bson.M{
+		"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": claims.Subject}},
+	}).Decode(&user)
+	if err == nil {
+		return &user, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	email := models.NormalizeEmail(claims.Email)
+	if email == "" {
+		return nil, errNoEmail
+	}
+
+	identity := models.Identity{
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    email,
+		LinkedAt: time.Now(),
+	}
+
+	err = collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	if err == nil {
+		// Only link on a verified email, otherwise anyone could claim the account at a lax provider
+		if !claims.EmailVerified {
+			return nil, errEmailNotVerified
+		}
+		// Nor onto an account that never proved it owns the email: whoever
+		// registered it could have pre-registered someone else's address and
+		// would keep their password after the owner linked their identity
+		if !user.EmailVerified {
+			return nil, errAccountUnverified
+		}
+		_, err = collection.UpdateOne(ctx, bson.M{"_id": user.ID, "email_verified": true}, bson.M{
+			"$push": bson.M{"identities": identity},
+		})
+		if err != nil {
+			return nil, err
+		}
+		user.Identities = append(user.Identities, identity)
+		return &user, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = strings.Split(email, "@")[0]
+	}
+
+	user = models.User{
+		ID:         primitive.NewObjectID(),
+		Name:       name,
+		Email:      email,
+		CreatedAt:  time.Now(),
+		Role:       models.RoleUser,
+		Identities: []models.Identity{identity},
+
+		EmailVerified: bool(claims.EmailVerified),
+	}
+	if _, err := collection.InsertOne(ctx, user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CreateOutfitRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	ItemIDs  []string `json:"itemIds" binding:"required,min=1"`
+	VibeTags []string `json:"vibeTags"`
+	IsPublic bool     `json:"isPublic"`
+}
+
+// UpdateOutfitRequest changes only the fields that are sent
+type UpdateOutfitRequest struct {
+	Name     *string  `json:"name"`
+	ItemIDs  []string `json:"itemIds"`
+	VibeTags []string `json:"vibeTags"`
+	IsPublic *bool    `json:"isPublic"`
+}
+
+// ownedItemIDs parses ids and checks every one is a clothing item the user owns
+func ownedItemIDs(ctx context.Context, userID string, ids []string) ([]primitive.ObjectID, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	seen := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid item ID %q", id)
+		}
+		if !seen[objectID] {
+			seen[objectID] = true
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	count, err := database.GetCollection("clothing").CountDocuments(ctx, bson.M{
+		"_id":     bson.M{"$in": objectIDs},
+		"user_id": userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if int(count) != len(objectIDs) {
+		return nil, fmt.Errorf("some items don't exist or aren't yours")
+	}
+	return objectIDs, nil
+}
+
+// @Summary Create an outfit
+// @Description Save a combination of the user's clothing items as an outfit
+// @Tags outfits
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOutfitRequest true "Outfit"
+// @Success 201 {object} models.Outfit
+// @Failure 400 {object} map[string]string "Invalid request body or items"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /outfits [post]
+func CreateOutfitHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req CreateOutfitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	itemIDs, err := ownedItemIDs(ctx, userID, req.ItemIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.VibeTags == nil {
+		req.VibeTags = []string{}
+	}
+
+	now := time.Now()
+	outfit := models.Outfit{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Name:      name,
+		ItemIDs:   itemIDs,
+		VibeTags:  req.VibeTags,
+		IsPublic:  req.IsPublic,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := database.GetCollection("outfits").InsertOne(ctx, outfit); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save outfit"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, outfit)
+}
+
+// @Summary List outfits
+// @Description Get all of the user's outfits, newest first
+// @Tags outfits
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Outfit
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /outfits [get]
+func ListOutfitsHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	ctx := c.Request.Context()
+	cursor, err := database.GetCollection("outfits").Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	outfits := []models.Outfit{}
+	if err == nil {
+		err = cursor.All(ctx, &outfits)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch outfits"})
+		return
+	}
+
+	c.JSON(http.StatusOK, outfits)
+}
+
+// @Summary Get an outfit
+// @Description Get one of the user's outfits by ID
+// @Tags outfits
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outfit ID"
+// @Success 200 {object} models.Outfit
+// @Failure 400 {object} map[string]string "Invalid outfit ID"
+// @Failure 404 {object} map[string]string "Outfit not found"
+// @Router /outfits/{id} [get]
+func GetOutfitHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid outfit ID"})
+		return
+	}
+
+	var outfit models.Outfit
+	err = database.GetCollection("outfits").FindOne(c.Request.Context(), bson.M{"_id": objectID, "user_id": userID}).Decode(&outfit)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Outfit not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch outfit"})
+		return
+	}
+
+	c.JSON(http.StatusOK, outfit)
+}
+
+// @Summary Update an outfit
+// @Description Rename an outfit, change its items or vibe tags, or make it public
+// @Tags outfits
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outfit ID"
+// @Param request body UpdateOutfitRequest true "Fields to change"
+// @Success 200 {object} models.Outfit
+// @Failure 400 {object} map[string]string "Invalid request body or items"
+// @Failure 404 {object} map[string]string "Outfit not found"
+// @Router /outfits/{id} [patch]
+func UpdateOutfitHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid outfit ID"})
+		return
+	}
+
+	var req UpdateOutfitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	set := bson.M{"updated_at": time.Now()}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+			return
+		}
+		set["name"] = name
+	}
+	if req.ItemIDs != nil {
+		if len(req.ItemIDs) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "An outfit needs at least one item"})
+			return
+		}
+		itemIDs, err := ownedItemIDs(ctx, userID, req.ItemIDs)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		set["item_ids"] = itemIDs
+	}
+	if req.VibeTags != nil {
+		set["vibe_tags"] = req.VibeTags
+	}
+	if req.IsPublic != nil {
+		set["is_public"] = *req.IsPublic
+	}
+
+	var outfit models.Outfit
+	err = database.GetCollection("outfits").FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, "user_id": userID},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&outfit)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Outfit not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update outfit"})
+		return
+	}
+
+	c.JSON(http.StatusOK, outfit)
+}
+
+// @Summary Delete an outfit
+// @Description Delete one of the user's outfits. The clothing items are kept; share links to the outfit are revoked and its comments and reactions deleted.
+// @Tags outfits
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Outfit ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid outfit ID"
+// @Failure 404 {object} map[string]string "Outfit not found"
+// @Router /outfits/{id} [delete]
+func DeleteOutfitHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid outfit ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := database.GetCollection("outfits").DeleteOne(ctx, bson.M{"_id": objectID, "user_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete outfit"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Outfit not found"})
+		return
+	}
+	revokeShareLinks(ctx, models.ShareOutfit, objectID)
+	deleteEngagement(ctx, models.ShareOutfit, objectID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Outfit deleted successfully"})
+}
@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/capsule"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/taxonomy"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxTripDays = 30
+
+// Checklist notes for items that aren't in the closet right now
+var packingNotes = map[string]string{
+	models.AvailabilityInLaundry:  "In the laundry, wash it before you go",
+	models.AvailabilityDryCleaner: "At the dry cleaner, pick it up before you go",
+	models.AvailabilityInStorage:  "In storage, get it out before you go",
+	models.AvailabilityLent:       "Lent out, due back before the trip",
+}
+
+// CreatePackingListRequest describes a trip. Send either endDate or days.
+type CreatePackingListRequest struct {
+	Name        string                   `json:"name" example:"Lisbon"`
+	Destination string                   `json:"destination" example:"Lisbon, Portugal"`
+	StartDate   string                   `json:"startDate" binding:"required" example:"2026-06-12"`
+	EndDate     string                   `json:"endDate" example:"2026-06-16"`
+	Days        int                      `json:"days" example:"5"`
+	Activities  []models.PackingActivity `json:"activities"` // Casual every day if empty
+	Weather     *models.TripWeather      `json:"weather"`    // Leave out if unknown
+}
+
+type UpdatePackingItemRequest struct {
+	Packed *bool `json:"packed" binding:"required"`
+}
+
+// PackingListView is a packing list with its items filled in
+type PackingListView struct {
+	models.PackingList
+	// Details of the items still in the closet, in checklist order
+	Clothing []SharedItem `json:"clothing"`
+	Packed   int          `json:"packed"`
+	Total    int          `json:"total"`
+}
+
+// tripDays lays out the trip's days and the occasions planned on each.
+// An activity on fewer days than the trip lasts is spread out evenly;
+// days with nothing planned are casual.
+func tripDays(req CreatePackingListRequest) (time.Time, []models.PackingActivity, []models.PackingDay, error) {
+	start, err := time.Parse(borrowDateLayout, req.StartDate)
+	if err != nil {
+		return time.Time{}, nil, nil, errors.New("startDate must be a YYYY-MM-DD date")
+	}
+	n := req.Days
+	if req.EndDate != "" {
+		end, err := time.Parse(borrowDateLayout, req.EndDate)
+		if err != nil {
+			return time.Time{}, nil, nil, errors.New("endDate must be a YYYY-MM-DD date")
+		}
+		if end.Before(start) {
+			return time.Time{}, nil, nil, errors.New("endDate can't be before startDate")
+		}
+		n = int(end.Sub(start).Hours()/24) + 1
+	}
+	if n < 1 {
+		return time.Time{}, nil, nil, errors.New("set endDate or days")
+	}
+	if n > maxTripDays {
+		return time.Time{}, nil, nil, fmt.Errorf("trips can be at most %d days", maxTripDays)
+	}
+
+	activities := []models.PackingActivity{}
+	for _, a := range req.Activities {
+		occasion, ok := taxonomy.Normalize(a.Occasion, taxonomy.Occasions)
+		if !ok {
+			return time.Time{}, nil, nil, fmt.Errorf("unknown occasion %q", a.Occasion)
+		}
+		if a.Days < 0 || a.Days > n {
+			return time.Time{}, nil, nil, fmt.Errorf("%s can be planned on at most %d days", occasion, n)
+		}
+		activities = append(activities, models.PackingActivity{Occasion: occasion, Days: a.Days})
+	}
+
+	days := make([]models.PackingDay, n)
+	for i := range days {
+		days[i] = models.PackingDay{Date: start.AddDate(0, 0, i), Occasions: []string{}}
+	}
+	for _, a := range activities {
+		count := a.Days
+		if count == 0 {
+			count = n
+		}
+		for k := 0; k < count; k++ {
+			day := &days[k*n/count]
+			if !slices.Contains(day.Occasions, a.Occasion) {
+				day.Occasions = append(day.Occasions, a.Occasion)
+			}
+		}
+	}
+	for i := range days {
+		if len(days[i].Occasions) == 0 {
+			days[i].Occasions = []string{capsule.DefaultOccasion}
+		}
+	}
+	return start, activities, days, nil
+}
+
+// packableItems loads the items that can come on a trip from start to end:
+// everything but items in need of repair or lent out during the trip
+func packableItems(ctx context.Context, userID string, start, end time.Time) ([]models.ClothingItem, error) {
+	cursor, err := database.GetCollection("borrow_requests").Find(ctx, bson.M{
+		"owner_id": userID,
+		"status":   bson.M{"$in": []string{models.BorrowApproved, models.BorrowActive}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var loans []models.BorrowRequest
+	if err := cursor.All(ctx, &loans); err != nil {
+		return nil, err
+	}
+	blocked := map[primitive.ObjectID]bool{}
+	returned := map[primitive.ObjectID]bool{} // Lent now, but back before the trip
+	for _, loan := range loans {
+		if !loan.StartDate.After(end) && !loan.EndDate.Before(start) {
+			blocked[loan.ItemID] = true
+		} else if loan.Status == models.BorrowActive && loan.EndDate.Before(start) {
+			returned[loan.ItemID] = true
+		}
+	}
+
+	cursor, err = database.GetCollection("clothing").Find(ctx,
+		bson.M{"user_id": userID, "availability": bson.M{"$ne": models.AvailabilityNeedsRepair}, "draft": bson.M{"$ne": true}},
+		options.Find().SetProjection(bson.M{"embedding": 0, "availability_history": 0}))
+	if err != nil {
+		return nil, err
+	}
+	var closet []models.ClothingItem
+	if err := cursor.All(ctx, &closet); err != nil {
+		return nil, err
+	}
+	items := []models.ClothingItem{}
+	for _, item := range closet {
+		if blocked[item.ID] || (item.Availability == models.AvailabilityLent && !returned[item.ID]) {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func packingListView(ctx context.Context, userID string, list models.PackingList) (PackingListView, error) {
+	ids := make([]primitive.ObjectID, len(list.Items))
+	view := PackingListView{PackingList: list, Total: len(list.Items)}
+	for i, item := range list.Items {
+		ids[i] = item.ItemID
+		if item.Packed {
+			view.Packed++
+		}
+	}
+	clothing, err := sharedItems(ctx, userID, ids)
+	if err != nil {
+		return PackingListView{}, err
+	}
+	view.Clothing = clothing
+	return view, nil
+}
+
+// @Summary Generate a packing list
+// @Description Pick a minimal capsule from the closet for a trip: the fewest items that still give each planned activity (a taxonomy occasion) a different outfit every day it comes up, suited to the expected weather. Items needing repair or lent out during the trip are left out. The result is saved as a checklist with an outfit for each day.
+// @Tags packing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreatePackingListRequest true "Trip dates, activities and weather"
+// @Success 201 {object} handlers.PackingListView
+// @Failure 400 {object} map[string]string "Invalid request body, dates, activities or weather"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /packing-lists [post]
+func CreatePackingListHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req CreatePackingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	start, activities, days, err := tripDays(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Weather != nil && req.Weather.MaxTemp < req.Weather.MinTemp {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "weather.maxTemp can't be below weather.minTemp"})
+		return
+	}
+	end := days[len(days)-1].Date
+
+	destination := strings.TrimSpace(req.Destination)
+	name := strings.TrimSpace(req.Name)
+	if name == "" && destination != "" {
+		name = "Trip to " + destination
+	} else if name == "" {
+		name = "Trip on " + start.Format(borrowDateLayout)
+	}
+
+	ctx := c.Request.Context()
+	closet, err := packableItems(ctx, userID, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+	packing := capsule.Pack(closet, days, req.Weather)
+
+	now := time.Now()
+	list := models.PackingList{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		Name:         name,
+		Destination:  destination,
+		StartDate:    start,
+		EndDate:      end,
+		Activities:   activities,
+		Weather:      req.Weather,
+		Items:        make([]models.PackingItem, len(packing.Items)),
+		Days:         packing.Days,
+		Combinations: packing.Combinations,
+		Uncovered:    packing.Uncovered,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	for i, item := range packing.Items {
+		list.Items[i] = models.PackingItem{
+			ItemID:   item.ID,
+			Name:     item.Name,
+			Category: item.Category,
+			Note:     packingNotes[item.Availability],
+		}
+	}
+	if _, err := database.GetCollection("packing_lists").InsertOne(ctx, list); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save packing list"})
+		return
+	}
+
+	view, err := packingListView(ctx, userID, list)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+	c.JSON(http.StatusCreated, view)
+}
+
+// @Summary List packing lists
+// @Description The user's packing lists, latest trip first
+// @Tags packing
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.PackingList
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /packing-lists [get]
+func ListPackingListsHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	ctx := c.Request.Context()
+	cursor, err := database.GetCollection("packing_lists").Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "start_date", Value: -1}, {Key: "created_at", Value: -1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch packing lists"})
+		return
+	}
+	lists := []models.PackingList{}
+	if err := cursor.All(ctx, &lists); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode packing lists"})
+		return
+	}
+
+	c.JSON(http.StatusOK, lists)
+}
+
+// @Summary Get a packing list
+// @Description A packing list with its checklist progress and the details of its items
+// @Tags packing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Packing list ID"
+// @Success 200 {object} handlers.PackingListView
+// @Failure 400 {object} map[string]string "Invalid packing list ID"
+// @Failure 404 {object} map[string]string "Packing list not found"
+// @Router /packing-lists/{id} [get]
+func GetPackingListHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid packing list ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var list models.PackingList
+	err = database.GetCollection("packing_lists").FindOne(ctx, bson.M{"_id": objectID, "user_id": userID}).Decode(&list)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Packing list not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch packing list"})
+		return
+	}
+
+	view, err := packingListView(ctx, userID, list)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// @Summary Tick off a packing list item
+// @Description Mark an item on the checklist as packed, or not packed
+// @Tags packing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Packing list ID"
+// @Param itemId path string true "Clothing item ID"
+// @Param request body UpdatePackingItemRequest true "Packed or not"
+// @Success 200 {object} handlers.PackingListView
+// @Failure 400 {object} map[string]string "Invalid request body or ID"
+// @Failure 404 {object} map[string]string "Packing list or item not found"
+// @Router /packing-lists/{id}/items/{itemId} [patch]
+func UpdatePackingItemHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid packing list ID"})
+		return
+	}
+	itemID, err := primitive.ObjectIDFromHex(c.Param("itemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clothing ID"})
+		return
+	}
+
+	var req UpdatePackingItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"items.$.packed": true, "items.$.packed_at": now, "updated_at": now}}
+	if !*req.Packed {
+		update = bson.M{"$set": bson.M{"items.$.packed": false, "updated_at": now}, "$unset": bson.M{"items.$.packed_at": ""}}
+	}
+
+	ctx := c.Request.Context()
+	var list models.PackingList
+	err = database.GetCollection("packing_lists").FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, "user_id": userID, "items.item_id": itemID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&list)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Packing list or item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update packing list"})
+		return
+	}
+
+	view, err := packingListView(ctx, userID, list)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// @Summary Delete a packing list
+// @Description Delete one of the user's packing lists. The clothing items are kept.
+// @Tags packing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Packing list ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid packing list ID"
+// @Failure 404 {object} map[string]string "Packing list not found"
+// @Router /packing-lists/{id} [delete]
+func DeletePackingListHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid packing list ID"})
+		return
+	}
+
+	result, err := database.GetCollection("packing_lists").DeleteOne(c.Request.Context(), bson.M{"_id": objectID, "user_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete packing list"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Packing list not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Packing list deleted"})
+}
@@ -0,0 +1,715 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/ical"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	plannerDefaultDays = 7
+	plannerMaxDays     = 92
+	maxPlanNotesLength = 500
+
+	// The calendar feed covers this window around today
+	calendarFeedPastDays   = 30
+	calendarFeedFutureDays = 365
+)
+
+// Planner warning kinds
+const (
+	PlanWarnConsecutive = "consecutive_days" // Same item planned two days running
+	PlanWarnUnavailable = "unavailable"      // Item is in the laundry, at the cleaner, etc.
+	PlanWarnLent        = "lent"             // Item is lent out on that day
+	PlanWarnMissing     = "missing"          // Outfit or item has been deleted
+)
+
+type CreatePlanEntryRequest struct {
+	Date     string   `json:"date" binding:"required" example:"2026-05-01"`
+	OutfitID string   `json:"outfitId"` // Either an outfit...
+	ItemIDs  []string `json:"itemIds"`  // ...or an ad-hoc list of items
+	Notes    string   `json:"notes"`
+}
+
+// UpdatePlanEntryRequest changes only the fields that are sent. Setting
+// outfitId clears itemIds and vice versa.
+type UpdatePlanEntryRequest struct {
+	Date     *string  `json:"date" example:"2026-05-01"`
+	OutfitID *string  `json:"outfitId"`
+	ItemIDs  []string `json:"itemIds"`
+	Notes    *string  `json:"notes"`
+}
+
+type PlanWarning struct {
+	Kind     string `json:"kind"`
+	Date     string `json:"date"`
+	EntryID  string `json:"entryId"`
+	ItemID   string `json:"itemId,omitempty"`
+	ItemName string `json:"itemName,omitempty"`
+	// For consecutive_days, the entry on the day before
+	OtherEntryID string `json:"otherEntryId,omitempty"`
+	Message      string `json:"message"`
+}
+
+// PlanEntryView is an entry with its outfit's name and items filled in
+type PlanEntryView struct {
+	models.PlanEntry
+	OutfitName string       `json:"outfitName,omitempty"`
+	Items      []SharedItem `json:"items"`
+}
+
+type PlannerResponse struct {
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+	Entries  []PlanEntryView `json:"entries"`
+	Warnings []PlanWarning   `json:"warnings"`
+}
+
+type PlanEntryResponse struct {
+	Entry    PlanEntryView `json:"entry"`
+	Warnings []PlanWarning `json:"warnings"`
+}
+
+type CalendarFeedResponse struct {
+	URL string `json:"url"` // Subscribe to this in a calendar app; anyone with it can see your plans
+}
+
+func parsePlanDate(value string) (time.Time, error) {
+	date, err := time.Parse(borrowDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a YYYY-MM-DD date", value)
+	}
+	return date, nil
+}
+
+// planEntryContent resolves what an entry's content should be: an owned outfit or owned items
+func planEntryContent(ctx context.Context, userID, outfitID string, itemIDs []string) (*primitive.ObjectID, []primitive.ObjectID, error) {
+	switch {
+	case outfitID != "" && len(itemIDs) > 0:
+		return nil, nil, errors.New("set either outfitId or itemIds, not both")
+	case outfitID != "":
+		objectID, err := primitive.ObjectIDFromHex(outfitID)
+		if err != nil {
+			return nil, nil, errors.New("invalid outfit ID")
+		}
+		count, err := database.GetCollection("outfits").CountDocuments(ctx, bson.M{"_id": objectID, "user_id": userID})
+		if err != nil {
+			return nil, nil, err
+		}
+		if count == 0 {
+			return nil, nil, errors.New("outfit not found")
+		}
+		return &objectID, nil, nil
+	case len(itemIDs) > 0:
+		ids, err := ownedItemIDs(ctx, userID, itemIDs)
+		return nil, ids, err
+	}
+	return nil, nil, errors.New("set outfitId or itemIds")
+}
+
+// findPlanEntries loads the user's entries on days from..to, inclusive, in date order
+func findPlanEntries(ctx context.Context, userID string, from, to time.Time) ([]models.PlanEntry, error) {
+	cursor, err := database.GetCollection("plan_entries").Find(ctx,
+		bson.M{"user_id": userID, "date": bson.M{"$gte": from, "$lte": to}},
+		options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	entries := []models.PlanEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// planViews fills in entries and works out their warnings. Warnings are
+// checked across all of entries, which should include the days either side
+// of the ones being shown so consecutive-day repeats at the edges are found.
+func planViews(ctx context.Context, userID string, entries []models.PlanEntry) ([]PlanEntryView, []PlanWarning, error) {
+	views := make([]PlanEntryView, len(entries))
+	warnings := []PlanWarning{}
+	if len(entries) == 0 {
+		return views, warnings, nil
+	}
+
+	var outfitIDs []primitive.ObjectID
+	for _, e := range entries {
+		if e.OutfitID != nil {
+			outfitIDs = append(outfitIDs, *e.OutfitID)
+		}
+	}
+	outfits := map[primitive.ObjectID]models.Outfit{}
+	if len(outfitIDs) > 0 {
+		cursor, err := database.GetCollection("outfits").Find(ctx, bson.M{"_id": bson.M{"$in": outfitIDs}, "user_id": userID})
+		if err != nil {
+			return nil, nil, err
+		}
+		var found []models.Outfit
+		if err := cursor.All(ctx, &found); err != nil {
+			return nil, nil, err
+		}
+		for _, o := range found {
+			outfits[o.ID] = o
+		}
+	}
+
+	// Each entry's items, whether planned directly or through an outfit
+	entryItems := make([][]primitive.ObjectID, len(entries))
+	var allItems []primitive.ObjectID
+	for i, e := range entries {
+		entryItems[i] = e.ItemIDs
+		if e.OutfitID != nil {
+			entryItems[i] = outfits[*e.OutfitID].ItemIDs
+		}
+		allItems = append(allItems, entryItems[i]...)
+	}
+
+	cursor, err := database.GetCollection("clothing").Find(ctx, bson.M{"_id": bson.M{"$in": allItems}, "user_id": userID},
+		options.Find().SetProjection(bson.M{"embedding": 0, "availability_history": 0}))
+	if err != nil {
+		return nil, nil, err
+	}
+	var found []models.ClothingItem
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, nil, err
+	}
+	items := map[primitive.ObjectID]models.ClothingItem{}
+	for _, item := range found {
+		items[item.ID] = item
+	}
+
+	// Loans of the user's items that touch the planned days
+	first, last := entries[0].Date, entries[len(entries)-1].Date
+	cursor, err = database.GetCollection("borrow_requests").Find(ctx, bson.M{
+		"owner_id":   userID,
+		"item_id":    bson.M{"$in": allItems},
+		"status":     bson.M{"$in": []string{models.BorrowApproved, models.BorrowActive}},
+		"start_date": bson.M{"$lte": last},
+		"end_date":   bson.M{"$gte": first},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var loans []models.BorrowRequest
+	if err := cursor.All(ctx, &loans); err != nil {
+		return nil, nil, err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	byDay := map[time.Time]map[primitive.ObjectID]string{} // day -> item -> entry ID
+	for i, e := range entries {
+		day := e.Date.UTC()
+		if byDay[day] == nil {
+			byDay[day] = map[primitive.ObjectID]string{}
+		}
+		for _, id := range entryItems[i] {
+			byDay[day][id] = e.ID.Hex()
+		}
+	}
+
+	for i, e := range entries {
+		date := e.Date.UTC().Format(borrowDateLayout)
+		views[i] = PlanEntryView{PlanEntry: e, Items: []SharedItem{}}
+		warn := func(kind string, item *models.ClothingItem, message string) *PlanWarning {
+			w := PlanWarning{Kind: kind, Date: date, EntryID: e.ID.Hex(), Message: message}
+			if item != nil {
+				w.ItemID = item.ID.Hex()
+				w.ItemName = item.Name
+			}
+			warnings = append(warnings, w)
+			return &warnings[len(warnings)-1]
+		}
+
+		if e.OutfitID != nil {
+			outfit, ok := outfits[*e.OutfitID]
+			if !ok {
+				warn(PlanWarnMissing, nil, "The planned outfit has been deleted")
+				continue
+			}
+			views[i].OutfitName = outfit.Name
+		}
+
+		missing := 0
+		for _, id := range entryItems[i] {
+			item, ok := items[id]
+			if !ok {
+				missing++
+				continue
+			}
+			views[i].Items = append(views[i].Items, sharedItem(item))
+
+			if otherID, ok := byDay[e.Date.UTC().AddDate(0, 0, -1)][id]; ok {
+				w := warn(PlanWarnConsecutive, &item, item.Name+" is also planned the day before")
+				w.OtherEntryID = otherID
+			}
+
+			lent := false
+			for _, loan := range loans {
+				if loan.ItemID == id && !e.Date.Before(loan.StartDate) && !e.Date.After(loan.EndDate) {
+					lent = true
+					warn(PlanWarnLent, &item, item.Name+" is lent out that day")
+					break
+				}
+			}
+			// Loans are checked by date above; other statuses are only known for now
+			if !lent && !e.Date.Before(today) && !item.IsAvailable() && item.Availability != models.AvailabilityLent {
+				warn(PlanWarnUnavailable, &item, item.Name+" is currently "+strings.ReplaceAll(item.Availability, "_", " "))
+			}
+		}
+		if missing > 0 {
+			warn(PlanWarnMissing, nil, fmt.Sprintf("%d planned item(s) have been deleted", missing))
+		}
+	}
+	return views, warnings, nil
+}
+
+// planEntryResponse loads the days around an entry and reports the warnings involving it
+func planEntryResponse(ctx context.Context, userID string, entry models.PlanEntry) (PlanEntryResponse, error) {
+	entries, err := findPlanEntries(ctx, userID, entry.Date.AddDate(0, 0, -1), entry.Date.AddDate(0, 0, 1))
+	if err != nil {
+		return PlanEntryResponse{}, err
+	}
+	views, warnings, err := planViews(ctx, userID, entries)
+	if err != nil {
+		return PlanEntryResponse{}, err
+	}
+
+	response := PlanEntryResponse{Warnings: []PlanWarning{}}
+	for _, v := range views {
+		if v.ID == entry.ID {
+			response.Entry = v
+		}
+	}
+	id := entry.ID.Hex()
+	for _, w := range warnings {
+		if w.EntryID == id || w.OtherEntryID == id {
+			response.Warnings = append(response.Warnings, w)
+		}
+	}
+	return response, nil
+}
+
+// @Summary Plan an outfit
+// @Description Put an outfit, or an ad-hoc list of items, on a day. The response warns if an item is also planned the day before or after, is lent out that day, or is currently unavailable.
+// @Tags planner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreatePlanEntryRequest true "Day and outfit or items"
+// @Success 201 {object} handlers.PlanEntryResponse
+// @Failure 400 {object} map[string]string "Invalid request body, date, outfit or items"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /planner [post]
+func CreatePlanEntryHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req CreatePlanEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	date, err := parsePlanDate(req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	notes := strings.TrimSpace(req.Notes)
+	if len([]rune(notes)) > maxPlanNotesLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Notes can be at most 500 characters"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	outfitID, itemIDs, err := planEntryContent(ctx, userID, req.OutfitID, req.ItemIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	entry := models.PlanEntry{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Date:      date,
+		OutfitID:  outfitID,
+		ItemIDs:   itemIDs,
+		Notes:     notes,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := database.GetCollection("plan_entries").InsertOne(ctx, entry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save plan"})
+		return
+	}
+
+	response, err := planEntryResponse(ctx, userID, entry)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check plan"})
+		return
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// @Summary Get the planner
+// @Description Planned outfits for a range of days (a week from today by default, at most 92 days), with warnings about repeats on consecutive days and unavailable or lent items
+// @Tags planner
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First day, YYYY-MM-DD (default today)"
+// @Param to query string false "Last day, YYYY-MM-DD (default a week from from)"
+// @Success 200 {object} handlers.PlannerResponse
+// @Failure 400 {object} map[string]string "Invalid range"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /planner [get]
+func GetPlannerHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	if raw := c.Query("from"); raw != "" {
+		date, err := parsePlanDate(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		from = date
+	}
+	to := from.AddDate(0, 0, plannerDefaultDays-1)
+	if raw := c.Query("to"); raw != "" {
+		date, err := parsePlanDate(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		to = date
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to can't be before from"})
+		return
+	}
+	if to.Sub(from) >= plannerMaxDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The range can be at most 92 days"})
+		return
+	}
+
+	// One extra day each side so repeats across the edges are caught
+	ctx := c.Request.Context()
+	entries, err := findPlanEntries(ctx, userID, from.AddDate(0, 0, -1), to.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plans"})
+		return
+	}
+	views, warnings, err := planViews(ctx, userID, entries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plans"})
+		return
+	}
+
+	response := PlannerResponse{
+		From:     from.Format(borrowDateLayout),
+		To:       to.Format(borrowDateLayout),
+		Entries:  []PlanEntryView{},
+		Warnings: []PlanWarning{},
+	}
+	inRange := map[string]bool{}
+	for _, v := range views {
+		if !v.Date.Before(from) && !v.Date.After(to) {
+			response.Entries = append(response.Entries, v)
+			inRange[v.ID.Hex()] = true
+		}
+	}
+	for _, w := range warnings {
+		if inRange[w.EntryID] {
+			response.Warnings = append(response.Warnings, w)
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Update a planned outfit
+// @Description Move an entry to another day, change what's planned or edit its notes
+// @Tags planner
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Plan entry ID"
+// @Param request body UpdatePlanEntryRequest true "Fields to change"
+// @Success 200 {object} handlers.PlanEntryResponse
+// @Failure 400 {object} map[string]string "Invalid request body, date, outfit or items"
+// @Failure 404 {object} map[string]string "Plan entry not found"
+// @Router /planner/{id} [patch]
+func UpdatePlanEntryHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid plan entry ID"})
+		return
+	}
+	var req UpdatePlanEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	set := bson.M{"updated_at": time.Now()}
+	unset := bson.M{}
+	if req.Date != nil {
+		date, err := parsePlanDate(*req.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		set["date"] = date
+	}
+	if req.OutfitID != nil || req.ItemIDs != nil {
+		outfitID := ""
+		if req.OutfitID != nil {
+			outfitID = *req.OutfitID
+		}
+		newOutfit, newItems, err := planEntryContent(ctx, userID, outfitID, req.ItemIDs)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if newOutfit != nil {
+			set["outfit_id"] = *newOutfit
+			unset["item_ids"] = ""
+		} else {
+			set["item_ids"] = newItems
+			unset["outfit_id"] = ""
+		}
+	}
+	if req.Notes != nil {
+		notes := strings.TrimSpace(*req.Notes)
+		if len([]rune(notes)) > maxPlanNotesLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Notes can be at most 500 characters"})
+			return
+		}
+		set["notes"] = notes
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	var entry models.PlanEntry
+	err = database.GetCollection("plan_entries").FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID, "user_id": userID}, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plan entry not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update plan"})
+		return
+	}
+
+	response, err := planEntryResponse(ctx, userID, entry)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check plan"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Delete a planned outfit
+// @Tags planner
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Plan entry ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid plan entry ID"
+// @Failure 404 {object} map[string]string "Plan entry not found"
+// @Router /planner/{id} [delete]
+func DeletePlanEntryHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid plan entry ID"})
+		return
+	}
+
+	result, err := database.GetCollection("plan_entries").DeleteOne(c.Request.Context(), bson.M{"_id": objectID, "user_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete plan"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Plan entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Plan entry deleted"})
+}
+
+// @Summary Turn on the calendar feed
+// @Description Create a secret iCalendar (.ics) URL for the planner that calendar apps can subscribe to. Calling this again replaces the URL; the old one stops working.
+// @Tags planner
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} handlers.CalendarFeedResponse
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /planner/calendar-feed [post]
+func CreateCalendarFeedHandler(c *gin.Context) {
+	user, ok := loadUser(c)
+	if !ok {
+		return
+	}
+
+	secret, err := newRefreshSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create feed"})
+		return
+	}
+	_, err = database.GetCollection("users").UpdateOne(c.Request.Context(), bson.M{"_id": user.ID},
+		bson.M{"$set": bson.M{"calendar_feed_hash": hashToken(secret)}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, CalendarFeedResponse{URL: apiBaseURL(c) + "/calendar/" + secret + ".ics"})
+}
+
+// @Summary Turn off the calendar feed
+// @Tags planner
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /planner/calendar-feed [delete]
+func DeleteCalendarFeedHandler(c *gin.Context) {
+	user, ok := loadUser(c)
+	if !ok {
+		return
+	}
+
+	_, err := database.GetCollection("users").UpdateOne(c.Request.Context(), bson.M{"_id": user.ID},
+		bson.M{"$unset": bson.M{"calendar_feed_hash": ""}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to turn off feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Calendar feed turned off"})
+}
+
+// @Summary Planner calendar feed
+// @Description iCalendar feed of planned outfits from 30 days ago to a year ahead, one all-day event per entry. The URL comes from POST /planner/calendar-feed; no other authentication is needed.
+// @Tags planner
+// @Produce text/calendar
+// @Param token path string true "Feed secret, optionally followed by .ics"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 404 {object} map[string]string "Feed not found"
+// @Router /calendar/{token} [get]
+func CalendarFeedHandler(c *gin.Context) {
+	secret := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	ctx := c.Request.Context()
+	var user models.User
+	err := database.GetCollection("users").FindOne(ctx, bson.M{
+		"calendar_feed_hash": hashToken(secret),
+		"disabled":           bson.M{"$ne": true},
+	}).Decode(&user)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		return
+	}
+
+	userID := user.ID.Hex()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	entries, err := findPlanEntries(ctx, userID, today.AddDate(0, 0, -calendarFeedPastDays), today.AddDate(0, 0, calendarFeedFutureDays))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plans"})
+		return
+	}
+	views, warnings, err := planViews(ctx, userID, entries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch plans"})
+		return
+	}
+	warningsByEntry := map[string][]string{}
+	for _, w := range warnings {
+		warningsByEntry[w.EntryID] = append(warningsByEntry[w.EntryID], w.Message)
+	}
+
+	cal := ical.Calendar{Name: "Armoire outfits"}
+	for _, v := range views {
+		names := make([]string, len(v.Items))
+		for i, item := range v.Items {
+			names[i] = item.Name
+		}
+		sort.Strings(names)
+
+		summary := v.OutfitName
+		if summary == "" {
+			summary = strings.Join(names, ", ")
+		}
+		if summary == "" {
+			summary = "Planned outfit"
+		}
+		var description []string
+		if v.Notes != "" {
+			description = append(description, v.Notes)
+		}
+		if len(names) > 0 {
+			description = append(description, "Wearing: "+strings.Join(names, ", "))
+		}
+		for _, message := range warningsByEntry[v.ID.Hex()] {
+			description = append(description, "⚠ "+message)
+		}
+
+		cal.Events = append(cal.Events, ical.Event{
+			UID:         v.ID.Hex() + "@armoire",
+			Date:        v.Date.UTC(),
+			Summary:     summary,
+			Description: strings.Join(description, "\n"),
+			URL:         appBaseURL() + "/planner?date=" + v.Date.UTC().Format(borrowDateLayout),
+			Updated:     v.UpdatedAt,
+		})
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", `inline; filename="armoire.ics"`)
+	c.Status(http.StatusOK)
+	if err := cal.Write(c.Writer); err != nil {
+		fmt.Printf("Warning: Failed to write calendar feed for %s: %v\n", userID, err)
+	}
+}
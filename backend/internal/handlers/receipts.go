@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/ai"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/notify"
+	"github.com/exply/armoire/internal/receipt"
+	"github.com/exply/armoire/internal/storage"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxReceiptBytes caps a pasted email or uploaded receipt
+const maxReceiptBytes = 10 << 20
+
+// ReceiptImportResponse lists the drafts created from a receipt
+type ReceiptImportResponse struct {
+	Merchant     string                `json:"merchant,omitempty"`
+	PurchaseDate *time.Time            `json:"purchaseDate,omitempty"`
+	Items        []models.ClothingItem `json:"items"`
+}
+
+// receiptInput reads the pasted email or uploaded file from the form. Photos
+// and PDFs are returned as data for Gemini to read; emails as their text.
+func receiptInput(c *gin.Context) (text string, sentAt time.Time, data []byte, mimeType string, ok bool) {
+	var raw []byte
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		if fileHeader.Size > maxReceiptBytes {
+			return "", time.Time{}, nil, "", false
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return "", time.Time{}, nil, "", false
+		}
+		defer file.Close()
+		if raw, err = io.ReadAll(file); err != nil {
+			return "", time.Time{}, nil, "", false
+		}
+
+		mimeType = http.DetectContentType(raw)
+		if strings.HasPrefix(mimeType, "image/") || mimeType == "application/pdf" {
+			return "", time.Time{}, raw, mimeType, true
+		}
+	} else {
+		raw = []byte(c.PostForm("email"))
+	}
+	if len(raw) == 0 || len(raw) > maxReceiptBytes {
+		return "", time.Time{}, nil, "", false
+	}
+
+	email, err := receipt.Read(raw)
+	if err != nil || strings.TrimSpace(email.Text) == "" {
+		return "", time.Time{}, nil, "", false
+	}
+	text = email.Text
+	if email.Subject != "" {
+		text = "Subject: " + email.Subject + "\n\n" + text
+	}
+	return text, email.Date, nil, "", true
+}
+
+// @Summary Create draft items from a receipt
+// @Description Read an order confirmation email (pasted as text or HTML, or uploaded as .eml, .html or .txt) or a receipt photo or PDF, extract the clothing bought with AI, and create a draft closet item per piece, pre-filled with name, brand, size, color, price and purchase date. Drafts have no photo or AI tags until one is added with POST /clothing/{id}/photo.
+// @Tags clothing
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param email formData string false "Pasted order email: plain text, HTML or a raw message"
+// @Param file formData file false "Order email (.eml, .html, .txt), receipt photo or PDF (max 10MB); used instead of email"
+// @Success 201 {object} handlers.ReceiptImportResponse
+// @Failure 400 {object} map[string]string "No readable email or receipt"
+// @Failure 401 {object} map[string]string "User ID not found in context"
+// @Failure 422 {object} map[string]string "No clothing found on the receipt"
+// @Failure 500 {object} map[string]string "Failed to read the receipt / Database Save Failed"
+// @Router /clothing/receipts [post]
+func ImportReceiptHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	text, sentAt, data, mimeType, ok := receiptInput(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Send an order email as email, or an email, receipt photo or PDF as file (max 10MB)"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	aiClient, err := ai.NewAIClient(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "AI client unavailable"})
+		return
+	}
+	parsed, err := aiClient.ParseReceipt(ctx, text, data, mimeType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read the receipt: " + err.Error()})
+		return
+	}
+	if len(parsed.Items) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No clothing found on the receipt"})
+		return
+	}
+
+	// Fall back to when the email was sent if the order date isn't in it
+	purchaseDate := parsed.PurchaseDate
+	if purchaseDate == nil && !sentAt.IsZero() {
+		day := time.Date(sentAt.Year(), sentAt.Month(), sentAt.Day(), 0, 0, 0, 0, time.UTC)
+		purchaseDate = &day
+	}
+
+	now := time.Now()
+	drafts := []models.ClothingItem{}
+	docs := []interface{}{}
+	for _, line := range parsed.Items {
+		// Two of the same shirt are two items in the closet
+		for i := 0; i < line.Quantity; i++ {
+			draft := models.ClothingItem{
+				ID:           primitive.NewObjectID(),
+				UserID:       userID,
+				Name:         line.Name,
+				Category:     line.Category,
+				SubCategory:  line.SubCategory,
+				Colors:       line.Colors,
+				Seasons:      []string{},
+				Occasions:    []string{},
+				Materials:    []string{},
+				BrandText:    line.Brand,
+				SizeLabel:    line.Size,
+				Price:        line.Price,
+				Currency:     line.Currency,
+				PurchaseDate: purchaseDate,
+				Draft:        true,
+				Availability: models.AvailabilityAvailable,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+				IsPublic:     false,
+
+				AvailabilityChangedAt: now,
+			}
+			drafts = append(drafts, draft)
+			docs = append(docs, draft)
+		}
+	}
+
+	if _, err := database.GetCollection("clothing").InsertMany(ctx, docs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database Save Failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ReceiptImportResponse{
+		Merchant:     parsed.Merchant,
+		PurchaseDate: purchaseDate,
+		Items:        drafts,
+	})
+}
+
+// @Summary Add a photo to a draft item
+// @Description Complete a draft created from a receipt with its photo. The photo goes through the normal upload pipeline (background removal, thumbnail, AI tagging and embedding); the name, brand, size, price and purchase date from the receipt are kept, as are its category and colors if it had them.
+// @Tags clothing
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Clothing item ID"
+// @Param image formData file true "Clothing item image (max 10MB)"
+// @Success 200 {object} models.ClothingItem
+// @Failure 400 {object} map[string]string "Invalid clothing ID or file"
+// @Failure 401 {object} map[string]string "User ID not found in context"
+// @Failure 404 {object} map[string]string "Clothing item not found"
+// @Failure 409 {object} map[string]string "The item already has a photo"
+// @Failure 500 {object} map[string]string "Failed to upload to GCS / AI Analysis Failed / Vector Embedding Failed / Database Save Failed"
+// @Router /clothing/{id}/photo [post]
+func AddDraftPhotoHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clothing ID"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open file"})
+		return
+	}
+	defer file.Close()
+	originalBytes, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+		return
+	}
+
+	collection := database.GetCollection("clothing")
+	ctx := c.Request.Context()
+	filter := bson.M{"_id": objectID, "user_id": userID}
+
+	var draft models.ClothingItem
+	err = collection.FindOne(ctx, filter).Decode(&draft)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Clothing item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing item"})
+		return
+	}
+	if !draft.Draft {
+		c.JSON(http.StatusConflict, gin.H{"error": "The item already has a photo"})
+		return
+	}
+
+	gcsClient, _ := storage.NewStorageClient("armoire-bucket")
+	aiClient, _ := ai.NewAIClient(ctx)
+	image, err := processImage(ctx, gcsClient, aiClient, originalBytes, fileHeader.Filename)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// What the receipt said beats the AI's guesses from the photo
+	item := newClothingItem(userID, image)
+	item.ID = draft.ID
+	item.CreatedAt = draft.CreatedAt
+	item.Availability = draft.Availability
+	item.AvailabilityChangedAt = draft.AvailabilityChangedAt
+	item.AvailabilityHistory = draft.AvailabilityHistory
+	item.Name = draft.Name
+	if draft.Category != "" {
+		item.Category = draft.Category
+		item.SubCategory = draft.SubCategory
+	}
+	if len(draft.Colors) > 0 {
+		item.Colors = draft.Colors
+	}
+	if draft.BrandText != "" {
+		item.BrandText = draft.BrandText
+	}
+	if draft.SizeLabel != "" {
+		item.SizeLabel = draft.SizeLabel
+	}
+	item.Price = draft.Price
+	item.Currency = draft.Currency
+	item.SourceURL = draft.SourceURL
+	item.PurchaseDate = draft.PurchaseDate
+	item.IsPublic = draft.IsPublic
+	item.Engagement = draft.Engagement
+	item.LoanSeq = draft.LoanSeq
+
+	// Only replace it if it's still a draft, so two uploads can't both win
+	var updatedItem models.ClothingItem
+	err = collection.FindOneAndReplace(ctx, bson.M{"_id": objectID, "user_id": userID, "draft": true}, item,
+		options.FindOneAndReplace().SetReturnDocument(options.After)).Decode(&updatedItem)
+	if err == mongo.ErrNoDocuments {
+		deleteStoredImage(image.GCSURI, image.ThumbnailURL)
+		c.JSON(http.StatusConflict, gin.H{"error": "The item already has a photo"})
+		return
+	}
+	if err != nil {
+		deleteStoredImage(image.GCSURI, image.ThumbnailURL)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database Save Failed"})
+		return
+	}
+
+	notify.Send(ctx, models.Notification{
+		UserID:     userID,
+		Type:       models.NotifyUploadComplete,
+		TargetType: models.ShareItem,
+		TargetID:   updatedItem.ID.Hex(),
+		Text:       updatedItem.Name + " was added to your closet",
+	})
+
+	c.JSON(http.StatusOK, updatedItem)
+}
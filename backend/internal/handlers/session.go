@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/auth"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AccessTokenTTL  = 15 * time.Minute    // The frontend refreshes on 401
+	RefreshTokenTTL = 30 * 24 * time.Hour // Slides forward on every refresh
+)
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// TokenResponse is returned whenever a session is started or refreshed
+type TokenResponse struct {
+	Token        string `json:"token"`        // Short-lived access token (JWT)
+	RefreshToken string `json:"refreshToken"` // Single-use, rotated on every refresh
+	ExpiresIn    int    `json:"expiresIn"`    // Access token lifetime in seconds
+}
+
+// startSession records a new session for the user and issues its first token pair
+func startSession(c *gin.Context, userID string) (*TokenResponse, error) {
+	secret, err := newRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		ID:               primitive.NewObjectID(),
+		UserID:           userID,
+		RefreshTokenHash: hashToken(secret),
+		UserAgent:        c.Request.UserAgent(),
+		IP:               c.ClientIP(),
+		CreatedAt:        now,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(RefreshTokenTTL),
+	}
+
+	if _, err := database.GetCollection("sessions").InsertOne(c.Request.Context(), session); err != nil {
+		return nil, err
+	}
+
+	return issueTokens(session, secret)
+}
+
+func issueTokens(session models.Session, secret string) (*TokenResponse, error) {
+	token, err := auth.IssueAccessToken(session.UserID, session.ID.Hex(), AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenResponse{
+		Token:        token,
+		RefreshToken: session.ID.Hex() + "." + secret,
+		ExpiresIn:    int(AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func newRefreshSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken is what gets stored; the raw secret only ever lives on the client
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// revokeSessions revokes every still-active session matching filter
+func revokeSessions(ctx context.Context, filter bson.M) (int64, error) {
+	filter["revoked_at"] = bson.M{"$exists": false}
+	result, err := database.GetCollection("sessions").UpdateMany(ctx, filter, bson.M{
+		"$set": bson.M{"revoked_at": time.Now()},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// @Summary Refresh an access token
+// @Description Exchange a refresh token for a new access token. The refresh token is rotated; replaying an old one revokes the session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh token"
+// @Success 200 {object} handlers.TokenResponse
+// @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 401 {object} map[string]string "Invalid, expired or revoked refresh token"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /auth/refresh [post]
+func RefreshHandler(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	// Refresh tokens look like "<sessionID>.<secret>"
+	sessionHex, secret, found := strings.Cut(req.RefreshToken, ".")
+	sessionID, err := primitive.ObjectIDFromHex(sessionHex)
+	if !found || err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	collection := database.GetCollection("sessions")
+	ctx := c.Request.Context()
+
+	var session models.Session
+	if err := collection.FindOne(ctx, bson.M{"_id": sessionID}).Decode(&session); err != nil || !session.Active() {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked"})
+		return
+	}
+
+	presented := hashToken(secret)
+
+	if subtle.ConstantTimeCompare([]byte(presented), []byte(session.RefreshTokenHash)) != 1 {
+		// An already-rotated token being replayed means it was stolen; kill the session
+		if session.PreviousTokenHash != "" && subtle.ConstantTimeCompare([]byte(presented), []byte(session.PreviousTokenHash)) == 1 {
+			revokeSessions(ctx, bson.M{"_id": session.ID})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected, session revoked"})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	newSecret, err := newRefreshSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	// Filtering on the current hash makes rotation atomic if two refreshes race
+	now := time.Now()
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"_id": session.ID, "refresh_token_hash": session.RefreshTokenHash},
+		bson.M{"$set": bson.M{
+			"refresh_token_hash":  hashToken(newSecret),
+			"previous_token_hash": session.RefreshTokenHash,
+			"last_used_at":        now,
+			"expires_at":          now.Add(RefreshTokenTTL),
+		}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not refresh session"})
+		return
+	}
+	if result.ModifiedCount == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	tokens, err := issueTokens(session, newSecret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Log out
+// @Description Revoke the current session so its access and refresh tokens stop working
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /auth/logout [post]
+func LogoutHandler(c *gin.Context) {
+	sessionIDVal, exists := c.Get("sessionID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Session not found in context"})
+		return
+	}
+	sessionID, _ := primitive.ObjectIDFromHex(sessionIDVal.(string))
+
+	if _, err := revokeSessions(c.Request.Context(), bson.M{"_id": sessionID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// @Summary Log out of all devices
+// @Description Revoke every session of the authenticated user, including the current one
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{} "Number of sessions revoked"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /auth/logout-all [post]
+func LogoutAllHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	revoked, err := revokeSessions(c.Request.Context(), bson.M{"user_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out of all devices", "revoked": revoked})
+}
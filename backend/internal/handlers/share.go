@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CreateShareLinkRequest struct {
+	ResourceType string     `json:"resourceType" binding:"required"` // item, outfit or collection
+	ResourceID   string     `json:"resourceId" binding:"required"`
+	ExpiresAt    *time.Time `json:"expiresAt"`                // Omit for a link that never expires
+	MaxViews     int        `json:"maxViews" binding:"min=0"` // 0 for unlimited
+}
+
+// ShareLinkResponse is only returned when a link is created; the token can't be recovered later
+type ShareLinkResponse struct {
+	models.ShareLink
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
+
+// SharedItem is the public view of a clothing item: no owner, storage, care or availability details
+type SharedItem struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Category     string   `json:"category"`
+	SubCategory  string   `json:"subCategory"`
+	Description  string   `json:"description"`
+	Colors       []string `json:"colors"`
+	Seasons      []string `json:"seasons"`
+	Occasions    []string `json:"occasions"`
+	Materials    []string `json:"materials"`
+	Pattern      string   `json:"pattern"`
+	Fit          string   `json:"fit"`
+	Neckline     string   `json:"neckline"`
+	SleeveLength string   `json:"sleeveLength"`
+	Brand        string   `json:"brand"`
+	ImageURL     string   `json:"imageUrl"`
+	ThumbnailURL string   `json:"thumbnailUrl"`
+	models.Engagement
+}
+
+type SharedOutfit struct {
+	ID       string       `json:"id,omitempty"`
+	Name     string       `json:"name"`
+	VibeTags []string     `json:"vibeTags"`
+	Items    []SharedItem `json:"items"`
+	models.Engagement
+}
+
+type SharedCollection struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Items       []SharedItem `json:"items"`
+}
+
+// SharedView is what anyone holding a share link sees; exactly one of Item, Outfit and Collection is set
+type SharedView struct {
+	ResourceType string            `json:"resourceType"`
+	OwnerName    string            `json:"ownerName"`
+	Item         *SharedItem       `json:"item,omitempty"`
+	Outfit       *SharedOutfit     `json:"outfit,omitempty"`
+	Collection   *SharedCollection `json:"collection,omitempty"`
+	Views        int               `json:"views"`
+	MaxViews     int               `json:"maxViews"`
+	ExpiresAt    *time.Time        `json:"expiresAt,omitempty"`
+}
+
+// shareCollections maps a resource type to the collection it lives in
+var shareCollections = map[string]string{
+	models.ShareItem:       "clothing",
+	models.ShareOutfit:     "outfits",
+	models.ShareCollection: "collections",
+}
+
+func sharedItem(item models.ClothingItem) SharedItem {
+	return SharedItem{
+		ID:           item.ID.Hex(),
+		Name:         item.Name,
+		Category:     item.Category,
+		SubCategory:  item.SubCategory,
+		Description:  item.Description,
+		Colors:       item.Colors,
+		Seasons:      item.Seasons,
+		Occasions:    item.Occasions,
+		Materials:    item.Materials,
+		Pattern:      item.Pattern,
+		Fit:          item.Fit,
+		Neckline:     item.Neckline,
+		SleeveLength: item.SleeveLength,
+		Brand:        item.BrandText,
+		ImageURL:     item.ImageURL,
+		ThumbnailURL: item.ThumbnailURL,
+		Engagement:   item.Engagement,
+	}
+}
+
+// sharedItems loads the owner's items in ids order, skipping any that were deleted
+func sharedItems(ctx context.Context, userID string, ids []primitive.ObjectID) ([]SharedItem, error) {
+	return findSharedItems(ctx, bson.M{"_id": bson.M{"$in": ids}, "user_id": userID}, ids)
+}
+
+// publicItems is sharedItems without the items the owner keeps private
+func publicItems(ctx context.Context, userID string, ids []primitive.ObjectID) ([]SharedItem, error) {
+	return findSharedItems(ctx, bson.M{"_id": bson.M{"$in": ids}, "user_id": userID, "is_public": true}, ids)
+}
+
+func findSharedItems(ctx context.Context, filter bson.M, ids []primitive.ObjectID) ([]SharedItem, error) {
+	cursor, err := database.GetCollection("clothing").Find(ctx, filter,
+		options.Find().SetProjection(bson.M{"embedding": 0}))
+	if err != nil {
+		return nil, err
+	}
+	var items []models.ClothingItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	byID := map[primitive.ObjectID]models.ClothingItem{}
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	result := []SharedItem{}
+	for _, id := range ids {
+		if item, ok := byID[id]; ok {
+			result = append(result, sharedItem(item))
+		}
+	}
+	return result, nil
+}
+
+// revokeShareLinks revokes every link to a resource, e.g. because it was deleted
+func revokeShareLinks(ctx context.Context, resourceType string, resourceID primitive.ObjectID) {
+	_, err := database.GetCollection("share_links").UpdateMany(ctx,
+		bson.M{"resource_type": resourceType, "resource_id": resourceID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		log.Printf("Could not revoke share links to %s %s: %v", resourceType, resourceID.Hex(), err)
+	}
+}
+
+// @Summary Create a share link
+// @Description Create an unguessable link that lets anyone view an item, outfit or collection without an account. The token is only shown in this response.
+// @Tags sharing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateShareLinkRequest true "What to share, and optional expiry and view limit"
+// @Success 201 {object} handlers.ShareLinkResponse
+// @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 404 {object} map[string]string "Resource not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /share-links [post]
+func CreateShareLinkHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	collectionName, ok := shareCollections[req.ResourceType]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "resourceType must be one of item, outfit, collection"})
+		return
+	}
+	resourceID, err := primitive.ObjectIDFromHex(req.ResourceID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expiresAt must be in the future"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	count, err := database.GetCollection(collectionName).CountDocuments(ctx, bson.M{"_id": resourceID, "user_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to look up " + req.ResourceType})
+		return
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+		return
+	}
+
+	token, err := newRefreshSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+
+	link := models.ShareLink{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		ResourceType: req.ResourceType,
+		ResourceID:   resourceID,
+		TokenHash:    hashToken(token),
+		CreatedAt:    time.Now(),
+		ExpiresAt:    req.ExpiresAt,
+		MaxViews:     req.MaxViews,
+	}
+	if _, err := database.GetCollection("share_links").InsertOne(ctx, link); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save share link"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ShareLinkResponse{
+		ShareLink: link,
+		Token:     token,
+		URL:       appBaseURL() + "/shared/" + token,
+	})
+}
+
+// @Summary List share links
+// @Description List the user's share links with their view counts, optionally for a single resource
+// @Tags sharing
+// @Produce json
+// @Security BearerAuth
+// @Param resourceType query string false "item, outfit or collection"
+// @Param resourceId query string false "Resource ID"
+// @Success 200 {array} models.ShareLink
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /share-links [get]
+func ListShareLinksHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	filter := bson.M{"user_id": userID}
+	if resourceType := c.Query("resourceType"); resourceType != "" {
+		if !slices.Contains(models.ShareResourceTypes, resourceType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "resourceType must be one of item, outfit, collection"})
+			return
+		}
+		filter["resource_type"] = resourceType
+	}
+	if resourceID := c.Query("resourceId"); resourceID != "" {
+		objectID, err := primitive.ObjectIDFromHex(resourceID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+			return
+		}
+		filter["resource_id"] = objectID
+	}
+
+	ctx := c.Request.Context()
+	cursor, err := database.GetCollection("share_links").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	links := []models.ShareLink{}
+	if err == nil {
+		err = cursor.All(ctx, &links)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch share links"})
+		return
+	}
+
+	c.JSON(http.StatusOK, links)
+}
+
+// @Summary Revoke a share link
+// @Description Stop a share link from working. The link and its view count are kept for the owner's records.
+// @Tags sharing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Share link ID"
+// @Success 200 {object} models.ShareLink
+// @Failure 400 {object} map[string]string "Invalid share link ID"
+// @Failure 404 {object} map[string]string "Share link not found"
+// @Router /share-links/{id} [delete]
+func RevokeShareLinkHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid share link ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	collection := database.GetCollection("share_links")
+	filter := bson.M{"_id": objectID, "user_id": userID}
+
+	// Revoking twice keeps the original revocation time
+	_, err = collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "user_id": userID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke share link"})
+		return
+	}
+
+	var link models.ShareLink
+	err = collection.FindOne(ctx, filter).Decode(&link)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Share link not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch share link"})
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// @Summary Open a share link
+// @Description Public. View the item, outfit or collection behind a share link. Each successful call counts as a view.
+// @Tags sharing
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} handlers.SharedView
+// @Failure 404 {object} map[string]string "Unknown link, or the shared resource was deleted"
+// @Failure 410 {object} map[string]string "Link expired, revoked or out of views"
+// @Router /shared/{token} [get]
+func GetSharedResourceHandler(c *gin.Context) {
+	ctx := c.Request.Context()
+	collection := database.GetCollection("share_links")
+	tokenHash := hashToken(c.Param("token"))
+	now := time.Now()
+
+	// Count the view only if the link is still usable, in one step so
+	// concurrent views can't push it past its limit
+	var link models.ShareLink
+	err := collection.FindOneAndUpdate(ctx,
+		bson.M{
+			"token_hash": tokenHash,
+			"revoked_at": bson.M{"$exists": false},
+			"$and": []bson.M{
+				{"$or": []bson.M{{"expires_at": bson.M{"$exists": false}}, {"expires_at": bson.M{"$gt": now}}}},
+				{"$or": []bson.M{{"max_views": 0}, {"$expr": bson.M{"$lt": []string{"$views", "$max_views"}}}}},
+			},
+		},
+		bson.M{"$inc": bson.M{"views": 1}, "$set": bson.M{"last_viewed_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&link)
+	if err == mongo.ErrNoDocuments {
+		if count, _ := collection.CountDocuments(ctx, bson.M{"token_hash": tokenHash}); count > 0 {
+			c.JSON(http.StatusGone, gin.H{"error": "This link has expired or been revoked"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Link not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open link"})
+		return
+	}
+
+	view := SharedView{
+		ResourceType: link.ResourceType,
+		Views:        link.Views,
+		MaxViews:     link.MaxViews,
+		ExpiresAt:    link.ExpiresAt,
+	}
+	if ownerID, err := primitive.ObjectIDFromHex(link.UserID); err == nil {
+		var owner models.User
+		if err := database.GetCollection("users").FindOne(ctx, bson.M{"_id": ownerID}).Decode(&owner); err == nil {
+			view.OwnerName = owner.Name
+		}
+	}
+
+	resourceFilter := bson.M{"_id": link.ResourceID, "user_id": link.UserID}
+	switch link.ResourceType {
+	case models.ShareItem:
+		var item models.ClothingItem
+		err = database.GetCollection("clothing").FindOne(ctx, resourceFilter).Decode(&item)
+		if err == nil {
+			shared := sharedItem(item)
+			view.Item = &shared
+		}
+	case models.ShareOutfit:
+		var outfit models.Outfit
+		err = database.GetCollection("outfits").FindOne(ctx, resourceFilter).Decode(&outfit)
+		if err == nil {
+			view.Outfit = &SharedOutfit{Name: outfit.Name, VibeTags: outfit.VibeTags}
+			view.Outfit.Items, err = sharedItems(ctx, link.UserID, outfit.ItemIDs)
+		}
+	case models.ShareCollection:
+		var collection models.Collection
+		err = database.GetCollection("collections").FindOne(ctx, resourceFilter).Decode(&collection)
+		if err == nil {
+			view.Collection = &SharedCollection{Name: collection.Name, Description: collection.Description}
+			view.Collection.Items, err = sharedItems(ctx, link.UserID, collection.ItemIDs)
+		}
+	}
+	if err != nil {
+		// Only views that showed something count against the link's limit
+		if _, err := collection.UpdateOne(ctx, bson.M{"_id": link.ID}, bson.M{"$inc": bson.M{"views": -1}}); err != nil {
+			log.Printf("Could not uncount view of share link %s: %v", link.ID.Hex(), err)
+		}
+	}
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "The shared " + link.ResourceType + " no longer exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load shared " + link.ResourceType})
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
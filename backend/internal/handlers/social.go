@@ -0,0 +1,785 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	profileListLimit = 50 // Public items and outfits shown on a profile
+	feedPageSize     = 20
+	feedMaxPageSize  = 50
+)
+
+// UserSummary is how other users appear in lists and feeds
+type UserSummary struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	AvatarURL string `json:"avatarUrl,omitempty"`
+}
+
+type Profile struct {
+	UserSummary
+	ProfileVisibility string `json:"profileVisibility"`
+	FollowerCount     int64  `json:"followerCount"`
+	FollowingCount    int64  `json:"followingCount"`
+
+	// The viewer's relationship with this user
+	FollowStatus string `json:"followStatus,omitempty"` // accepted, pending or empty
+	FollowsYou   bool   `json:"followsYou"`
+
+	// False when the privacy setting hides items and outfits from the viewer
+	Visible bool           `json:"visible"`
+	Items   []SharedItem   `json:"items"`
+	Outfits []SharedOutfit `json:"outfits"`
+}
+
+type FollowRequest struct {
+	ID        string      `json:"id"`
+	From      UserSummary `json:"from"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// FeedEntry is one public item or outfit; exactly one of Item and Outfit is set
+type FeedEntry struct {
+	Type      string        `json:"type"` // item or outfit
+	CreatedAt time.Time     `json:"createdAt"`
+	Owner     UserSummary   `json:"owner"`
+	Item      *SharedItem   `json:"item,omitempty"`
+	Outfit    *SharedOutfit `json:"outfit,omitempty"`
+}
+
+// id is the entry's item or outfit ID; both are ObjectIDs, so their hex
+// strings sort like the IDs themselves
+func (e FeedEntry) id() string {
+	if e.Item != nil {
+		return e.Item.ID
+	}
+	return e.Outfit.ID
+}
+
+type FeedResponse struct {
+	Entries []FeedEntry `json:"entries"`
+	// Pass as ?before= and ?beforeId= to get the next page; empty on the last page
+	NextBefore   *time.Time `json:"nextBefore,omitempty"`
+	NextBeforeID string     `json:"nextBeforeId,omitempty"`
+}
+
+func userSummary(u models.User) UserSummary {
+	return UserSummary{ID: u.ID.Hex(), Name: u.Name, AvatarURL: u.AvatarURL}
+}
+
+// followStatus is the state of follower's follow of followee, or "" if there is none
+func followStatus(ctx context.Context, followerID, followeeID string) (string, error) {
+	var follow models.Follow
+	err := database.GetCollection("follows").FindOne(ctx, bson.M{"follower_id": followerID, "followee_id": followeeID}).Decode(&follow)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return follow.Status, nil
+}
+
+// canViewProfile reports whether viewerID may see owner's public items and outfits
+func canViewProfile(ctx context.Context, viewerID string, owner models.User) (bool, error) {
+	if owner.ID.Hex() == viewerID {
+		return true, nil
+	}
+	switch owner.Visibility() {
+	case models.ProfilePublic:
+		return true, nil
+	case models.ProfileFollowers:
+		status, err := followStatus(ctx, viewerID, owner.ID.Hex())
+		return status == models.FollowAccepted, err
+	}
+	return false, nil
+}
+
+// itemOwnerVisible is canViewProfile for the owner of a public item
+func itemOwnerVisible(ctx context.Context, viewerID, ownerID string) bool {
+	objectID, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		return false
+	}
+	var owner models.User
+	if err := database.GetCollection("users").FindOne(ctx, bson.M{"_id": objectID}).Decode(&owner); err != nil {
+		return false
+	}
+	if owner.Disabled {
+		return false
+	}
+	visible, err := canViewProfile(ctx, viewerID, owner)
+	return err == nil && visible
+}
+
+// socialTarget loads the user named by the :id path parameter
+func socialTarget(c *gin.Context) (*models.User, bool) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+	var user models.User
+	err = database.GetCollection("users").FindOne(c.Request.Context(), bson.M{"_id": objectID, "disabled": bson.M{"$ne": true}}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return nil, false
+	}
+	return &user, true
+}
+
+// userSummaries loads summaries for ids, keyed by ID
+func userSummaries(ctx context.Context, ids []string) (map[string]UserSummary, error) {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	cursor, err := database.GetCollection("users").Find(ctx, bson.M{"_id": bson.M{"$in": objectIDs}},
+		options.Find().SetProjection(bson.M{"name": 1, "avatar_url": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	summaries := map[string]UserSummary{}
+	for _, u := range users {
+		summaries[u.ID.Hex()] = userSummary(u)
+	}
+	return summaries, nil
+}
+
+// @Summary Follow a user
+// @Description Follow another user. Following a public profile takes effect immediately; other profiles get a follow request to approve.
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} models.Follow
+// @Failure 400 {object} map[string]string "Invalid user ID, or following yourself"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/{id}/follow [post]
+func FollowUserHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	target, ok := socialTarget(c)
+	if !ok {
+		return
+	}
+	if target.ID.Hex() == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You can't follow yourself"})
+		return
+	}
+
+	// Following again is a no-op, so an existing follow or request is left as it is
+	now := time.Now()
+	followID := primitive.NewObjectID()
+	insert := bson.M{
+		"_id":         followID,
+		"follower_id": userID,
+		"followee_id": target.ID.Hex(),
+		"status":      models.FollowPending,
+		"created_at":  now,
+	}
+	if target.Visibility() == models.ProfilePublic {
+		insert["status"] = models.FollowAccepted
+		insert["accepted_at"] = now
+	}
+	filter := bson.M{"follower_id": userID, "followee_id": target.ID.Hex()}
+	update := bson.M{"$setOnInsert": insert}
+
+	var follow models.Follow
+	collection := database.GetCollection("follows")
+	err := collection.FindOneAndUpdate(c.Request.Context(), filter, update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)).Decode(&follow)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent request inserted it first; return that one
+		err = collection.FindOne(c.Request.Context(), filter).Decode(&follow)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to follow user"})
+		return
+	}
+
+	if follow.ID == followID {
+		n := models.Notification{UserID: follow.FolloweeID, TargetType: "user", TargetID: userID}
+		if follow.Status == models.FollowAccepted {
+			n.Type = models.NotifyNewFollower
+			notifyFrom(userID, n, "started following you")
+		} else {
+			n.Type = models.NotifyFollowRequest
+			notifyFrom(userID, n, "asked to follow you")
+		}
+	}
+
+	c.JSON(http.StatusOK, follow)
+}
+
+// @Summary Unfollow a user
+// @Description Stop following a user, or withdraw a pending follow request
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid user ID"
+// @Router /users/{id}/follow [delete]
+func UnfollowUserHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	if _, err := primitive.ObjectIDFromHex(c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	_, err := database.GetCollection("follows").DeleteOne(c.Request.Context(), bson.M{"follower_id": userID, "followee_id": c.Param("id")})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfollow user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Unfollowed"})
+}
+
+// @Summary List follow requests
+// @Description Pending requests to follow the authenticated user, oldest first
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} handlers.FollowRequest
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /user/follow-requests [get]
+func ListFollowRequestsHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	ctx := c.Request.Context()
+	cursor, err := database.GetCollection("follows").Find(ctx,
+		bson.M{"followee_id": userID, "status": models.FollowPending},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	var follows []models.Follow
+	if err == nil {
+		err = cursor.All(ctx, &follows)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch follow requests"})
+		return
+	}
+
+	ids := make([]string, len(follows))
+	for i, f := range follows {
+		ids[i] = f.FollowerID
+	}
+	summaries, err := userSummaries(ctx, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	requests := []FollowRequest{}
+	for _, f := range follows {
+		if from, ok := summaries[f.FollowerID]; ok {
+			requests = append(requests, FollowRequest{ID: f.ID.Hex(), From: from, CreatedAt: f.CreatedAt})
+		}
+	}
+	c.JSON(http.StatusOK, requests)
+}
+
+// @Summary Approve a follow request
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Follow request ID"
+// @Success 200 {object} models.Follow
+// @Failure 400 {object} map[string]string "Invalid follow request ID"
+// @Failure 404 {object} map[string]string "Follow request not found"
+// @Router /user/follow-requests/{id}/approve [post]
+func ApproveFollowRequestHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid follow request ID"})
+		return
+	}
+
+	var follow models.Follow
+	err = database.GetCollection("follows").FindOneAndUpdate(c.Request.Context(),
+		bson.M{"_id": objectID, "followee_id": userID, "status": models.FollowPending},
+		bson.M{"$set": bson.M{"status": models.FollowAccepted, "accepted_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&follow)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Follow request not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to approve follow request"})
+		return
+	}
+	notifyFrom(userID, models.Notification{
+		UserID:     follow.FollowerID,
+		Type:       models.NotifyFollowAccepted,
+		TargetType: "user",
+		TargetID:   userID,
+	}, "accepted your follow request")
+
+	c.JSON(http.StatusOK, follow)
+}
+
+// @Summary Decline a follow request
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Follow request ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid follow request ID"
+// @Failure 404 {object} map[string]string "Follow request not found"
+// @Router /user/follow-requests/{id} [delete]
+func DeclineFollowRequestHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid follow request ID"})
+		return
+	}
+
+	result, err := database.GetCollection("follows").DeleteOne(c.Request.Context(),
+		bson.M{"_id": objectID, "followee_id": userID, "status": models.FollowPending})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decline follow request"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Follow request not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Follow request declined"})
+}
+
+// @Summary Remove a follower
+// @Description Make another user stop following the authenticated user
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Follower's user ID"
+// @Success 200 {object} map[string]string
+// @Failure 404 {object} map[string]string "Not a follower"
+// @Router /user/followers/{id} [delete]
+func RemoveFollowerHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	result, err := database.GetCollection("follows").DeleteOne(c.Request.Context(),
+		bson.M{"follower_id": c.Param("id"), "followee_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove follower"})
+		return
+	}
+	if result.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not a follower"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Follower removed"})
+}
+
+// listConnections answers /users/:id/followers and /following. field is the
+// side of the follow the profile owner is on; the other side is listed.
+func listConnections(c *gin.Context, field, other string) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	target, ok := socialTarget(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	if visible, err := canViewProfile(ctx, userID, *target); err != nil || !visible {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This profile is private"})
+		return
+	}
+
+	cursor, err := database.GetCollection("follows").Find(ctx,
+		bson.M{field: target.ID.Hex(), "status": models.FollowAccepted},
+		options.Find().SetSort(bson.D{{Key: "accepted_at", Value: -1}}))
+	var follows []models.Follow
+	if err == nil {
+		err = cursor.All(ctx, &follows)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch follows"})
+		return
+	}
+
+	ids := make([]string, len(follows))
+	for i, f := range follows {
+		if other == "follower_id" {
+			ids[i] = f.FollowerID
+		} else {
+			ids[i] = f.FolloweeID
+		}
+	}
+	summaries, err := userSummaries(ctx, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	users := []UserSummary{}
+	for _, id := range ids {
+		if summary, ok := summaries[id]; ok {
+			users = append(users, summary)
+		}
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// @Summary List a user's followers
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {array} handlers.UserSummary
+// @Failure 403 {object} map[string]string "This profile is private"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/{id}/followers [get]
+func ListFollowersHandler(c *gin.Context) {
+	listConnections(c, "followee_id", "follower_id")
+}
+
+// @Summary List who a user follows
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {array} handlers.UserSummary
+// @Failure 403 {object} map[string]string "This profile is private"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/{id}/following [get]
+func ListFollowingHandler(c *gin.Context) {
+	listConnections(c, "follower_id", "followee_id")
+}
+
+// @Summary Get a user's profile
+// @Description A user's name, avatar, follow counts and, if their privacy setting allows the viewer, their most recent public items and outfits
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} handlers.Profile
+// @Failure 400 {object} map[string]string "Invalid user ID"
+// @Failure 404 {object} map[string]string "User not found"
+// @Router /users/{id} [get]
+func GetProfileHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	target, ok := socialTarget(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	targetID := target.ID.Hex()
+	follows := database.GetCollection("follows")
+
+	profile := Profile{
+		UserSummary:       userSummary(*target),
+		ProfileVisibility: target.Visibility(),
+		Items:             []SharedItem{},
+		Outfits:           []SharedOutfit{},
+	}
+	profile.FollowerCount, _ = follows.CountDocuments(ctx, bson.M{"followee_id": targetID, "status": models.FollowAccepted})
+	profile.FollowingCount, _ = follows.CountDocuments(ctx, bson.M{"follower_id": targetID, "status": models.FollowAccepted})
+	profile.FollowStatus, _ = followStatus(ctx, userID, targetID)
+	followsYou, _ := followStatus(ctx, targetID, userID)
+	profile.FollowsYou = followsYou == models.FollowAccepted
+
+	visible, err := canViewProfile(ctx, userID, *target)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check profile visibility"})
+		return
+	}
+	profile.Visible = visible
+	if !visible {
+		c.JSON(http.StatusOK, profile)
+		return
+	}
+
+	recent := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(profileListLimit)
+	publicFilter := bson.M{"user_id": targetID, "is_public": true}
+
+	var items []models.ClothingItem
+	cursor, err := database.GetCollection("clothing").Find(ctx, publicFilter, recent.SetProjection(bson.M{"embedding": 0}))
+	if err == nil {
+		err = cursor.All(ctx, &items)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch items"})
+		return
+	}
+	for _, item := range items {
+		profile.Items = append(profile.Items, sharedItem(item))
+	}
+
+	var outfits []models.Outfit
+	cursor, err = database.GetCollection("outfits").Find(ctx, publicFilter, recent.SetProjection(nil))
+	if err == nil {
+		err = cursor.All(ctx, &outfits)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch outfits"})
+		return
+	}
+	for _, outfit := range outfits {
+		shared, err := sharedOutfit(ctx, outfit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch outfit items"})
+			return
+		}
+		profile.Outfits = append(profile.Outfits, shared)
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// @Summary Get the feed
+// @Description Recent public items and outfits from the users you follow, newest first. Page with ?before= and ?beforeId= set to the previous page's nextBefore and nextBeforeId.
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Param before query string false "RFC 3339 timestamp; only entries created before it"
+// @Param beforeId query string false "ID of the last entry of the previous page; with before, also returns later entries created at the same time"
+// @Param limit query int false "Entries per page (max 50)"
+// @Success 200 {object} handlers.FeedResponse
+// @Failure 400 {object} map[string]string "Invalid cursor"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /feed [get]
+func GetFeedHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	before := time.Now()
+	if raw := c.Query("before"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be an RFC 3339 timestamp"})
+			return
+		}
+		before = parsed
+	}
+	var beforeID primitive.ObjectID
+	if raw := c.Query("beforeId"); raw != "" {
+		parsed, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "beforeId must be an entry ID"})
+			return
+		}
+		beforeID = parsed
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(feedPageSize)))
+	if limit < 1 {
+		limit = feedPageSize
+	}
+	limit = min(limit, feedMaxPageSize)
+
+	ctx := c.Request.Context()
+	response := FeedResponse{Entries: []FeedEntry{}}
+
+	// Accepted follows already satisfy "followers" profiles; only private ones drop out
+	cursor, err := database.GetCollection("follows").Find(ctx, bson.M{"follower_id": userID, "status": models.FollowAccepted})
+	var follows []models.Follow
+	if err == nil {
+		err = cursor.All(ctx, &follows)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch follows"})
+		return
+	}
+	var followeeIDs []primitive.ObjectID
+	for _, f := range follows {
+		if id, err := primitive.ObjectIDFromHex(f.FolloweeID); err == nil {
+			followeeIDs = append(followeeIDs, id)
+		}
+	}
+	if len(followeeIDs) == 0 {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	cursor, err = database.GetCollection("users").Find(ctx, bson.M{
+		"_id":                bson.M{"$in": followeeIDs},
+		"profile_visibility": bson.M{"$ne": models.ProfilePrivate},
+		"disabled":           bson.M{"$ne": true},
+	})
+	var followees []models.User
+	if err == nil {
+		err = cursor.All(ctx, &followees)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+	owners := map[string]UserSummary{}
+	ownerIDs := []string{}
+	for _, u := range followees {
+		owners[u.ID.Hex()] = userSummary(u)
+		ownerIDs = append(ownerIDs, u.ID.Hex())
+	}
+
+	// Fetch a page of each kind, then merge; anything past the limit comes back
+	// on the next page. Entries are ordered by (created_at, _id) so ones created
+	// at the same instant aren't skipped at a page boundary.
+	filter := bson.M{"user_id": bson.M{"$in": ownerIDs}, "is_public": true, "created_at": bson.M{"$lt": before}}
+	if !beforeID.IsZero() {
+		delete(filter, "created_at")
+		filter["$or"] = []bson.M{
+			{"created_at": bson.M{"$lt": before}},
+			{"created_at": before, "_id": bson.M{"$lt": beforeID}},
+		}
+	}
+	page := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(int64(limit))
+
+	var items []models.ClothingItem
+	cursor, err = database.GetCollection("clothing").Find(ctx, filter, page.SetProjection(bson.M{"embedding": 0}))
+	if err == nil {
+		err = cursor.All(ctx, &items)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch items"})
+		return
+	}
+	for _, item := range items {
+		shared := sharedItem(item)
+		response.Entries = append(response.Entries, FeedEntry{
+			Type: models.ShareItem, CreatedAt: item.CreatedAt, Owner: owners[item.UserID], Item: &shared,
+		})
+	}
+
+	var outfits []models.Outfit
+	cursor, err = database.GetCollection("outfits").Find(ctx, filter, page.SetProjection(nil))
+	if err == nil {
+		err = cursor.All(ctx, &outfits)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch outfits"})
+		return
+	}
+	for _, outfit := range outfits {
+		shared, err := sharedOutfit(ctx, outfit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch outfit items"})
+			return
+		}
+		response.Entries = append(response.Entries, FeedEntry{
+			Type: models.ShareOutfit, CreatedAt: outfit.CreatedAt, Owner: owners[outfit.UserID], Outfit: &shared,
+		})
+	}
+
+	sort.SliceStable(response.Entries, func(i, j int) bool {
+		a, b := response.Entries[i], response.Entries[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.id() > b.id()
+	})
+	// Entries cut off here, or a full page of either kind, may have more behind them
+	more := len(response.Entries) > limit || len(items) == limit || len(outfits) == limit
+	if len(response.Entries) > limit {
+		response.Entries = response.Entries[:limit]
+	}
+	if more {
+		last := response.Entries[len(response.Entries)-1]
+		next := last.CreatedAt
+		response.NextBefore = &next
+		response.NextBeforeID = last.id()
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// sharedOutfit is the public view of an outfit with its public items; making
+// an outfit public doesn't publish the private items in it
+func sharedOutfit(ctx context.Context, outfit models.Outfit) (SharedOutfit, error) {
+	items, err := publicItems(ctx, outfit.UserID, outfit.ItemIDs)
+	if err != nil {
+		return SharedOutfit{}, err
+	}
+	return SharedOutfit{ID: outfit.ID.Hex(), Name: outfit.Name, VibeTags: outfit.VibeTags, Items: items, Engagement: outfit.Engagement}, nil
+}
+
+// acceptPendingFollows lets everyone waiting in once a profile goes public
+func acceptPendingFollows(ctx context.Context, userID string) error {
+	_, err := database.GetCollection("follows").UpdateMany(ctx,
+		bson.M{"followee_id": userID, "status": models.FollowPending},
+		bson.M{"$set": bson.M{"status": models.FollowAccepted, "accepted_at": time.Now()}},
+	)
+	return err
+}
+
+func validVisibility(visibility string) bool {
+	return slices.Contains(models.ProfileVisibilities, visibility)
+}
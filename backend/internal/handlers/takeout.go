@@ -0,0 +1,529 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/ai"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/storage"
+	"github.com/exply/armoire/internal/taxonomy"
+	"github.com/exply/armoire/internal/usage"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	takeoutVersion  = 1
+	manifestName    = "manifest.json"
+	maxImportSize   = 512 << 20 // Whole archive
+	maxImportedFile = 32 << 20  // Any single entry, guards against zip bombs
+)
+
+// TakeoutManifest is the manifest.json at the root of an export archive
+type TakeoutManifest struct {
+	Version     int                 `json:"version"`
+	ExportedAt  time.Time           `json:"exportedAt"`
+	User        TakeoutUser         `json:"user"`
+	Items       []TakeoutItem       `json:"items"`
+	Outfits     []models.Outfit     `json:"outfits"`
+	Collections []models.Collection `json:"collections,omitempty"`
+
+	// Objects that could not be fetched from storage at export time
+	Missing []string `json:"missing,omitempty"`
+}
+
+type TakeoutUser struct {
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"emailVerified"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// TakeoutItem is a clothing item (embedding and storage URIs are never
+// serialized) plus the paths of its images inside the archive
+type TakeoutItem struct {
+	models.ClothingItem
+	Files TakeoutFiles `json:"files"`
+}
+
+type TakeoutFiles struct {
+	Image     string `json:"image,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+	CareLabel string `json:"careLabel,omitempty"`
+}
+
+// ImportReport summarizes what an import created
+type ImportReport struct {
+	Items       int      `json:"items"`
+	Outfits     int      `json:"outfits"`
+	Collections int      `json:"collections"`
+	Images      int      `json:"images"`
+	Skipped     []string `json:"skipped,omitempty"`  // Items that could not be restored
+	Warnings    []string `json:"warnings,omitempty"` // Restored, but with something missing
+}
+
+// @Summary Export account data
+// @Description Download a zip containing manifest.json (profile, clothing items without embeddings, outfits, collections) and every original, thumbnail and care label image
+// @Tags user
+// @Produce application/zip
+// @Security BearerAuth
+// @Success 200 {file} file "Zip archive"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /user/export [get]
+func ExportAccountHandler(c *gin.Context) {
+	user, ok := loadUser(c)
+	if !ok {
+		return
+	}
+	userID := user.ID.Hex()
+	ctx := c.Request.Context()
+
+	var items []models.ClothingItem
+	cursor, err := database.GetCollection("clothing").Find(ctx, bson.M{"user_id": userID})
+	if err == nil {
+		err = cursor.All(ctx, &items)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+
+	outfits := []models.Outfit{}
+	cursor, err = database.GetCollection("outfits").Find(ctx, bson.M{"user_id": userID})
+	if err == nil {
+		err = cursor.All(ctx, &outfits)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch outfits"})
+		return
+	}
+
+	collections := []models.Collection{}
+	cursor, err = database.GetCollection("collections").Find(ctx, bson.M{"user_id": userID})
+	if err == nil {
+		err = cursor.All(ctx, &collections)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collections"})
+		return
+	}
+
+	gcsClient, err := storage.NewStorageClient("armoire-bucket")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Storage unavailable"})
+		return
+	}
+
+	manifest := TakeoutManifest{
+		Version:    takeoutVersion,
+		ExportedAt: time.Now(),
+		User: TakeoutUser{
+			Name:          user.Name,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			CreatedAt:     user.CreatedAt,
+		},
+		Items:       []TakeoutItem{},
+		Outfits:     outfits,
+		Collections: collections,
+	}
+
+	// Headers go out with the first write, so from here on failures are
+	// recorded in the manifest instead of turning into an error response
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="armoire-export-%s.zip"`, time.Now().Format("2006-01-02")))
+	c.Status(http.StatusOK)
+
+	zw := zip.NewWriter(c.Writer)
+
+	// copyObject stores one image under dir/ and returns its archive path
+	copyObject := func(dir, gcsURI string) string {
+		if gcsURI == "" {
+			return ""
+		}
+		data, err := gcsClient.ReadFile(gcsURI)
+		if err != nil {
+			fmt.Printf("Warning: Failed to export %s: %v\n", gcsURI, err)
+			manifest.Missing = append(manifest.Missing, gcsURI)
+			return ""
+		}
+		name := dir + "/" + path.Base(gcsURI)
+		w, err := zw.Create(name)
+		if err == nil {
+			_, err = w.Write(data)
+		}
+		if err != nil {
+			manifest.Missing = append(manifest.Missing, gcsURI)
+			return ""
+		}
+		return name
+	}
+
+	for _, item := range items {
+		files := TakeoutFiles{
+			Image:     copyObject("images", item.GCSURI),
+			CareLabel: copyObject("care_labels", item.CareLabelGCSURI),
+		}
+		if thumbURI, ok := gcsClient.URIFromPublicURL(item.ThumbnailURL); ok && thumbURI != item.GCSURI {
+			files.Thumbnail = copyObject("thumbnails", thumbURI)
+		}
+		manifest.Items = append(manifest.Items, TakeoutItem{ClothingItem: item, Files: files})
+	}
+
+	w, err := zw.Create(manifestName)
+	if err == nil {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(manifest)
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		fmt.Printf("Warning: Export for user %s was cut short: %v\n", userID, err)
+	}
+}
+
+// @Summary Import account data
+// @Description Restore an archive produced by the export endpoint into the authenticated account. Items, outfits and collections get new IDs, images are re-uploaded and embeddings re-computed. Items are validated like an edit and skipped if a field is invalid; everything is imported private, with no loans and fresh timestamps. Existing data is kept.
+// @Tags user
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param archive formData file true "Export zip"
+// @Success 200 {object} handlers.ImportReport
+// @Failure 400 {object} map[string]string "Invalid archive"
+// @Failure 413 {object} map[string]string "Archive too large"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /user/import [post]
+func ImportAccountHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	fileHeader, err := c.FormFile("archive")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file"})
+		return
+	}
+	if fileHeader.Size > maxImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Archive too large"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportSize))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+		return
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Archive is not a valid zip file"})
+		return
+	}
+	entries := map[string]*zip.File{}
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+
+	manifestFile, ok := entries[manifestName]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Archive has no " + manifestName})
+		return
+	}
+	raw, err := readZipEntry(manifestFile)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read manifest: " + err.Error()})
+		return
+	}
+	var manifest TakeoutManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manifest: " + err.Error()})
+		return
+	}
+	if manifest.Version != takeoutVersion {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported export version %d", manifest.Version)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	gcsClient, err := storage.NewStorageClient("armoire-bucket")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Storage unavailable"})
+		return
+	}
+	aiClient, err := ai.NewAIClient(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "AI client unavailable"})
+		return
+	}
+
+	report := ImportReport{}
+
+	// restore uploads one archive entry under a new name and returns its gs:// URI and public URL
+	restore := func(entry, filename string) (string, string, error) {
+		f, ok := entries[entry]
+		if !ok {
+			return "", "", fmt.Errorf("%s is not in the archive", entry)
+		}
+		content, err := readZipEntry(f)
+		if err != nil {
+			return "", "", err
+		}
+		if !strings.HasPrefix(http.DetectContentType(content), "image/") {
+			return "", "", fmt.Errorf("%s is not an image", entry)
+		}
+		filename += strings.ToLower(path.Ext(entry))
+		gcsURI, err := gcsClient.UploadFile(bytes.NewReader(content), filename)
+		if err != nil {
+			return "", "", err
+		}
+		report.Images++
+		return gcsURI, "https://storage.googleapis.com/armoire-bucket/" + filename, nil
+	}
+
+	now := time.Now()
+	newIDs := map[primitive.ObjectID]primitive.ObjectID{}
+	collection := database.GetCollection("clothing")
+
+	for i, entry := range manifest.Items {
+		item := entry.ClothingItem
+		label := item.Name
+		if label == "" {
+			label = item.ID.Hex()
+		}
+
+		// The route's quota check covers one call; every item embeds once more
+		err := usage.Check(ctx, userID, ai.OpEmbedding)
+		var quotaErr *usage.QuotaError
+		if errors.As(err, &quotaErr) {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%d item(s) not restored: monthly AI quota exceeded (%s), it resets on %s",
+				len(manifest.Items)-i, quotaErr.Quota, quotaErr.ResetsAt.Format("January 2")))
+			break
+		}
+		if err != nil {
+			log.Printf("Could not check AI quota for %s: %v", userID, err)
+		}
+
+		// The archive may have been edited; hold it to the same rules as a PATCH
+		if errs := normalizeImportedItem(&item); len(errs) > 0 {
+			report.Skipped = append(report.Skipped, label+": "+errs.Error())
+			continue
+		}
+
+		newID := primitive.NewObjectID()
+		baseName := newID.Hex()
+
+		if entry.Files.Image == "" {
+			report.Skipped = append(report.Skipped, label+": no image in archive")
+			continue
+		}
+		gcsURI, publicURL, err := restore(entry.Files.Image, baseName)
+		if err != nil {
+			report.Skipped = append(report.Skipped, label+": "+err.Error())
+			continue
+		}
+
+		item.GCSURI = gcsURI
+		item.ImageURL = publicURL
+		item.ThumbnailURL = publicURL
+		if entry.Files.Thumbnail != "" {
+			if _, thumbURL, err := restore(entry.Files.Thumbnail, baseName+"_thumb"); err == nil {
+				item.ThumbnailURL = thumbURL
+			} else {
+				report.Warnings = append(report.Warnings, label+": thumbnail not restored: "+err.Error())
+			}
+		}
+
+		item.CareLabelURL, item.CareLabelGCSURI = "", ""
+		if entry.Files.CareLabel != "" {
+			if careURI, careURL, err := restore(entry.Files.CareLabel, baseName+"_care"); err == nil {
+				item.CareLabelGCSURI, item.CareLabelURL = careURI, careURL
+			} else {
+				report.Warnings = append(report.Warnings, label+": care label not restored: "+err.Error())
+			}
+		}
+
+		// Embeddings aren't exported; the target instance may even use a different model
+		embedText := item.Description
+		if embedText == "" {
+			embedText = item.Name
+		}
+		item.Embedding, err = aiClient.GetEmbedding(ctx, embedText)
+		if err != nil {
+			report.Warnings = append(report.Warnings, label+": embedding failed, item won't appear in vibe search")
+		}
+
+		// Loans, sharing and counters belong to the old account, not the archive
+		if item.Availability == models.AvailabilityLent || !models.IsAvailabilityStatus(item.Availability) {
+			item.Availability = models.AvailabilityAvailable
+			item.AvailabilityChangedAt = now
+		}
+		item.LoanSeq = 0
+		item.Draft = false
+		item.IsPublic = false
+		item.CreatedAt = now
+		item.UpdatedAt = now
+		item.UserID = userID
+		// Comments and reactions aren't part of an export
+		item.Engagement = models.Engagement{CommentsDisabled: item.CommentsDisabled}
+		oldID := item.ID
+		item.ID = newID
+
+		if _, err := collection.InsertOne(ctx, item); err != nil {
+			// Don't leave the restored files behind without an item
+			deleteStoredImage(gcsURI, item.ThumbnailURL)
+			if item.CareLabelGCSURI != "" {
+				if err := gcsClient.DeleteFile(item.CareLabelGCSURI); err != nil {
+					fmt.Printf("Warning: Failed to delete care label from GCS: %v\n", err)
+				}
+			}
+			report.Skipped = append(report.Skipped, label+": failed to save")
+			continue
+		}
+		newIDs[oldID] = newID
+		report.Items++
+	}
+
+	// remap swaps old item IDs for the restored ones, dropping items that weren't restored
+	remap := func(ids []primitive.ObjectID) []primitive.ObjectID {
+		mapped := []primitive.ObjectID{}
+		for _, id := range ids {
+			if newID, ok := newIDs[id]; ok {
+				mapped = append(mapped, newID)
+			}
+		}
+		return mapped
+	}
+
+	for _, outfit := range manifest.Outfits {
+		itemIDs := remap(outfit.ItemIDs)
+		if len(itemIDs) < len(outfit.ItemIDs) {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("outfit %q: %d item(s) were not restored", outfit.Name, len(outfit.ItemIDs)-len(itemIDs)))
+		}
+
+		outfit.ID = primitive.NewObjectID()
+		outfit.UserID = userID
+		outfit.ItemIDs = itemIDs
+		outfit.Engagement = models.Engagement{CommentsDisabled: outfit.CommentsDisabled}
+		outfit.IsPublic = false
+		outfit.CreatedAt = now
+		outfit.UpdatedAt = now
+		if _, err := database.GetCollection("outfits").InsertOne(ctx, outfit); err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("outfit %q: failed to save", outfit.Name))
+			continue
+		}
+		report.Outfits++
+	}
+
+	for _, collection := range manifest.Collections {
+		itemIDs := remap(collection.ItemIDs)
+		if len(itemIDs) < len(collection.ItemIDs) {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("collection %q: %d item(s) were not restored", collection.Name, len(collection.ItemIDs)-len(itemIDs)))
+		}
+
+		collection.ID = primitive.NewObjectID()
+		collection.UserID = userID
+		collection.ItemIDs = itemIDs
+		collection.CreatedAt = now
+		collection.UpdatedAt = now
+		if _, err := database.GetCollection("collections").InsertOne(ctx, collection); err != nil {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("collection %q: failed to save", collection.Name))
+			continue
+		}
+		report.Collections++
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// normalizeImportedItem runs an archived item's editable fields through
+// parseClothingUpdate and writes the normalized values back
+func normalizeImportedItem(item *models.ClothingItem) taxonomy.ValidationErrors {
+	list := func(values []string) []interface{} {
+		result := make([]interface{}, len(values))
+		for i, v := range values {
+			result[i] = v
+		}
+		return result
+	}
+
+	fields, errs := parseClothingUpdate(map[string]interface{}{
+		"name":          item.Name,
+		"description":   item.Description,
+		"category":      item.Category,
+		"sub_category":  item.SubCategory,
+		"colors":        list(item.Colors),
+		"seasons":       list(item.Seasons),
+		"occasions":     list(item.Occasions),
+		"materials":     list(item.Materials),
+		"pattern":       item.Pattern,
+		"fit":           item.Fit,
+		"neckline":      item.Neckline,
+		"sleeve_length": item.SleeveLength,
+		"brand_text":    item.BrandText,
+		"size_label":    item.SizeLabel,
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+
+	item.Name = fields["name"].(string)
+	item.Description = fields["description"].(string)
+	item.Category = fields["category"].(string)
+	item.SubCategory = fields["sub_category"].(string)
+	item.Colors = fields["colors"].([]string)
+	item.Seasons = fields["seasons"].([]string)
+	item.Occasions = fields["occasions"].([]string)
+	item.Materials = fields["materials"].([]string)
+	item.Pattern = fields["pattern"].(string)
+	item.Fit = fields["fit"].(string)
+	item.Neckline = fields["neckline"].(string)
+	item.SleeveLength = fields["sleeve_length"].(string)
+	item.BrandText = fields["brand_text"].(string)
+	item.SizeLabel = fields["size_label"].(string)
+	return nil
+}
+
+// readZipEntry reads one archive entry, refusing anything that inflates past maxImportedFile
+func readZipEntry(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > maxImportedFile {
+		return nil, fmt.Errorf("%s is too large", f.Name)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, maxImportedFile+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImportedFile {
+		return nil, fmt.Errorf("%s is too large", f.Name)
+	}
+	return data, nil
+}
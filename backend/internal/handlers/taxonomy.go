@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/exply/armoire/internal/taxonomy"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get the clothing taxonomy
+// @Description Get the versioned category → sub-category → attribute hierarchy with display names in the requested language
+// @Tags taxonomy
+// @Produce json
+// @Param lang query string false "Language code (e.g. en, fr). Defaults to the Accept-Language header, then English"
+// @Success 200 {object} taxonomy.LocalizedTaxonomy
+// @Router /taxonomy [get]
+func GetTaxonomyHandler(c *gin.Context) {
+	lang := c.Query("lang")
+	if lang == "" {
+		lang = primaryLanguage(c.GetHeader("Accept-Language"))
+	}
+
+	c.JSON(http.StatusOK, taxonomy.Current().Localize(lang))
+}
+
+// primaryLanguage extracts the base language of the first Accept-Language entry ("fr-CA,fr;q=0.9" → "fr")
+func primaryLanguage(header string) string {
+	first := strings.Split(header, ",")[0]
+	first = strings.Split(first, ";")[0]
+	first = strings.Split(first, "-")[0]
+	return strings.ToLower(strings.TrimSpace(first))
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/exply/armoire/internal/ai"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/usage"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// QuotaStatus is usage against one monthly quota; Limit 0 means unlimited
+type QuotaStatus struct {
+	Quota     string `json:"quota"`
+	Used      int64  `json:"used"`
+	Limit     int64  `json:"limit"`
+	Remaining *int64 `json:"remaining,omitempty"`
+}
+
+type UsageResponse struct {
+	Period     string                `json:"period"`
+	ResetsAt   time.Time             `json:"resetsAt"`
+	Operations []models.UsageCounter `json:"operations"`
+	Quotas     []QuotaStatus         `json:"quotas"`
+}
+
+// UsageTotals aggregates usage over a group of counters
+type UsageTotals struct {
+	Operation    string `bson:"_id,omitempty" json:"operation,omitempty"`
+	UserID       string `bson:"user_id,omitempty" json:"userId,omitempty"`
+	Email        string `bson:"-" json:"email,omitempty"`
+	Users        int64  `bson:"users,omitempty" json:"users,omitempty"`
+	Calls        int64  `bson:"calls" json:"calls"`
+	PromptTokens int64  `bson:"prompt_tokens" json:"promptTokens"`
+	OutputTokens int64  `bson:"output_tokens" json:"outputTokens"`
+	TotalTokens  int64  `bson:"total_tokens" json:"totalTokens"`
+	Credits      int64  `bson:"credits" json:"credits"`
+}
+
+type AdminUsageSummary struct {
+	Period     string        `json:"period"`
+	Operations []UsageTotals `json:"operations"`
+	TopUsers   []UsageTotals `json:"topUsers"` // By total tokens, then calls
+}
+
+const adminTopUsers = 20
+
+// usagePeriod reads ?period=YYYY-MM, defaulting to the current month
+func usagePeriod(c *gin.Context) (string, time.Time, bool) {
+	period := c.DefaultQuery("period", usage.Period(time.Now()))
+	end, err := usage.PeriodEnd(period)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period must look like 2006-01"})
+		return "", time.Time{}, false
+	}
+	return period, end, true
+}
+
+func quotaStatus(key string, used int64) QuotaStatus {
+	status := QuotaStatus{Quota: key, Used: used, Limit: usage.Quotas[key]}
+	if status.Limit > 0 {
+		remaining := max(status.Limit-used, 0)
+		status.Remaining = &remaining
+	}
+	return status
+}
+
+// @Summary Get AI usage
+// @Description Get the authenticated user's AI usage (calls, Gemini tokens, Clipdrop credits) per operation for a month, and how much of each monthly quota is left
+// @Tags user
+// @Produce json
+// @Security BearerAuth
+// @Param period query string false "Month as YYYY-MM, defaults to the current month"
+// @Success 200 {object} handlers.UsageResponse
+// @Failure 400 {object} map[string]string "Invalid period"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /user/usage [get]
+func GetUsageHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	period, resetsAt, ok := usagePeriod(c)
+	if !ok {
+		return
+	}
+
+	counters, err := usage.Counters(c.Request.Context(), userID, period)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch usage"})
+		return
+	}
+
+	used := usage.Used(counters)
+
+	quotas := []QuotaStatus{}
+	for _, key := range append(append([]string{}, ai.Operations...), usage.QuotaTokens, usage.QuotaCredits) {
+		quotas = append(quotas, quotaStatus(key, used[key]))
+	}
+
+	c.JSON(http.StatusOK, UsageResponse{
+		Period:     period,
+		ResetsAt:   resetsAt,
+		Operations: counters,
+		Quotas:     quotas,
+	})
+}
+
+// @Summary Get AI usage across all users
+// @Description Admin only. Totals per operation for a month, plus the heaviest users.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param period query string false "Month as YYYY-MM, defaults to the current month"
+// @Success 200 {object} handlers.AdminUsageSummary
+// @Failure 400 {object} map[string]string "Invalid period"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/usage [get]
+func GetAdminUsageSummaryHandler(c *gin.Context) {
+	period, _, ok := usagePeriod(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	collection := database.GetCollection(usage.Collection)
+	sums := bson.M{
+		"calls":         bson.M{"$sum": "$calls"},
+		"prompt_tokens": bson.M{"$sum": "$prompt_tokens"},
+		"output_tokens": bson.M{"$sum": "$output_tokens"},
+		"total_tokens":  bson.M{"$sum": "$total_tokens"},
+		"credits":       bson.M{"$sum": "$credits"},
+	}
+
+	byOperation := bson.M{"_id": "$operation", "user_ids": bson.M{"$addToSet": "$user_id"}}
+	for k, v := range sums {
+		byOperation[k] = v
+	}
+	operations := []UsageTotals{}
+	if err := aggregateInto(c, collection, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"period": period}}},
+		{{Key: "$group", Value: byOperation}},
+		{{Key: "$set", Value: bson.M{"users": bson.M{"$size": "$user_ids"}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}, &operations); err != nil {
+		return
+	}
+
+	byUser := bson.M{"_id": "$user_id"}
+	for k, v := range sums {
+		byUser[k] = v
+	}
+	topUsers := []UsageTotals{}
+	if err := aggregateInto(c, collection, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"period": period}}},
+		{{Key: "$group", Value: byUser}},
+		{{Key: "$sort", Value: bson.D{{Key: "total_tokens", Value: -1}, {Key: "calls", Value: -1}}}},
+		{{Key: "$limit", Value: adminTopUsers}},
+		{{Key: "$set", Value: bson.M{"user_id": "$_id"}}},
+		{{Key: "$unset", Value: "_id"}},
+	}, &topUsers); err != nil {
+		return
+	}
+
+	// Attach emails so the summary is readable
+	var ids []primitive.ObjectID
+	for _, u := range topUsers {
+		if id, err := primitive.ObjectIDFromHex(u.UserID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > 0 {
+		var users []models.User
+		cursor, err := database.GetCollection("users").Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err == nil && cursor.All(ctx, &users) == nil {
+			emails := map[string]string{}
+			for _, u := range users {
+				emails[u.ID.Hex()] = u.Email
+			}
+			for i := range topUsers {
+				topUsers[i].Email = emails[topUsers[i].UserID]
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, AdminUsageSummary{
+		Period:     period,
+		Operations: operations,
+		TopUsers:   topUsers,
+	})
+}
+
+// aggregateInto runs pipeline and decodes every result into out, answering 500 itself on failure
+func aggregateInto(c *gin.Context, collection *mongo.Collection, pipeline mongo.Pipeline, out interface{}) error {
+	ctx := c.Request.Context()
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err == nil {
+		err = cursor.All(ctx, out)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to aggregate usage"})
+	}
+	return err
+}
@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/mailer"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	emailVerificationTTL = 24 * time.Hour
+	passwordResetTTL     = time.Hour
+)
+
+var errInvalidUserToken = errors.New("invalid or expired token")
+
+type EmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ConfirmTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// issueUserToken creates a fresh single-use token, invalidating any outstanding one for the same purpose
+func issueUserToken(ctx context.Context, user models.User, purpose string, ttl time.Duration) (string, error) {
+	collection := database.GetCollection("user_tokens")
+	now := time.Now()
+
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"user_id": user.ID.Hex(), "purpose": purpose, "used_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"used_at": now}},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := newRefreshSecret()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = collection.InsertOne(ctx, models.UserToken{
+		ID:        primitive.NewObjectID(),
+		UserID:    user.ID.Hex(),
+		Purpose:   purpose,
+		TokenHash: hashToken(raw),
+		Email:     user.Email,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// consumeUserToken atomically marks a token used, so it can only ever be redeemed once
+func consumeUserToken(ctx context.Context, raw, purpose string) (*models.UserToken, error) {
+	now := time.Now()
+
+	var token models.UserToken
+	err := database.GetCollection("user_tokens").FindOneAndUpdate(ctx,
+		bson.M{
+			"token_hash": hashToken(raw),
+			"purpose":    purpose,
+			"used_at":    bson.M{"$exists": false},
+			"expires_at": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{"used_at": now}},
+	).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, errInvalidUserToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// appBaseURL is where the frontend is served
+func appBaseURL() string {
+	if base := os.Getenv("APP_BASE_URL"); base != "" {
+		return base
+	}
+	return "http://localhost:5173"
+}
+
+// apiBaseURL is where this API is reached from outside, for links that
+// calendar apps and other clients fetch directly
+func apiBaseURL(c *gin.Context) string {
+	if base := os.Getenv("API_BASE_URL"); base != "" {
+		return base
+	}
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// appLink builds a link into the frontend, e.g. /verify-email?token=...
+func appLink(path, token string) string {
+	return appBaseURL() + path + "?" + url.Values{"token": {token}}.Encode()
+}
+
+func sendVerificationEmail(ctx context.Context, user models.User) error {
+	token, err := issueUserToken(ctx, user, models.TokenVerifyEmail, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your Armoire email",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm your email address by opening this link:\n\n%s\n\nThe link expires in 24 hours.\n",
+			user.Name, appLink("/verify-email", token)),
+	})
+}
+
+// sendEmailChangedNotice tells the previous address, so the owner notices if
+// someone else changed it
+func sendEmailChangedNotice(ctx context.Context, user models.User, oldEmail string) error {
+	return mailer.Send(ctx, mailer.Message{
+		To:      oldEmail,
+		Subject: "Your Armoire email was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe email address of your Armoire account was changed to %s.\n\nIf you didn't do this, reset your password and contact support right away.\n",
+			user.Name, user.Email),
+	})
+}
+
+// @Summary Request an email verification link
+// @Description Send (or re-send) a verification link to the authenticated user's email address
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 500 {object} map[string]string "Could not send verification email"
+// @Router /auth/verify-email/request [post]
+func RequestEmailVerificationHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	objectID, _ := primitive.ObjectIDFromHex(userIDVal.(string))
+
+	ctx := c.Request.Context()
+	var user models.User
+	if err := database.GetCollection("users").FindOne(ctx, bson.M{"_id": objectID}).Decode(&user); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if user.EmailVerified {
+		c.JSON(http.StatusOK, gin.H{"message": "Email already verified"})
+		return
+	}
+
+	if err := sendVerificationEmail(ctx, user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not send verification email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification email sent"})
+}
+
+// @Summary Confirm an email address
+// @Description Redeem a verification token from the email link
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ConfirmTokenRequest true "Verification token"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid or expired token"
+// @Router /auth/verify-email/confirm [post]
+func ConfirmEmailVerificationHandler(c *gin.Context) {
+	var req ConfirmTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	token, err := consumeUserToken(ctx, req.Token, models.TokenVerifyEmail)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+
+	// The token only verifies the address it was sent to, in case the email changed since
+	userObjectID, _ := primitive.ObjectIDFromHex(token.UserID)
+	now := time.Now()
+	result, err := database.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": userObjectID, "email": token.Email},
+		bson.M{"$set": bson.M{"email_verified": true, "email_verified_at": now}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify email"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified"})
+}
+
+// @Summary Request a password reset
+// @Description Email a password reset link. Always succeeds so it can't be used to discover accounts.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body EmailRequest true "Account email"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid request body"
+// @Router /auth/password-reset/request [post]
+func RequestPasswordResetHandler(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var user models.User
+	if err := database.GetCollection("users").FindOne(ctx, bson.M{"email": models.NormalizeEmail(req.Email)}).Decode(&user); err == nil {
+		token, err := issueUserToken(ctx, user, models.TokenResetPassword, passwordResetTTL)
+		if err == nil {
+			err = mailer.Send(ctx, mailer.Message{
+				To:      user.Email,
+				Subject: "Reset your Armoire password",
+				Body: fmt.Sprintf("Hi %s,\n\nSomeone (hopefully you) asked to reset your password. Choose a new one here:\n\n%s\n\nThe link expires in 1 hour. If you didn't ask for this, you can ignore this email.\n",
+					user.Name, appLink("/reset-password", token)),
+			})
+		}
+		if err != nil {
+			log.Printf("Password reset email failed for user %s: %v", user.ID.Hex(), err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If an account exists for that email, a reset link has been sent"})
+}
+
+// @Summary Reset a password
+// @Description Set a new password with a reset token. Signs the user out everywhere.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid or expired token"
+// @Router /auth/password-reset/confirm [post]
+func ConfirmPasswordResetHandler(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	token, err := consumeUserToken(ctx, req.Token, models.TokenResetPassword)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not hash password"})
+		return
+	}
+
+	// Receiving the link proves ownership of the address, so it counts as verified too
+	userObjectID, _ := primitive.ObjectIDFromHex(token.UserID)
+	result, err := database.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": userObjectID, "email": token.Email},
+		bson.M{"$set": bson.M{"password": string(hashedPassword), "email_verified": true}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not reset password"})
+		return
+	}
+	if result.MatchedCount == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+
+	// The owner may have locked themselves out before asking for the link
+	if _, err := database.GetCollection(loginFailuresCollection).DeleteOne(ctx, bson.M{"_id": token.Email}); err != nil {
+		log.Printf("Could not reset failed logins after password reset for %s: %v", token.UserID, err)
+	}
+
+	// Whoever knew the old password shouldn't stay signed in
+	if _, err := revokeSessions(ctx, bson.M{"user_id": token.UserID}); err != nil {
+		log.Printf("Could not revoke sessions after password reset for %s: %v", token.UserID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password updated, please log in again"})
+}
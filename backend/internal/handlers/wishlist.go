@@ -0,0 +1,581 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/ai"
+	"github.com/exply/armoire/internal/capsule"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/exply/armoire/internal/storage"
+	"github.com/exply/armoire/internal/taxonomy"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Closet items at least this similar (cosine of the embeddings) to a
+// wishlist item are reported as near-duplicates
+const nearDuplicateSimilarity = 0.9
+
+const maxWishlistNotesLength = 500
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// CreateWishlistItemRequest is sent as multipart/form-data, optionally with
+// an image file. Tags that are left out are filled in by AI tagging of the image.
+type CreateWishlistItemRequest struct {
+	Name        string   `form:"name"` // Required without an image
+	Link        string   `form:"link"`
+	Price       float64  `form:"price"`
+	Currency    string   `form:"currency"`
+	ImageURL    string   `form:"imageUrl"` // Downloaded if no image file is sent
+	Category    string   `form:"category"`
+	SubCategory string   `form:"subCategory"`
+	Colors      []string `form:"colors"`
+	Notes       string   `form:"notes"`
+}
+
+// UpdateWishlistItemRequest changes only the fields that are sent
+type UpdateWishlistItemRequest struct {
+	Name        *string  `json:"name"`
+	Link        *string  `json:"link"`
+	Price       *float64 `json:"price"`
+	Currency    *string  `json:"currency"`
+	Category    *string  `json:"category"`
+	SubCategory *string  `json:"subCategory"`
+	Colors      []string `json:"colors"`
+	Notes       *string  `json:"notes"`
+}
+
+// WishlistItemView is a wishlist item and how it would fit in the closet
+type WishlistItemView struct {
+	models.WishlistItem
+	// Outfits it would make with the closet as it is now
+	NewOutfits int `json:"newOutfits"`
+	// The most similar item already in the closet, if it looks like the same thing
+	OwnedSimilar *SharedItem `json:"ownedSimilar,omitempty"`
+	Similarity   float64     `json:"similarity,omitempty"`
+}
+
+// normalizeWishlist checks a wishlist item's link, price and tags, putting
+// them in canonical form
+func normalizeWishlist(w *models.WishlistItem) taxonomy.ValidationErrors {
+	var errs taxonomy.ValidationErrors
+
+	w.Name = strings.TrimSpace(w.Name)
+	w.Notes = strings.TrimSpace(w.Notes)
+	if len([]rune(w.Notes)) > maxWishlistNotesLength {
+		errs = append(errs, taxonomy.FieldError{Field: "notes", Message: "can be at most 500 characters"})
+	}
+	w.Link = strings.TrimSpace(w.Link)
+	if w.Link != "" {
+		u, err := url.Parse(w.Link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, taxonomy.FieldError{Field: "link", Value: w.Link, Message: "must be an http or https URL"})
+		}
+	}
+	if w.Price < 0 || math.IsNaN(w.Price) || math.IsInf(w.Price, 0) {
+		errs = append(errs, taxonomy.FieldError{Field: "price", Message: "must be a positive amount"})
+	}
+	w.Currency = strings.ToUpper(strings.TrimSpace(w.Currency))
+	if w.Currency != "" && !currencyPattern.MatchString(w.Currency) {
+		errs = append(errs, taxonomy.FieldError{Field: "currency", Value: w.Currency, Message: "must be a three-letter ISO 4217 code"})
+	}
+
+	if w.SubCategory != "" {
+		sub, ok := taxonomy.Normalize(w.SubCategory, taxonomy.SubCategories)
+		if !ok {
+			errs = append(errs, taxonomy.FieldError{Field: "sub_category", Value: w.SubCategory, Message: "unknown value"})
+		}
+		w.SubCategory = sub
+	}
+	if w.Category != "" {
+		category, ok := taxonomy.Normalize(w.Category, taxonomy.Categories)
+		if !ok {
+			errs = append(errs, taxonomy.FieldError{Field: "category", Value: w.Category, Message: "unknown value"})
+		}
+		w.Category = category
+	} else if parent, ok := taxonomy.CategoryOf(w.SubCategory); ok {
+		w.Category = parent
+	}
+	if w.Category != "" && w.SubCategory != "" {
+		if fieldErr := taxonomy.CheckSubCategory(w.Category, w.SubCategory); fieldErr != nil {
+			errs = append(errs, *fieldErr)
+		}
+	}
+	colors, colorErrs := taxonomy.NormalizeList("colors", w.Colors, taxonomy.Colors)
+	w.Colors = colors
+	errs = append(errs, colorErrs...)
+	return errs
+}
+
+// wishlistEmbedText describes an item without a photo, for its embedding
+func wishlistEmbedText(w models.WishlistItem) string {
+	parts := []string{w.Name}
+	if len(w.Colors) > 0 {
+		parts = append(parts, strings.Join(w.Colors, " "))
+	}
+	if w.SubCategory != "" {
+		parts = append(parts, w.SubCategory)
+	} else if w.Category != "" {
+		parts = append(parts, w.Category)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// wishlistImage reads the image sent with a wishlist item, as a file or a URL.
+// It returns nil if there is neither.
+func wishlistImage(c *gin.Context, imageURL string) ([]byte, string, error) {
+	if fileHeader, err := c.FormFile("image"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		return data, fileHeader.Filename, err
+	}
+	if imageURL == "" {
+		return nil, "", nil
+	}
+
+	resp, err := urlFetcher.Get(c.Request.Context(), imageURL)
+	if err != nil {
+		return nil, "", err
+	}
+	if !strings.HasPrefix(resp.ContentType, "image/") {
+		return nil, "", errors.New("imageUrl is not an image")
+	}
+	filename := "image"
+	if u, err := url.Parse(resp.URL); err == nil {
+		filename = path.Base(u.Path)
+	}
+	return resp.Body, filename, nil
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// wishlistViews works out, against the closet as it is now, how many outfits
+// each item would make and whether something like it is already owned
+func wishlistViews(ctx context.Context, userID string, wishes []models.WishlistItem) ([]WishlistItemView, error) {
+	views := make([]WishlistItemView, len(wishes))
+	if len(wishes) == 0 {
+		return views, nil
+	}
+
+	// Drafts from receipts aren't tagged yet
+	cursor, err := database.GetCollection("clothing").Find(ctx, bson.M{"user_id": userID, "draft": bson.M{"$ne": true}},
+		options.Find().SetProjection(bson.M{"availability_history": 0}))
+	if err != nil {
+		return nil, err
+	}
+	var closet []models.ClothingItem
+	if err := cursor.All(ctx, &closet); err != nil {
+		return nil, err
+	}
+
+	for i, w := range wishes {
+		views[i] = WishlistItemView{WishlistItem: w, NewOutfits: capsule.NewOutfits(closet, w.AsClothingItem())}
+
+		var best *models.ClothingItem
+		bestScore := 0.0
+		for j, item := range closet {
+			// Different kinds of garment are never duplicates, however alike they're described
+			if w.Category != "" && item.Category != w.Category {
+				continue
+			}
+			if score := cosineSimilarity(w.Embedding, item.Embedding); score > bestScore {
+				best, bestScore = &closet[j], score
+			}
+		}
+		if best != nil && bestScore >= nearDuplicateSimilarity {
+			similar := sharedItem(*best)
+			views[i].OwnedSimilar = &similar
+			views[i].Similarity = math.Round(bestScore*1000) / 1000
+		}
+	}
+	return views, nil
+}
+
+// @Summary Add to the wishlist
+// @Description Save something to buy: a name, shop link and price, and a photo (uploaded or from a URL) that goes through the same background removal and AI tagging as a closet upload. Tags sent with the request win over AI ones. The response says how many outfits it would make with the closet and whether something very similar is already owned.
+// @Tags wishlist
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param image formData file false "Photo of the item (max 10MB)"
+// @Param imageUrl formData string false "Photo URL, used if no file is sent"
+// @Param name formData string false "Name, required without a photo"
+// @Param link formData string false "Shop page"
+// @Param price formData number false "Price"
+// @Param currency formData string false "ISO 4217 currency code"
+// @Param category formData string false "Category"
+// @Param subCategory formData string false "Sub-category"
+// @Param colors formData []string false "Colors" collectionFormat(multi)
+// @Param notes formData string false "Notes"
+// @Success 201 {object} handlers.WishlistItemView
+// @Failure 400 {object} map[string]interface{} "Invalid fields or image"
+// @Failure 500 {object} map[string]string "Failed to upload to GCS / AI Analysis Failed / Vector Embedding Failed / Database Save Failed"
+// @Router /wishlist [post]
+func CreateWishlistItemHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	var req CreateWishlistItemRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	wish := models.WishlistItem{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		Name:        req.Name,
+		Link:        req.Link,
+		Price:       req.Price,
+		Currency:    req.Currency,
+		Category:    req.Category,
+		SubCategory: req.SubCategory,
+		Colors:      req.Colors,
+		Seasons:     []string{},
+		Occasions:   []string{},
+		Notes:       req.Notes,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if errs := normalizeWishlist(&wish); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist item", "fields": errs})
+		return
+	}
+
+	imageBytes, filename, err := wishlistImage(c, req.ImageURL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read the image: " + err.Error()})
+		return
+	}
+	if imageBytes == nil && wish.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A name is required without an image"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	aiClient, err := ai.NewAIClient(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "AI client unavailable"})
+		return
+	}
+
+	if imageBytes != nil {
+		gcsClient, _ := storage.NewStorageClient("armoire-bucket")
+		image, err := processImage(ctx, gcsClient, aiClient, imageBytes, filename)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		analysis := image.Analysis
+		wish.ImageURL = image.PublicURL
+		wish.GCSURI = image.GCSURI
+		wish.ThumbnailURL = image.ThumbnailURL
+		wish.Embedding = image.Embedding
+		wish.Description = analysis.Description
+		wish.Seasons = analysis.Seasons
+		wish.Occasions = analysis.Occasions
+		wish.Pattern = analysis.Pattern
+		if wish.Name == "" {
+			wish.Name = analysis.Name
+		}
+		if wish.Category == "" {
+			wish.Category = analysis.Category
+			wish.SubCategory = analysis.SubCategory
+		}
+		if len(wish.Colors) == 0 {
+			wish.Colors = analysis.Colors
+		}
+	} else {
+		wish.Embedding, err = aiClient.GetEmbedding(ctx, wishlistEmbedText(wish))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Vector Embedding Failed"})
+			return
+		}
+	}
+
+	if _, err := database.GetCollection("wishlist").InsertOne(ctx, wish); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database Save Failed"})
+		return
+	}
+
+	views, err := wishlistViews(ctx, userID, []models.WishlistItem{wish})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+	c.JSON(http.StatusCreated, views[0])
+}
+
+// @Summary Get the wishlist
+// @Description Everything on the wishlist, newest first, each with how many outfits it would make with the closet and the most similar item already owned, if it's a near-duplicate
+// @Tags wishlist
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} handlers.WishlistItemView
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /wishlist [get]
+func ListWishlistHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	ctx := c.Request.Context()
+	cursor, err := database.GetCollection("wishlist").Find(ctx, bson.M{"user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch wishlist"})
+		return
+	}
+	wishes := []models.WishlistItem{}
+	if err := cursor.All(ctx, &wishes); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode wishlist"})
+		return
+	}
+
+	views, err := wishlistViews(ctx, userID, wishes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+	c.JSON(http.StatusOK, views)
+}
+
+// @Summary Get a wishlist item
+// @Description A wishlist item with how many outfits it would make with the closet and any near-duplicate already owned
+// @Tags wishlist
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Wishlist item ID"
+// @Success 200 {object} handlers.WishlistItemView
+// @Failure 400 {object} map[string]string "Invalid wishlist item ID"
+// @Failure 404 {object} map[string]string "Wishlist item not found"
+// @Router /wishlist/{id} [get]
+func GetWishlistItemHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist item ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var wish models.WishlistItem
+	err = database.GetCollection("wishlist").FindOne(ctx, bson.M{"_id": objectID, "user_id": userID}).Decode(&wish)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wishlist item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch wishlist item"})
+		return
+	}
+
+	views, err := wishlistViews(ctx, userID, []models.WishlistItem{wish})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+	c.JSON(http.StatusOK, views[0])
+}
+
+// @Summary Update a wishlist item
+// @Description Change a wishlist item's name, link, price, tags or notes. Items without a photo are re-embedded when their name, category or colors change.
+// @Tags wishlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Wishlist item ID"
+// @Param request body UpdateWishlistItemRequest true "Fields to change"
+// @Success 200 {object} handlers.WishlistItemView
+// @Failure 400 {object} map[string]interface{} "Invalid request body or fields"
+// @Failure 404 {object} map[string]string "Wishlist item not found"
+// @Router /wishlist/{id} [patch]
+func UpdateWishlistItemHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist item ID"})
+		return
+	}
+
+	var req UpdateWishlistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	collection := database.GetCollection("wishlist")
+	var wish models.WishlistItem
+	err = collection.FindOne(ctx, bson.M{"_id": objectID, "user_id": userID}).Decode(&wish)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wishlist item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch wishlist item"})
+		return
+	}
+
+	before := wishlistEmbedText(wish)
+	if req.Name != nil {
+		wish.Name = *req.Name
+	}
+	if req.Link != nil {
+		wish.Link = *req.Link
+	}
+	if req.Price != nil {
+		wish.Price = *req.Price
+	}
+	if req.Currency != nil {
+		wish.Currency = *req.Currency
+	}
+	if req.Category != nil {
+		wish.Category = *req.Category
+	}
+	if req.SubCategory != nil {
+		wish.SubCategory = *req.SubCategory
+	}
+	if req.Colors != nil {
+		wish.Colors = req.Colors
+	}
+	if req.Notes != nil {
+		wish.Notes = *req.Notes
+	}
+	if errs := normalizeWishlist(&wish); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist item", "fields": errs})
+		return
+	}
+	if wish.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+		return
+	}
+
+	if wish.GCSURI == "" && wishlistEmbedText(wish) != before {
+		aiClient, err := ai.NewAIClient(ctx)
+		if err == nil {
+			wish.Embedding, err = aiClient.GetEmbedding(ctx, wishlistEmbedText(wish))
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Vector Embedding Failed"})
+			return
+		}
+	}
+
+	wish.UpdatedAt = time.Now()
+	_, err = collection.UpdateOne(ctx, bson.M{"_id": objectID, "user_id": userID}, bson.M{"$set": bson.M{
+		"name":         wish.Name,
+		"link":         wish.Link,
+		"price":        wish.Price,
+		"currency":     wish.Currency,
+		"category":     wish.Category,
+		"sub_category": wish.SubCategory,
+		"colors":       wish.Colors,
+		"notes":        wish.Notes,
+		"embedding":    wish.Embedding,
+		"updated_at":   wish.UpdatedAt,
+	}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update wishlist item"})
+		return
+	}
+
+	views, err := wishlistViews(ctx, userID, []models.WishlistItem{wish})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch clothing items"})
+		return
+	}
+	c.JSON(http.StatusOK, views[0])
+}
+
+// @Summary Remove from the wishlist
+// @Description Delete a wishlist item and its photo
+// @Tags wishlist
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Wishlist item ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid wishlist item ID"
+// @Failure 404 {object} map[string]string "Wishlist item not found"
+// @Router /wishlist/{id} [delete]
+func DeleteWishlistItemHandler(c *gin.Context) {
+	userIDVal, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+		return
+	}
+	userID := userIDVal.(string)
+
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wishlist item ID"})
+		return
+	}
+
+	var wish models.WishlistItem
+	err = database.GetCollection("wishlist").FindOneAndDelete(c.Request.Context(), bson.M{"_id": objectID, "user_id": userID}).Decode(&wish)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Wishlist item not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete wishlist item"})
+		return
+	}
+
+	deleteStoredImage(wish.GCSURI, wish.ThumbnailURL)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Wishlist item deleted"})
+}
@@ -0,0 +1,92 @@
+// Package ical writes iCalendar (RFC 5545) feeds of all-day events.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout  = "20060102"
+	stampLayout = "20060102T150405Z"
+	lineLimit   = 75 // Octets per line before folding
+)
+
+// Event is an all-day event
+type Event struct {
+	UID         string // Globally unique and stable, e.g. "<id>@armoire"
+	Date        time.Time
+	Summary     string
+	Description string
+	URL         string
+	Updated     time.Time
+}
+
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Write renders the calendar with CRLF line endings
+func (cal Calendar) Write(w io.Writer) error {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:-//Armoire//Outfit Planner//EN")
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if cal.Name != "" {
+		lw.line("X-WR-CALNAME:" + Escape(cal.Name))
+	}
+	for _, e := range cal.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + Escape(e.UID))
+		lw.line("DTSTAMP:" + e.Updated.UTC().Format(stampLayout))
+		lw.line("DTSTART;VALUE=DATE:" + e.Date.Format(dateLayout))
+		lw.line("DTEND;VALUE=DATE:" + e.Date.AddDate(0, 0, 1).Format(dateLayout))
+		lw.line("SUMMARY:" + Escape(e.Summary))
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + Escape(e.Description))
+		}
+		if e.URL != "" {
+			lw.line("URL:" + e.URL)
+		}
+		lw.line("TRANSP:TRANSPARENT") // Planning an outfit doesn't make you busy
+		lw.line("END:VEVENT")
+	}
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+// Escape escapes a TEXT value
+func Escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+// line writes one content line, folding it into 75-octet pieces without
+// splitting a UTF-8 sequence
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > lineLimit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	_, lw.err = fmt.Fprint(lw.w, b.String())
+}
@@ -0,0 +1,191 @@
+package laundry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/exply/armoire/internal/models"
+)
+
+// Ways a load gets cleaned
+const (
+	MethodMachine  = "machine"
+	MethodHand     = "hand"
+	MethodDryClean = "dry_clean"
+)
+
+// Color groups that shouldn't share a wash
+const (
+	ColorsDark  = "darks"
+	ColorsLight = "lights"
+	ColorsMixed = "colors"
+)
+
+// Machine cycles
+const (
+	CycleNormal   = "normal"
+	CycleDelicate = "delicate"
+)
+
+// defaultTemperature is used for items with no readable wash temperature; cold is always safe
+const defaultTemperature = 30
+
+// temperatureSteps are the washer settings loads are bucketed into, coldest first
+var temperatureSteps = []int{30, 40, 60}
+
+var lightColors = map[string]bool{"White": true, "Beige": true, "Silver": true}
+var darkColors = map[string]bool{"Black": true, "Brown": true, "Grey": true, "Blue": true, "Purple": true}
+
+// Fibers and materials that call for the delicate cycle
+var delicateFibers = []string{"silk", "wool", "cashmere", "merino", "lace", "angora", "mohair"}
+
+// Load is a set of items that can be cleaned together
+type Load struct {
+	Name           string `json:"name"` // e.g. "Darks, 30°C, delicate"
+	Method         string `json:"method"`
+	ColorGroup     string `json:"colorGroup,omitempty"`
+	Cycle          string `json:"cycle,omitempty"`
+	MaxTemperature int    `json:"maxTemperature,omitempty"` // °C, machine loads only
+
+	ItemIDs []string `json:"itemIds"`
+
+	// Items to pull out before the dryer
+	HangDryItemIDs []string `json:"hangDryItemIds"`
+	// Items without care label data, washed with the safest settings
+	UnknownCareItemIDs []string `json:"unknownCareItemIds"`
+}
+
+// GroupLoads splits items into loads with compatible care requirements
+func GroupLoads(items []models.ClothingItem) []Load {
+	loads := make(map[string]*Load)
+
+	for _, item := range items {
+		care := item.Care
+		if care == nil {
+			care = &models.CareInstructions{}
+		}
+
+		load := Load{Method: methodFor(care)}
+		if load.Method != MethodDryClean {
+			load.ColorGroup = colorGroup(item.Colors)
+		}
+		if load.Method == MethodMachine {
+			load.Cycle = CycleNormal
+			if isDelicate(item) {
+				load.Cycle = CycleDelicate
+			}
+			load.MaxTemperature = temperatureStep(care.WashTemperature)
+		}
+
+		key := strings.Join([]string{load.Method, load.ColorGroup, load.Cycle, fmt.Sprint(load.MaxTemperature)}, "|")
+		existing, ok := loads[key]
+		if !ok {
+			load.Name = loadName(load)
+			load.ItemIDs = []string{}
+			load.HangDryItemIDs = []string{}
+			load.UnknownCareItemIDs = []string{}
+			existing = &load
+			loads[key] = existing
+		}
+
+		id := item.ID.Hex()
+		existing.ItemIDs = append(existing.ItemIDs, id)
+		if item.Care == nil {
+			existing.UnknownCareItemIDs = append(existing.UnknownCareItemIDs, id)
+		}
+		if existing.Method == MethodMachine && care.TumbleDry == models.TumbleDryNo {
+			existing.HangDryItemIDs = append(existing.HangDryItemIDs, id)
+		}
+	}
+
+	result := make([]Load, 0, len(loads))
+	for _, load := range loads {
+		result = append(result, *load)
+	}
+
+	// Machine loads first, then by name, so the order is stable
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Method != result[j].Method {
+			return result[i].Method == MethodMachine
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+func methodFor(care *models.CareInstructions) string {
+	switch {
+	case care.DryClean == models.DryCleanOnly || care.WashMethod == models.WashDoNotWash:
+		return MethodDryClean
+	case care.WashMethod == models.WashHand:
+		return MethodHand
+	default:
+		return MethodMachine
+	}
+}
+
+// temperatureStep rounds a label's max temperature down to a washer setting
+func temperatureStep(max int) int {
+	if max == 0 {
+		return defaultTemperature
+	}
+	step := temperatureSteps[0]
+	for _, t := range temperatureSteps {
+		if t <= max {
+			step = t
+		}
+	}
+	return step
+}
+
+func colorGroup(colors []string) string {
+	if len(colors) == 0 {
+		return ColorsMixed
+	}
+	allLight := true
+	for _, color := range colors {
+		if darkColors[color] {
+			return ColorsDark
+		}
+		if !lightColors[color] {
+			allLight = false
+		}
+	}
+	if allLight {
+		return ColorsLight
+	}
+	return ColorsMixed
+}
+
+func isDelicate(item models.ClothingItem) bool {
+	var fibers []string
+	fibers = append(fibers, item.Materials...)
+	if item.Care != nil {
+		for _, fiber := range item.Care.Fibers {
+			fibers = append(fibers, fiber.Fiber)
+		}
+	}
+
+	for _, fiber := range fibers {
+		lower := strings.ToLower(fiber)
+		for _, delicate := range delicateFibers {
+			if strings.Contains(lower, delicate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func loadName(load Load) string {
+	switch load.Method {
+	case MethodDryClean:
+		return "Dry clean"
+	case MethodHand:
+		return "Hand wash, " + load.ColorGroup
+	default:
+		return fmt.Sprintf("%s, %d°C, %s", strings.ToUpper(load.ColorGroup[:1])+load.ColorGroup[1:], load.MaxTemperature, load.Cycle)
+	}
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends transactional email (verification links, password resets)
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Default is the mailer handlers send through, set by Init
+var Default Mailer = &LogMailer{}
+
+// Init picks the mailer from MAILER ("smtp" or "log", default "log")
+func Init() error {
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		m := &SMTPMailer{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("MAIL_FROM"),
+		}
+		if m.Host == "" || m.From == "" {
+			return fmt.Errorf("MAILER=smtp needs SMTP_HOST and MAIL_FROM")
+		}
+		if m.Port == "" {
+			m.Port = "587"
+		}
+		Default = m
+	case "", "log":
+		Default = &LogMailer{Path: os.Getenv("MAIL_LOG_FILE")}
+		log.Println("Mailer: emails are logged, not sent (set MAILER=smtp to send)")
+	default:
+		return fmt.Errorf("unknown MAILER %q", os.Getenv("MAILER"))
+	}
+	return nil
+}
+
+// Send delivers msg through the Default mailer
+func Send(ctx context.Context, msg Message) error {
+	return Default.Send(ctx, msg)
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AdminMiddleware must run after AuthMiddleware. The role is read from the
+// database on every request so demoting an admin takes effect immediately.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		objectID, err := primitive.ObjectIDFromHex(c.GetString("userID"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+			return
+		}
+
+		var user models.User
+		err = database.GetCollection("users").FindOne(c.Request.Context(), bson.M{"_id": objectID}).Decode(&user)
+		if err != nil || !user.IsAdmin() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			return
+		}
+
+		c.Set("role", user.Role)
+		c.Next()
+	}
+}
@@ -1,40 +1,94 @@
 package middleware
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
-	"os"
-	"strings"
+	"time"
 
+	"github.com/exply/armoire/internal/auth"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func AuthMiddleware() gin.HandlerFunc {
+	return authenticate()
+}
+
+// StreamAuthMiddleware is AuthMiddleware that also accepts a stream ticket
+// as ?ticket=, because browsers' EventSource can't set headers. Tickets come
+// from POST /notifications/stream-ticket and work once; access tokens are
+// never accepted in the query string, which ends up in logs.
+func StreamAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticket := c.Query("ticket")
+		if ticket == "" || c.GetHeader("Authorization") != "" {
+			authenticate()(c)
+			return
+		}
+
+		sum := sha256.Sum256([]byte(ticket))
+		var redeemed models.StreamTicket
+		err := database.GetCollection("stream_tickets").FindOneAndDelete(c.Request.Context(), bson.M{
+			"ticket_hash": hex.EncodeToString(sum[:]),
+			"expires_at":  bson.M{"$gt": time.Now()},
+		}).Decode(&redeemed)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired ticket"})
+			return
+		}
+		startSession(c, redeemed.SessionID, redeemed.UserID)
+	}
+}
+
+func authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
 			return
 		}
-
 		// Extract "Bearer <token>"
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-		// Parse Token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
+		tokenString, ok := auth.BearerToken(authHeader)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+			return
+		}
 
-		if err != nil || !token.Valid {
+		// Verify signature, algorithm, issuer, audience and lifetime
+		claims, err := auth.VerifyAccessToken(tokenString)
+		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			return
 		}
 
-		// Set UserID in context so handlers can use it
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Set("userID", claims["sub"])
-		}
+		// Every access token belongs to a session; a revoked session kills the token early
+		startSession(c, claims.SessionID, claims.Subject)
+	}
+}
+
+// startSession checks that the session is still active and belongs to userID,
+// then hands the request on with both set in the context
+func startSession(c *gin.Context, sessionHex, userID string) {
+	sessionID, err := primitive.ObjectIDFromHex(sessionHex)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return
+	}
 
-		c.Next()
+	var session models.Session
+	err = database.GetCollection("sessions").FindOne(c.Request.Context(), bson.M{"_id": sessionID}).Decode(&session)
+	if err != nil || !session.Active() || session.UserID != userID {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked"})
+		return
 	}
+
+	// Set UserID and SessionID in context so handlers can use them
+	c.Set("userID", session.UserID)
+	c.Set("sessionID", session.ID.Hex())
+
+	c.Next()
 }
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/exply/armoire/internal/ai"
+	"github.com/exply/armoire/internal/usage"
+	"github.com/gin-gonic/gin"
+)
+
+// AIQuota must run after AuthMiddleware. It refuses the request once the user
+// has used up this month's quota for any of operations, and books every AI
+// call the handler then makes against the user.
+func AIQuota(operations ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in context"})
+			return
+		}
+
+		err := usage.Check(c.Request.Context(), userID, operations...)
+		var quotaErr *usage.QuotaError
+		if errors.As(err, &quotaErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(time.Until(quotaErr.ResetsAt).Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":    "Monthly AI quota exceeded (" + quotaErr.Quota + "), it resets on " + quotaErr.ResetsAt.Format("January 2"),
+				"quota":    quotaErr.Quota,
+				"limit":    quotaErr.Limit,
+				"used":     quotaErr.Used,
+				"resetsAt": quotaErr.ResetsAt,
+			})
+			return
+		}
+		if err != nil {
+			// Don't lock people out because the usage lookup failed
+			log.Printf("Could not check AI quota for %s: %v", userID, err)
+		}
+
+		c.Request = c.Request.WithContext(ai.WithUsageRecorder(c.Request.Context(), usage.ForUser(userID)))
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/exply/armoire/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit throttles a route group with one token bucket per client IP and,
+// once AuthMiddleware has run, one per user. scope keeps groups' buckets apart;
+// pass a zero ratelimit.Limit to skip either check. The store failing lets
+// requests through rather than taking the API down with it.
+func RateLimit(scope string, perIP, perUser ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !perIP.Disabled() && !take(c, perIP, scope+":ip:"+c.ClientIP()) {
+			return
+		}
+		if userID := c.GetString("userID"); userID != "" && !perUser.Disabled() {
+			if !take(c, perUser, scope+":user:"+userID) {
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+func take(c *gin.Context, limit ratelimit.Limit, key string) bool {
+	result, err := ratelimit.Take(c.Request.Context(), key, limit)
+	if err != nil {
+		log.Printf("Rate limiter unavailable, allowing request: %v", err)
+		return true
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, slow down"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Availability statuses for a clothing item
+const (
+	AvailabilityAvailable   = "available"
+	AvailabilityInLaundry   = "in_laundry"
+	AvailabilityDryCleaner  = "at_dry_cleaner"
+	AvailabilityLent        = "lent"
+	AvailabilityInStorage   = "in_storage"
+	AvailabilityNeedsRepair = "needs_repair"
+)
+
+var AvailabilityStatuses = []string{
+	AvailabilityAvailable, AvailabilityInLaundry, AvailabilityDryCleaner,
+	AvailabilityLent, AvailabilityInStorage, AvailabilityNeedsRepair,
+}
+
+// UnavailableStatuses are excluded from search and recommendations by default.
+// Filters use $nin on these so items saved before statuses existed count as available.
+var UnavailableStatuses = []string{
+	AvailabilityInLaundry, AvailabilityDryCleaner, AvailabilityLent,
+	AvailabilityInStorage, AvailabilityNeedsRepair,
+}
+
+// MaxAvailabilityHistory caps how many transitions are kept per item
+const MaxAvailabilityHistory = 50
+
+type AvailabilityChange struct {
+	Status    string    `bson:"status" json:"status"`
+	Note      string    `bson:"note,omitempty" json:"note,omitempty"`
+	ChangedAt time.Time `bson:"changed_at" json:"changedAt"`
+}
+
+// IsAvailable reports whether the item can be worn or recommended right now
+func (item ClothingItem) IsAvailable() bool {
+	return item.Availability == "" || item.Availability == AvailabilityAvailable
+}
+
+// IsAvailabilityStatus reports whether status is one of AvailabilityStatuses
+func IsAvailabilityStatus(status string) bool {
+	for _, s := range AvailabilityStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Borrow request statuses
+const (
+	BorrowPending   = "pending"   // Waiting for the owner
+	BorrowApproved  = "approved"  // Approved, period not started yet
+	BorrowActive    = "active"    // Item is lent out
+	BorrowReturned  = "returned"  // Period over or owner marked it returned
+	BorrowDeclined  = "declined"  // Owner said no
+	BorrowCancelled = "cancelled" // Borrower withdrew, the item was deleted, or it never became available
+)
+
+// BorrowBlockingStatuses hold an item's dates; new requests may not overlap them
+var BorrowBlockingStatuses = []string{BorrowPending, BorrowApproved, BorrowActive}
+
+// BorrowRequest asks to borrow another user's item for a range of whole days (UTC)
+type BorrowRequest struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ItemID     primitive.ObjectID `bson:"item_id" json:"itemId"`
+	OwnerID    string             `bson:"owner_id" json:"ownerId"`
+	BorrowerID string             `bson:"borrower_id" json:"borrowerId"`
+
+	// First and last day of the loan, both inclusive, at midnight UTC
+	StartDate time.Time `bson:"start_date" json:"startDate"`
+	EndDate   time.Time `bson:"end_date" json:"endDate"`
+
+	Message string `bson:"message,omitempty" json:"message,omitempty"`
+	Status  string `bson:"status" json:"status"`
+
+	CreatedAt   time.Time  `bson:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time  `bson:"updated_at" json:"updatedAt"`
+	RespondedAt *time.Time `bson:"responded_at,omitempty" json:"respondedAt,omitempty"`
+	LentAt      *time.Time `bson:"lent_at,omitempty" json:"lentAt,omitempty"`
+	ReturnedAt  *time.Time `bson:"returned_at,omitempty" json:"returnedAt,omitempty"`
+
+	// Set while an approved loan can't start because the owner has the item
+	// marked unavailable
+	StartBlockedAt *time.Time `bson:"start_blocked_at,omitempty" json:"startBlockedAt,omitempty"`
+}
+
+// Ends is when the loan period is over: midnight after the last day
+func (r BorrowRequest) Ends() time.Time {
+	return r.EndDate.AddDate(0, 0, 1)
+}
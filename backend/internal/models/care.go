@@ -0,0 +1,58 @@
+package models
+
+// Wash methods read off a care label
+const (
+	WashMachine   = "machine"
+	WashHand      = "hand"
+	WashDoNotWash = "do_not_wash"
+)
+
+// Tumble dry settings
+const (
+	TumbleDryNormal = "normal"
+	TumbleDryLow    = "low"
+	TumbleDryNo     = "do_not_tumble_dry"
+)
+
+// Iron settings
+const (
+	IronLow    = "low"
+	IronMedium = "medium"
+	IronHigh   = "high"
+	IronNo     = "do_not_iron"
+)
+
+// Dry clean settings
+const (
+	DryCleanAllowed = "allowed"
+	DryCleanOnly    = "dry_clean_only"
+	DryCleanNo      = "do_not_dry_clean"
+)
+
+// Bleach settings
+const (
+	BleachAny         = "any"
+	BleachNonChlorine = "non_chlorine"
+	BleachNo          = "do_not_bleach"
+)
+
+type FiberContent struct {
+	Fiber   string `bson:"fiber" json:"fiber"`     // e.g., "Cotton"
+	Percent int    `bson:"percent" json:"percent"` // 0 when the label doesn't say
+}
+
+// CareInstructions is the structured reading of a garment's care label.
+// Empty strings mean the symbol was not present or not legible.
+type CareInstructions struct {
+	Fibers []FiberContent `bson:"fibers" json:"fibers"`
+
+	WashMethod      string `bson:"wash_method" json:"washMethod"`
+	WashTemperature int    `bson:"wash_temperature" json:"washTemperature"` // Max °C, 0 if unknown
+	Bleach          string `bson:"bleach" json:"bleach"`
+	TumbleDry       string `bson:"tumble_dry" json:"tumbleDry"`
+	Iron            string `bson:"iron" json:"iron"`
+	DryClean        string `bson:"dry_clean" json:"dryClean"`
+
+	// Whatever text the model could read, kept for reference
+	LabelText string `bson:"label_text" json:"labelText"`
+}
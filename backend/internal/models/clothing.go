@@ -15,6 +15,10 @@ type ClothingItem struct {
 	GCSURI       string `bson:"gcs_uri" json:"-"`          // gs:// path for Gemini API (internal use)
 	ThumbnailURL string `bson:"thumbnail_url" json:"thumbnailUrl"`
 
+	// Optional second photo of the care label
+	CareLabelURL    string `bson:"care_label_url,omitempty" json:"careLabelUrl,omitempty"`
+	CareLabelGCSURI string `bson:"care_label_gcs_uri,omitempty" json:"-"`
+
 	// Basic Metadata
 	Name        string `bson:"name" json:"name"`                // e.g., "Vintage Denim Jacket"
 	Category    string `bson:"category" json:"category"`        // e.g., "Outerwear", "Top", "Bottom"
@@ -30,6 +34,46 @@ type ClothingItem struct {
 	Seasons   []string `bson:"seasons" json:"seasons"`     // Winter, Summer
 	Occasions []string `bson:"occasions" json:"occasions"` // Casual, Formal
 
+	// Garment attributes (values from the taxonomy attribute lists)
+	Materials    []string `bson:"materials" json:"materials"`        // Cotton, Wool
+	Pattern      string   `bson:"pattern" json:"pattern"`            // Striped, Plaid, Floral
+	Fit          string   `bson:"fit" json:"fit"`                    // Slim, Relaxed
+	Neckline     string   `bson:"neckline" json:"neckline"`          // Crew, V-Neck
+	SleeveLength string   `bson:"sleeve_length" json:"sleeveLength"` // Short, Long
+
+	// Text read off the garment's logo and labels
+	BrandText string `bson:"brand_text" json:"brandText"`
+	SizeLabel string `bson:"size_label" json:"sizeLabel"`
+
+	// Purchase details, when known
+	Price     float64 `bson:"price,omitempty" json:"price,omitempty"`
+	Currency  string  `bson:"currency,omitempty" json:"currency,omitempty"`    // ISO 4217, e.g. "EUR"
+	SourceURL string  `bson:"source_url,omitempty" json:"sourceUrl,omitempty"` // Shop page it was imported from
+
+	PurchaseDate *time.Time `bson:"purchase_date,omitempty" json:"purchaseDate,omitempty"`
+
+	// Drafts are created from receipts and wait for a photo; until one is
+	// added they have no image, embedding or AI tags
+	Draft bool `bson:"draft,omitempty" json:"draft,omitempty"`
+
+	// Read from the care label photo, if one was uploaded
+	Care *CareInstructions `bson:"care,omitempty" json:"care,omitempty"`
+
+	// Whether the item is in the closet right now (see AvailabilityStatuses)
+	Availability          string               `bson:"availability" json:"availability"`
+	AvailabilityChangedAt time.Time            `bson:"availability_changed_at" json:"availabilityChangedAt"`
+	AvailabilityHistory   []AvailabilityChange `bson:"availability_history,omitempty" json:"availabilityHistory,omitempty"`
+
+	// Bumped inside every borrow request transaction so concurrent ones conflict
+	LoanSeq int64 `bson:"loan_seq,omitempty" json:"-"`
+
+	// Set when AI tagging could not be validated against the taxonomy
+	NeedsReview bool     `bson:"needs_review" json:"needsReview"`
+	ReviewNotes []string `bson:"review_notes,omitempty" json:"reviewNotes,omitempty"`
+
+	// Comment and reaction counters
+	Engagement `bson:",inline"`
+
 	// Timestamps
 	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
 	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReactionEmojis are the reactions people can leave. They double as keys of
+// Engagement.ReactionCounts, so none may contain "." or start with "$".
+var ReactionEmojis = []string{"❤️", "🔥", "😍", "👍", "😂", "👀", "✨", "💯"}
+
+// Engagement holds the comment and reaction counters kept on public items and
+// outfits. The counters are updated together with the comments and reactions
+// themselves, so listings never count on the fly.
+type Engagement struct {
+	CommentCount     int64            `bson:"comment_count,omitempty" json:"commentCount"`
+	ReactionCounts   map[string]int64 `bson:"reaction_counts,omitempty" json:"reactionCounts,omitempty"`
+	CommentsDisabled bool             `bson:"comments_disabled,omitempty" json:"commentsDisabled"`
+}
+
+// Comment is left on another user's (or your own) public item or outfit
+type Comment struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TargetType string             `bson:"target_type" json:"targetType"` // item or outfit
+	TargetID   primitive.ObjectID `bson:"target_id" json:"targetId"`
+	OwnerID    string             `bson:"owner_id" json:"ownerId"` // Owner of the target, who moderates
+	UserID     string             `bson:"user_id" json:"userId"`   // Author
+	Body       string             `bson:"body" json:"body"`
+	CreatedAt  time.Time          `bson:"created_at" json:"createdAt"`
+}
+
+// Reaction is one user's emoji on a target; each user can leave each emoji once
+type Reaction struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TargetType string             `bson:"target_type" json:"targetType"`
+	TargetID   primitive.ObjectID `bson:"target_id" json:"targetId"`
+	OwnerID    string             `bson:"owner_id" json:"ownerId"`
+	UserID     string             `bson:"user_id" json:"userId"`
+	Emoji      string             `bson:"emoji" json:"emoji"`
+	CreatedAt  time.Time          `bson:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	FollowAccepted = "accepted"
+	FollowPending  = "pending" // Waiting for a non-public profile's owner to approve
+)
+
+// Follow is a one-way edge in the social graph: FollowerID follows FolloweeID
+type Follow struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	FollowerID string             `bson:"follower_id" json:"followerId"`
+	FolloweeID string             `bson:"followee_id" json:"followeeId"`
+	Status     string             `bson:"status" json:"status"`
+	CreatedAt  time.Time          `bson:"created_at" json:"createdAt"`
+	AcceptedAt *time.Time         `bson:"accepted_at,omitempty" json:"acceptedAt,omitempty"`
+}
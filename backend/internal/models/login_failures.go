@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// LoginFailures counts failed password logins for an email address, whether
+// or not an account uses it, so a lockout doesn't reveal which ones do
+type LoginFailures struct {
+	Email       string     `bson:"_id"`
+	Count       int        `bson:"count"`
+	LastFailure time.Time  `bson:"last_failure"`
+	LockedUntil *time.Time `bson:"locked_until,omitempty"`
+}
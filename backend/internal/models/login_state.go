@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// LoginState remembers an in-flight OIDC login between the redirect and the callback.
+// It is deleted as soon as the callback uses it.
+type LoginState struct {
+	State        string    `bson:"_id"`
+	Provider     string    `bson:"provider"`
+	CodeVerifier string    `bson:"code_verifier"` // PKCE
+	Nonce        string    `bson:"nonce"`
+	ExpiresAt    time.Time `bson:"expires_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification types
+const (
+	NotifyNewFollower     = "new_follower"
+	NotifyFollowRequest   = "follow_request"
+	NotifyFollowAccepted  = "follow_accepted"
+	NotifyComment         = "comment"
+	NotifyReaction        = "reaction"
+	NotifyBorrowRequest   = "borrow_request"
+	NotifyBorrowApproved  = "borrow_approved"
+	NotifyBorrowDeclined  = "borrow_declined"
+	NotifyBorrowCancelled = "borrow_cancelled"
+	NotifyBorrowReturned  = "borrow_returned"
+	NotifyBorrowDelayed   = "borrow_delayed"
+	NotifyUploadComplete  = "upload_complete"
+)
+
+// Notification tells a user something happened. The actor's name is copied in
+// so lists and live events can be shown without looking anyone up.
+type Notification struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"userId"` // Recipient
+	Type   string             `bson:"type" json:"type"`
+
+	// Who caused it; empty for system events such as a finished upload
+	ActorID   string `bson:"actor_id,omitempty" json:"actorId,omitempty"`
+	ActorName string `bson:"actor_name,omitempty" json:"actorName,omitempty"`
+
+	// What it is about, e.g. "item" and the item's ID, for deep links
+	TargetType string `bson:"target_type,omitempty" json:"targetType,omitempty"`
+	TargetID   string `bson:"target_id,omitempty" json:"targetId,omitempty"`
+
+	Text      string     `bson:"text" json:"text"` // e.g. "Sam commented on Denim Jacket"
+	ReadAt    *time.Time `bson:"read_at,omitempty" json:"readAt,omitempty"`
+	CreatedAt time.Time  `bson:"created_at" json:"createdAt"`
+}
@@ -18,5 +18,12 @@ type Outfit struct {
 	// e.g., "Cozy study session fit"
 	VibeTags []string `bson:"vibe_tags" json:"vibeTags"`
 
+	// Shown on the owner's profile and in followers' feeds
+	IsPublic bool `bson:"is_public" json:"isPublic"`
+
+	// Comment and reaction counters
+	Engagement `bson:",inline"`
+
 	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
 }
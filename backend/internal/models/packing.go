@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TripWeather is the weather expected at a trip's destination
+type TripWeather struct {
+	MinTemp float64 `bson:"min_temp" json:"minTemp" example:"12"` // °C
+	MaxTemp float64 `bson:"max_temp" json:"maxTemp" example:"21"` // °C
+	Rain    bool    `bson:"rain" json:"rain"`
+}
+
+// PackingActivity is something planned on a trip, e.g. two Formal dinners
+type PackingActivity struct {
+	Occasion string `bson:"occasion" json:"occasion" example:"Formal"` // One of the taxonomy occasions
+	Days     int    `bson:"days" json:"days" example:"2"`              // 0 means every day
+}
+
+// PackingItem is one line of a packing checklist
+type PackingItem struct {
+	ItemID primitive.ObjectID `bson:"item_id" json:"itemId"`
+
+	// Copied from the item so the checklist still reads well if it is deleted
+	Name     string `bson:"name" json:"name"`
+	Category string `bson:"category" json:"category"`
+
+	// e.g. "In the laundry, wash it before you go"
+	Note string `bson:"note,omitempty" json:"note,omitempty"`
+
+	Packed   bool       `bson:"packed" json:"packed"`
+	PackedAt *time.Time `bson:"packed_at,omitempty" json:"packedAt,omitempty"`
+}
+
+// PackingOutfit is one outfit to wear on a day of the trip
+type PackingOutfit struct {
+	Occasion string               `bson:"occasion" json:"occasion"`
+	ItemIDs  []primitive.ObjectID `bson:"item_ids" json:"itemIds"`
+}
+
+// PackingDay is a day of the trip, what's planned and what to wear for it
+type PackingDay struct {
+	Date      time.Time       `bson:"date" json:"date"` // Midnight UTC
+	Occasions []string        `bson:"occasions" json:"occasions"`
+	Outfits   []PackingOutfit `bson:"outfits" json:"outfits"`
+}
+
+// PackingList is a capsule of items picked for a trip, as a checklist
+type PackingList struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"userId"`
+
+	Name        string `bson:"name" json:"name"`
+	Destination string `bson:"destination,omitempty" json:"destination,omitempty"`
+
+	// First and last day of the trip, both inclusive, at midnight UTC
+	StartDate time.Time `bson:"start_date" json:"startDate"`
+	EndDate   time.Time `bson:"end_date" json:"endDate"`
+
+	Activities []PackingActivity `bson:"activities" json:"activities"`
+	Weather    *TripWeather      `bson:"weather,omitempty" json:"weather,omitempty"`
+
+	Items []PackingItem `bson:"items" json:"items"`
+	Days  []PackingDay  `bson:"days" json:"days"`
+
+	// How many different outfits the packed items make, per occasion
+	Combinations map[string]int `bson:"combinations" json:"combinations"`
+	// Occasions no outfit could be put together for from the closet
+	Uncovered []string `bson:"uncovered" json:"uncovered"`
+
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PlanEntry puts an outfit, or an ad-hoc list of items, on a day of the planner
+type PlanEntry struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"userId"`
+
+	// The day, at midnight UTC
+	Date time.Time `bson:"date" json:"date"`
+
+	// Exactly one of OutfitID and ItemIDs is set
+	OutfitID *primitive.ObjectID  `bson:"outfit_id,omitempty" json:"outfitId,omitempty"`
+	ItemIDs  []primitive.ObjectID `bson:"item_ids,omitempty" json:"itemIds,omitempty"`
+
+	Notes string `bson:"notes,omitempty" json:"notes,omitempty"` // e.g. "Dinner with Sam's parents"
+
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+}
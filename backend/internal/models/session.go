@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session backs a refresh token. Access tokens carry the session ID as "sid"
+// so revoking the session invalidates them too.
+type Session struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"userId"`
+
+	// SHA-256 of the current refresh token secret, and of the one it replaced
+	// (a replayed previous token means the token leaked)
+	RefreshTokenHash  string `bson:"refresh_token_hash" json:"-"`
+	PreviousTokenHash string `bson:"previous_token_hash,omitempty" json:"-"`
+
+	UserAgent string `bson:"user_agent" json:"userAgent"`
+	IP        string `bson:"ip" json:"ip"`
+
+	CreatedAt  time.Time  `bson:"created_at" json:"createdAt"`
+	LastUsedAt time.Time  `bson:"last_used_at" json:"lastUsedAt"`
+	ExpiresAt  time.Time  `bson:"expires_at" json:"expiresAt"`
+	RevokedAt  *time.Time `bson:"revoked_at,omitempty" json:"revokedAt,omitempty"`
+}
+
+// Active reports whether the session can still be used
+func (s Session) Active() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// StreamTicket lets an EventSource, which can't send headers, open a
+// notification stream. It is single-use and short-lived so it's harmless in
+// access logs. Only the SHA-256 of the ticket is stored.
+type StreamTicket struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	TicketHash string             `bson:"ticket_hash"`
+	UserID     string             `bson:"user_id"`
+	SessionID  string             `bson:"session_id"`
+	ExpiresAt  time.Time          `bson:"expires_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// What a share link can point at
+const (
+	ShareItem       = "item"
+	ShareOutfit     = "outfit"
+	ShareCollection = "collection"
+)
+
+var ShareResourceTypes = []string{ShareItem, ShareOutfit, ShareCollection}
+
+// ShareLink grants anonymous read access to one resource through an
+// unguessable token. Only the token's hash is stored.
+type ShareLink struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       string             `bson:"user_id" json:"userId"`
+	ResourceType string             `bson:"resource_type" json:"resourceType"`
+	ResourceID   primitive.ObjectID `bson:"resource_id" json:"resourceId"`
+	TokenHash    string             `bson:"token_hash" json:"-"`
+
+	CreatedAt time.Time  `bson:"created_at" json:"createdAt"`
+	ExpiresAt *time.Time `bson:"expires_at,omitempty" json:"expiresAt,omitempty"` // nil never expires
+	RevokedAt *time.Time `bson:"revoked_at,omitempty" json:"revokedAt,omitempty"`
+
+	MaxViews     int        `bson:"max_views" json:"maxViews"` // 0 is unlimited
+	Views        int        `bson:"views" json:"views"`
+	LastViewedAt *time.Time `bson:"last_viewed_at,omitempty" json:"lastViewedAt,omitempty"`
+}
+
+// Active reports whether the link can still be opened
+func (l ShareLink) Active() bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	if l.ExpiresAt != nil && !time.Now().Before(*l.ExpiresAt) {
+		return false
+	}
+	return l.MaxViews == 0 || l.Views < l.MaxViews
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UsageCounter totals one user's AI usage for one operation over a calendar month (UTC)
+type UsageCounter struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	UserID    string             `bson:"user_id" json:"userId,omitempty"`
+	Period    string             `bson:"period" json:"period"` // "2006-01"
+	Operation string             `bson:"operation" json:"operation"`
+
+	Calls        int64 `bson:"calls" json:"calls"`
+	PromptTokens int64 `bson:"prompt_tokens" json:"promptTokens"`
+	OutputTokens int64 `bson:"output_tokens" json:"outputTokens"`
+	TotalTokens  int64 `bson:"total_tokens" json:"totalTokens"`
+	Credits      int64 `bson:"credits" json:"credits"` // Clipdrop credits
+
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+}
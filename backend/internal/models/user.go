@@ -1,15 +1,83 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// NormalizeEmail is the form emails are stored and looked up in, so the same
+// address can't end up on two accounts by differing in case
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+var Roles = []string{RoleUser, RoleAdmin}
+
+// Who can see a user's public items and outfits
+const (
+	ProfilePublic    = "public"    // Anyone signed in; follows are accepted right away
+	ProfileFollowers = "followers" // Approved followers only
+	ProfilePrivate   = "private"   // Nobody but the owner
+)
+
+var ProfileVisibilities = []string{ProfilePublic, ProfileFollowers, ProfilePrivate}
+
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Email     string             `bson:"email" json:"email"`
-	Password  string             `bson:"password" json:"-"` // Store hash, never return in JSON
-	Name      string             `bson:"name" json:"name"`
-	CreatedAt time.Time          `bson:"created_at" json:"createdAt"`
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email    string             `bson:"email" json:"email"`
+	Password string             `bson:"password" json:"-"` // Store hash, never return in JSON
+	Name     string             `bson:"name" json:"name"`
+
+	AvatarURL    string `bson:"avatar_url,omitempty" json:"avatarUrl,omitempty"`
+	AvatarGCSURI string `bson:"avatar_gcs_uri,omitempty" json:"-"`
+
+	// Empty means ProfilePublic
+	ProfileVisibility string `bson:"profile_visibility,omitempty" json:"profileVisibility"`
+
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+
+	// Empty means RoleUser
+	Role string `bson:"role,omitempty" json:"role"`
+
+	// Disabled accounts can't sign in; their sessions are revoked when disabled
+	Disabled       bool       `bson:"disabled,omitempty" json:"disabled,omitempty"`
+	DisabledAt     *time.Time `bson:"disabled_at,omitempty" json:"disabledAt,omitempty"`
+	DisabledReason string     `bson:"disabled_reason,omitempty" json:"disabledReason,omitempty"`
+
+	EmailVerified   bool       `bson:"email_verified" json:"emailVerified"`
+	EmailVerifiedAt *time.Time `bson:"email_verified_at,omitempty" json:"emailVerifiedAt,omitempty"`
+
+	// Hash of the secret in the planner's calendar feed URL; empty when the feed is off
+	CalendarFeedHash string `bson:"calendar_feed_hash,omitempty" json:"-"`
+
+	// External accounts (OIDC) linked to this user. Users created through
+	// social login have no password until they set one.
+	Identities []Identity `bson:"identities,omitempty" json:"identities,omitempty"`
+}
+
+// Identity links a user to an account at an OIDC provider
+type Identity struct {
+	Provider string    `bson:"provider" json:"provider"`
+	Subject  string    `bson:"subject" json:"-"` // The provider's stable user ID ("sub")
+	Email    string    `bson:"email" json:"email"`
+	LinkedAt time.Time `bson:"linked_at" json:"linkedAt"`
+}
+
+func (u User) IsAdmin() bool {
+	return u.Role == RoleAdmin && !u.Disabled
+}
+
+// Visibility returns the profile visibility, defaulting to public
+func (u User) Visibility() string {
+	if u.ProfileVisibility == "" {
+		return ProfilePublic
+	}
+	return u.ProfileVisibility
 }
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WishlistItem is something the user wants to buy
+type WishlistItem struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"userId"`
+
+	Name string `bson:"name" json:"name"`
+	Link string `bson:"link,omitempty" json:"link,omitempty"` // Shop page
+
+	Price    float64 `bson:"price,omitempty" json:"price,omitempty"`
+	Currency string  `bson:"currency,omitempty" json:"currency,omitempty"` // ISO 4217, e.g. "EUR"
+
+	// Photo, processed like a closet upload; empty if none was given
+	ImageURL     string `bson:"image_url,omitempty" json:"imageUrl,omitempty"`
+	GCSURI       string `bson:"gcs_uri,omitempty" json:"-"`
+	ThumbnailURL string `bson:"thumbnail_url,omitempty" json:"thumbnailUrl,omitempty"`
+
+	// Tags, from the user or from AI tagging of the photo
+	Category    string   `bson:"category" json:"category"`
+	SubCategory string   `bson:"sub_category" json:"subCategory"`
+	Colors      []string `bson:"colors" json:"colors"`
+	Seasons     []string `bson:"seasons" json:"seasons"`
+	Occasions   []string `bson:"occasions" json:"occasions"`
+	Pattern     string   `bson:"pattern" json:"pattern"`
+	Description string   `bson:"description" json:"description"`
+
+	// Compared against the closet's embeddings to spot near-duplicates
+	Embedding []float32 `bson:"embedding" json:"-"`
+
+	Notes string `bson:"notes,omitempty" json:"notes,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt"`
+}
+
+// AsClothingItem is the item as it would be in the closet, for outfit matching
+func (w WishlistItem) AsClothingItem() ClothingItem {
+	return ClothingItem{
+		ID:          w.ID,
+		Name:        w.Name,
+		Category:    w.Category,
+		SubCategory: w.SubCategory,
+		Colors:      w.Colors,
+		Seasons:     w.Seasons,
+		Occasions:   w.Occasions,
+		Pattern:     w.Pattern,
+	}
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"sync"
+
+	"github.com/exply/armoire/internal/models"
+)
+
+// subscriberBuffer is how many events a slow subscriber may fall behind
+// before further events are dropped for it. Dropped events are still in the
+// notifications collection, so a client catches up on reconnect.
+const subscriberBuffer = 16
+
+// Broker fans notifications out to the connections of their recipient. Hub
+// keeps everything in this process; a broker such as Redis pub/sub can stand
+// in when the API runs on several instances.
+type Broker interface {
+	Publish(n models.Notification)
+	// Subscribe delivers the user's notifications until cancel is called
+	Subscribe(userID string) (events <-chan models.Notification, cancel func())
+}
+
+// Default is the broker Send publishes to
+var Default Broker = NewHub()
+
+// Hub is an in-process Broker
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[string]map[chan models.Notification]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: map[string]map[chan models.Notification]struct{}{}}
+}
+
+func (h *Hub) Publish(n models.Notification) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subs[n.UserID] {
+		select {
+		case ch <- n:
+		default:
+		}
+	}
+}
+
+func (h *Hub) Subscribe(userID string) (<-chan models.Notification, func()) {
+	ch := make(chan models.Notification, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = map[chan models.Notification]struct{}{}
+	}
+	h.subs[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs[userID], ch)
+			if len(h.subs[userID]) == 0 {
+				delete(h.subs, userID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Collection holds every user's notifications
+const Collection = "notifications"
+
+// Send stores n and pushes it to the recipient's open connections. Nobody is
+// notified about their own actions. Failures are logged, never returned: a
+// notification must not fail the request that caused it.
+func Send(ctx context.Context, n models.Notification) {
+	if n.UserID == "" || (n.ActorID != "" && n.ActorID == n.UserID) {
+		return
+	}
+	n.ID = primitive.NewObjectID()
+	n.CreatedAt = time.Now()
+
+	if _, err := database.GetCollection(Collection).InsertOne(ctx, n); err != nil {
+		log.Printf("Could not store %s notification for %s: %v", n.Type, n.UserID, err)
+		return
+	}
+	Default.Publish(n)
+}
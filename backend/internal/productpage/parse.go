@@ -0,0 +1,297 @@
+package productpage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ErrNoProduct is returned for pages without any product metadata
+var ErrNoProduct = errors.New("no product found on the page")
+
+// Product is what a shop page says about the product it sells
+type Product struct {
+	Title       string  `json:"title"`
+	Brand       string  `json:"brand,omitempty"`
+	Description string  `json:"description,omitempty"`
+	ImageURL    string  `json:"imageUrl,omitempty"` // Absolute
+	Price       float64 `json:"price,omitempty"`
+	Currency    string  `json:"currency,omitempty"`
+	URL         string  `json:"url"` // Canonical URL if the page gives one
+}
+
+// Parse reads product details from a page's JSON-LD Product data, falling
+// back to OpenGraph (and product:) meta tags and then the page title for
+// anything JSON-LD doesn't have. pageURL resolves relative links.
+func Parse(page []byte, pageURL string) (*Product, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	root, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		meta      = map[string]string{}
+		jsonLD    []string
+		title     string
+		canonical string
+	)
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "meta":
+				key := strings.ToLower(attr(n, "property"))
+				if key == "" {
+					key = strings.ToLower(attr(n, "name"))
+				}
+				// The first value wins, as pages often repeat tags for variants
+				if _, seen := meta[key]; key != "" && !seen {
+					meta[key] = strings.TrimSpace(attr(n, "content"))
+				}
+			case "script":
+				if strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") && n.FirstChild != nil {
+					jsonLD = append(jsonLD, n.FirstChild.Data)
+				}
+			case "title":
+				if title == "" && n.FirstChild != nil {
+					title = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case "link":
+				if canonical == "" && strings.EqualFold(attr(n, "rel"), "canonical") {
+					canonical = attr(n, "href")
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	product := &Product{}
+	found := false
+	for _, block := range jsonLD {
+		var data interface{}
+		if json.Unmarshal([]byte(block), &data) != nil {
+			continue
+		}
+		if p := findProduct(data); p != nil {
+			fromJSONLD(product, p)
+			found = true
+			break
+		}
+	}
+
+	if meta["og:type"] == "product" || meta["og:type"] == "og:product" || meta["product:price:amount"] != "" || meta["og:price:amount"] != "" {
+		found = true
+	}
+	fillString(&product.Title, meta["og:title"], meta["twitter:title"], title)
+	fillString(&product.Description, meta["og:description"], meta["description"])
+	fillString(&product.ImageURL, meta["og:image:secure_url"], meta["og:image"], meta["og:image:url"], meta["twitter:image"])
+	fillString(&product.Brand, meta["product:brand"], meta["og:brand"])
+	fillString(&product.Currency, meta["product:price:currency"], meta["og:price:currency"])
+	if product.Price == 0 {
+		product.Price = parsePrice(firstNonEmpty(meta["product:price:amount"], meta["og:price:amount"]))
+	}
+	fillString(&product.URL, canonical, meta["og:url"], pageURL)
+
+	// An og:image alone is on every article and home page, not just products
+	if !found {
+		return nil, ErrNoProduct
+	}
+
+	product.ImageURL = resolve(base, product.ImageURL)
+	product.URL = resolve(base, product.URL)
+	product.Currency = strings.ToUpper(product.Currency)
+	return product, nil
+}
+
+// findProduct looks for a schema.org Product in decoded JSON-LD, which may be
+// a single object, an array, or an object with an @graph
+func findProduct(data interface{}) map[string]interface{} {
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if p := findProduct(item); p != nil {
+				return p
+			}
+		}
+	case map[string]interface{}:
+		if hasType(v, "Product") || hasType(v, "ProductGroup") {
+			return v
+		}
+		if graph, ok := v["@graph"]; ok {
+			return findProduct(graph)
+		}
+		// e.g. a WebPage whose mainEntity is the product
+		if entity, ok := v["mainEntity"]; ok {
+			return findProduct(entity)
+		}
+	}
+	return nil
+}
+
+func fromJSONLD(product *Product, p map[string]interface{}) {
+	product.Title = text(p["name"])
+	product.Description = text(p["description"])
+	product.Brand = name(p["brand"])
+	product.ImageURL = image(p["image"])
+	product.URL = text(p["url"])
+
+	offers := p["offers"]
+	if list, ok := offers.([]interface{}); ok && len(list) > 0 {
+		offers = list[0]
+	}
+	if offer, ok := offers.(map[string]interface{}); ok {
+		product.Price = number(offer["price"])
+		if product.Price == 0 {
+			product.Price = number(offer["lowPrice"])
+		}
+		product.Currency = text(offer["priceCurrency"])
+		// Some shops nest the price in a PriceSpecification
+		if spec, ok := offer["priceSpecification"].(map[string]interface{}); ok && product.Price == 0 {
+			product.Price = number(spec["price"])
+			if product.Currency == "" {
+				product.Currency = text(spec["priceCurrency"])
+			}
+		}
+	}
+
+	// A ProductGroup's details may only be on its variants
+	if variants, ok := p["hasVariant"].([]interface{}); ok && len(variants) > 0 {
+		if variant, ok := variants[0].(map[string]interface{}); ok {
+			var v Product
+			fromJSONLD(&v, variant)
+			fillString(&product.Title, v.Title)
+			fillString(&product.ImageURL, v.ImageURL)
+			fillString(&product.Brand, v.Brand)
+			fillString(&product.Currency, v.Currency)
+			if product.Price == 0 {
+				product.Price = v.Price
+			}
+		}
+	}
+}
+
+func hasType(v map[string]interface{}, want string) bool {
+	switch t := v["@type"].(type) {
+	case string:
+		return t == want || strings.HasSuffix(t, "/"+want)
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok && (s == want || strings.HasSuffix(s, "/"+want)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func text(v interface{}) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(html.UnescapeString(s))
+}
+
+// name reads a value that's either a string or an object with a name, like a Brand
+func name(v interface{}) string {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return text(t["name"])
+	case []interface{}:
+		if len(t) > 0 {
+			return name(t[0])
+		}
+	}
+	return text(v)
+}
+
+// image reads the first image of a value that's a URL, an ImageObject or a list of either
+func image(v interface{}) string {
+	switch t := v.(type) {
+	case []interface{}:
+		if len(t) > 0 {
+			return image(t[0])
+		}
+	case map[string]interface{}:
+		return firstNonEmpty(text(t["url"]), text(t["contentUrl"]))
+	}
+	return text(v)
+}
+
+func number(v interface{}) float64 {
+	switch t := v.(type) {
+	case float64:
+		return t
+	case string:
+		return parsePrice(t)
+	}
+	return 0
+}
+
+// parsePrice reads amounts like "59.95", "1,299.00", "1,299" or "59,95"
+func parsePrice(s string) float64 {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	i, j := strings.LastIndex(s, ","), strings.LastIndex(s, ".")
+	// Without a dot, a comma followed by exactly three digits separates thousands
+	thousands := j < 0 && i >= 0 && len(s)-i-1 == 3 && strings.Trim(s[i+1:], "0123456789") == ""
+	if i > j && !thousands {
+		// A comma after any dot is the decimal separator
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	price, err := strconv.ParseFloat(s, 64)
+	if err != nil || price < 0 {
+		return 0
+	}
+	return price
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func resolve(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
+
+// fillString sets *dst to the first non-empty value, if it's still empty
+func fillString(dst *string, values ...string) {
+	if *dst == "" {
+		*dst = firstNonEmpty(values...)
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package productpage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want Product
+	}{
+		{
+			name: "JSON-LD Product",
+			page: `<html><head><title>Shop</title>
+<script type="application/ld+json">{
+	"@context": "https://schema.org",
+	"@type": "Product",
+	"name": "Wool Coat",
+	"description": "A warm coat",
+	"brand": {"@type": "Brand", "name": "Acme"},
+	"image": ["/img/coat.jpg", "/img/coat-back.jpg"],
+	"url": "/p/wool-coat",
+	"offers": {"@type": "Offer", "price": "1,299", "priceCurrency": "sek"}
+}</script></head><body></body></html>`,
+			want: Product{
+				Title:       "Wool Coat",
+				Brand:       "Acme",
+				Description: "A warm coat",
+				ImageURL:    "https://shop.example/img/coat.jpg",
+				Price:       1299,
+				Currency:    "SEK",
+				URL:         "https://shop.example/p/wool-coat",
+			},
+		},
+		{
+			name: "JSON-LD @graph",
+			page: `<html><head>
+<script type="application/ld+json">{
+	"@context": "https://schema.org",
+	"@graph": [
+		{"@type": "WebSite", "name": "Shop"},
+		{"@type": ["Thing", "Product"], "name": "Linen Shirt", "brand": "Acme",
+		 "image": {"@type": "ImageObject", "url": "https://cdn.example/shirt.jpg"},
+		 "offers": [{"@type": "Offer", "price": 49.5, "priceCurrency": "EUR"}]}
+	]
+}</script></head></html>`,
+			want: Product{
+				Title:    "Linen Shirt",
+				Brand:    "Acme",
+				ImageURL: "https://cdn.example/shirt.jpg",
+				Price:    49.5,
+				Currency: "EUR",
+				URL:      "https://shop.example/item?id=1",
+			},
+		},
+		{
+			name: "ProductGroup with variants",
+			page: `<html><head>
+<link rel="canonical" href="https://shop.example/jeans">
+<script type="application/ld+json">{
+	"@type": "ProductGroup",
+	"name": "Slim Jeans",
+	"hasVariant": [
+		{"@type": "Product", "name": "Slim Jeans 32/32", "image": "/img/jeans-32.jpg",
+		 "offers": {"@type": "Offer", "priceSpecification": {"price": "89.00", "priceCurrency": "USD"}}}
+	]
+}</script></head></html>`,
+			want: Product{
+				Title:    "Slim Jeans",
+				ImageURL: "https://shop.example/img/jeans-32.jpg",
+				Price:    89,
+				Currency: "USD",
+				URL:      "https://shop.example/jeans",
+			},
+		},
+		{
+			name: "OpenGraph fallback",
+			page: `<html><head><title>Striped Tee | Shop</title>
+<script type="application/ld+json">{not json</script>
+<meta property="og:type" content="product">
+<meta property="og:title" content="Striped Tee">
+<meta property="og:image" content="/img/tee.jpg">
+<meta property="og:image" content="/img/tee-red.jpg">
+<meta property="product:brand" content="Acme">
+<meta property="product:price:amount" content="59,95">
+<meta property="product:price:currency" content="eur">
+<meta property="og:url" content="https://shop.example/tee">
+</head></html>`,
+			want: Product{
+				Title:    "Striped Tee",
+				Brand:    "Acme",
+				ImageURL: "https://shop.example/img/tee.jpg",
+				Price:    59.95,
+				Currency: "EUR",
+				URL:      "https://shop.example/tee",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.page), "https://shop.example/item?id=1")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNoProduct(t *testing.T) {
+	pages := map[string]string{
+		"plain page": `<html><head><title>About us</title></head><body>Hello</body></html>`,
+		"og:image only": `<html><head><title>Blog</title>
+<meta property="og:type" content="article">
+<meta property="og:image" content="/img/header.jpg">
+</head></html>`,
+		"other JSON-LD": `<html><head>
+<script type="application/ld+json">{"@type": "Organization", "name": "Shop", "logo": "/logo.png"}</script>
+</head></html>`,
+	}
+	for name, page := range pages {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(page), "https://shop.example/"); !errors.Is(err, ErrNoProduct) {
+				t.Errorf("Parse() error = %v, want ErrNoProduct", err)
+			}
+		})
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := map[string]float64{
+		"59.95":      59.95,
+		"59,95":      59.95,
+		"1,299":      1299,
+		"1,299.00":   1299,
+		"1.299,00":   1299,
+		"12,345,678": 12345678,
+		"0,5":        0.5,
+		" 100 ":      100,
+		"":           0,
+		"free":       0,
+		"-5":         0,
+	}
+	for in, want := range tests {
+		if got := parsePrice(in); got != want {
+			t.Errorf("parsePrice(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
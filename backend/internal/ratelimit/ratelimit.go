@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limit is a token bucket: up to Burst requests at once, refilled at Rate tokens per second.
+// The zero Limit means "no limit".
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func PerMinute(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+func PerHour(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 3600, Burst: burst}
+}
+
+func (l Limit) Disabled() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Result of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // When the next token arrives, set if not Allowed
+}
+
+// Store keeps the buckets. Keys are opaque to the store.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Default is the store the middleware uses, set by Init
+var Default Store = NewMemoryStore()
+
+// Init picks the store from RATE_LIMIT_STORE ("memory" or "redis", default "memory").
+// The memory store is per process; use redis when running more than one instance.
+func Init() error {
+	switch os.Getenv("RATE_LIMIT_STORE") {
+	case "", "memory":
+		Default = NewMemoryStore()
+	case "redis":
+		url := os.Getenv("REDIS_URL")
+		if url == "" {
+			return fmt.Errorf("RATE_LIMIT_STORE=redis needs REDIS_URL")
+		}
+		opts, err := redis.ParseURL(url)
+		if err != nil {
+			return fmt.Errorf("REDIS_URL: %w", err)
+		}
+		client := redis.NewClient(opts)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(ctx).Err(); err != nil {
+			return fmt.Errorf("redis: %w", err)
+		}
+		Default = NewRedisStore(client)
+		log.Println("Rate limiting: using Redis store")
+	default:
+		return fmt.Errorf("unknown RATE_LIMIT_STORE %q", os.Getenv("RATE_LIMIT_STORE"))
+	}
+	return nil
+}
+
+// Take removes one token from key's bucket in the Default store
+func Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Default.Take(ctx, key, limit)
+}
+
+// retryAfter is how long until a bucket holding tokens has a whole one again
+func retryAfter(tokens float64, limit Limit) time.Duration {
+	return time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+}
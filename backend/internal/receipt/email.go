@@ -0,0 +1,261 @@
+package receipt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// maxParts stops pathological multipart nesting
+const maxParts = 50
+
+// Email is the readable content of a pasted order confirmation
+type Email struct {
+	Subject string
+	Date    time.Time // Zero unless the paste was a full message with headers
+	Text    string
+}
+
+// Read turns a pasted order confirmation into plain text. It accepts a raw
+// message (.eml) with its MIME parts, an HTML body or plain text. For
+// messages the HTML part is preferred, as shops lay their orders out in
+// tables that the plain text part often flattens or leaves out.
+func Read(data []byte) (*Email, error) {
+	data = bytes.TrimLeft(data, "\ufeff \t\r\n")
+
+	if looksLikeMessage(data) {
+		if msg, err := mail.ReadMessage(bytes.NewReader(data)); err == nil {
+			email := &Email{Subject: decodeHeader(msg.Header.Get("Subject"))}
+			if date, err := msg.Header.Date(); err == nil {
+				email.Date = date
+			}
+			var plain, rich []string
+			parts := 0
+			if err := walk(mailHeader(msg.Header), msg.Body, &plain, &rich, &parts); err != nil {
+				return nil, err
+			}
+			if len(rich) > 0 {
+				email.Text = strings.Join(rich, "\n\n")
+			} else {
+				email.Text = strings.Join(plain, "\n\n")
+			}
+			return email, nil
+		}
+	}
+
+	if looksLikeHTML(data) {
+		return &Email{Text: HTMLText(data)}, nil
+	}
+	return &Email{Text: strings.TrimSpace(string(data))}, nil
+}
+
+// header is what walk needs from mail and MIME part headers
+type header interface {
+	Get(key string) string
+}
+
+type mailHeader mail.Header
+
+func (h mailHeader) Get(key string) string { return mail.Header(h).Get(key) }
+
+// walk collects the text and HTML bodies of a MIME entity, skipping attachments
+func walk(h header, body io.Reader, plain, rich *[]string, parts *int) error {
+	if *parts++; *parts > maxParts {
+		return nil
+	}
+	if disposition, _, _ := mime.ParseMediaType(h.Get("Content-Disposition")); disposition == "attachment" {
+		return nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				// Keep whatever was read before the broken part
+				return nil
+			}
+			if err := walk(part.Header, part, plain, rich, parts); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch mediaType {
+	case "text/plain", "text/html":
+	case "message/rfc822":
+		msg, err := mail.ReadMessage(body)
+		if err != nil {
+			return nil
+		}
+		return walk(mailHeader(msg.Header), msg.Body, plain, rich, parts)
+	default:
+		return nil
+	}
+
+	content, err := io.ReadAll(decode(h.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+	if mediaType == "text/html" {
+		*rich = append(*rich, HTMLText(content))
+	} else {
+		*plain = append(*plain, strings.TrimSpace(string(content)))
+	}
+	return nil
+}
+
+func decode(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &newlineStripper{r: body})
+	}
+	return body
+}
+
+// newlineStripper drops the line breaks base64 bodies are wrapped with
+type newlineStripper struct{ r io.Reader }
+
+func (s *newlineStripper) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	kept := 0
+	for _, b := range p[:n] {
+		if b != '\r' && b != '\n' && b != ' ' && b != '\t' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// headerLine matches the "Key: value" lines a message starts with
+var headerLine = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*:`)
+
+// looksLikeMessage is true when the paste starts with a block of mail headers,
+// ended by a blank line, that includes at least one header every message has.
+// Without the blank line it's text that merely starts like one, e.g.
+// "Subject: your order".
+func looksLikeMessage(data []byte) bool {
+	end := bytes.Index(data, []byte("\n\n"))
+	if crlf := bytes.Index(data, []byte("\r\n\r\n")); crlf >= 0 && (end < 0 || crlf < end) {
+		end = crlf
+	}
+	if end < 0 {
+		return false
+	}
+	head := data[:end]
+	known := false
+	for _, line := range strings.Split(string(head), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || line[0] == ' ' || line[0] == '\t' {
+			continue // Folded continuation of the previous header
+		}
+		if !headerLine.MatchString(line) {
+			return false
+		}
+		switch strings.ToLower(line[:strings.Index(line, ":")]) {
+		case "from", "to", "subject", "date", "content-type", "mime-version", "message-id":
+			known = true
+		}
+	}
+	return known
+}
+
+func looksLikeHTML(data []byte) bool {
+	start := strings.ToLower(string(data[:min(len(data), 512)]))
+	return strings.HasPrefix(start, "<!doctype html") || strings.Contains(start, "<html") ||
+		strings.Contains(start, "<body") || strings.Contains(start, "<table") || strings.Contains(start, "<div")
+}
+
+// blockElements start a new line in the text of an HTML page
+var blockElements = map[string]bool{
+	"p": true, "div": true, "br": true, "tr": true, "li": true, "table": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"section": true, "article": true, "header": true, "footer": true, "hr": true,
+}
+
+// HTMLText is the visible text of an HTML document, one block per line and
+// table cells separated by " | " so order lines stay together
+func HTMLText(page []byte) string {
+	root, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return strings.TrimSpace(string(page))
+	}
+
+	var b strings.Builder
+	var render func(n *html.Node)
+	render = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			if text := strings.Join(strings.Fields(n.Data), " "); text != "" {
+				b.WriteString(text)
+				b.WriteByte(' ')
+			}
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "script", "style", "head", "title", "noscript":
+				return
+			case "td", "th":
+				b.WriteString("| ")
+			case "img":
+				// Product images often carry the only description of the item
+				if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+					b.WriteString(alt + " ")
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			render(child)
+		}
+		if n.Type == html.ElementNode && blockElements[n.Data] {
+			b.WriteByte('\n')
+		}
+	}
+	render(root)
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		line = strings.TrimSpace(strings.Trim(strings.TrimSpace(line), "|"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
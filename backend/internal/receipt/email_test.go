@@ -0,0 +1,176 @@
+package receipt
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// crlf turns a readable fixture into a message with CRLF line endings, as
+// .eml files have
+func crlf(s string) []byte {
+	return []byte(strings.ReplaceAll(s, "\n", "\r\n"))
+}
+
+func TestReadMultipartPrefersHTML(t *testing.T) {
+	// The HTML part is base64, wrapped at 76 columns like mail clients do
+	msg := crlf(`From: Shop <orders@shop.example>
+To: me@example.com
+Subject: =?UTF-8?Q?Your_order_=E2=80=93_#1234?=
+Date: Tue, 02 Sep 2025 10:15:00 +0200
+MIME-Version: 1.0
+Content-Type: multipart/alternative; boundary="alt"
+
+--alt
+Content-Type: text/plain; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+Wool Coat 1 x 199,00 =E2=82=AC
+--alt
+Content-Type: text/html; charset=utf-8
+Content-Transfer-Encoding: base64
+
+PGh0bWw+PGJvZHk+PHRhYmxlPjx0cj48dGQ+V29vbCBDb2F0PC90ZD48dGQ+MTk5LDAwIOKCrDwv
+dGQ+PC90cj48L3RhYmxlPjwvYm9keT48L2h0bWw+
+--alt--
+`)
+
+	email, err := Read(msg)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if email.Subject != "Your order – #1234" {
+		t.Errorf("Subject = %q", email.Subject)
+	}
+	if want := time.Date(2025, 9, 2, 8, 15, 0, 0, time.UTC); !email.Date.Equal(want) {
+		t.Errorf("Date = %v, want %v", email.Date, want)
+	}
+	if email.Text != "Wool Coat | 199,00 €" {
+		t.Errorf("Text = %q", email.Text)
+	}
+}
+
+func TestReadQuotedPrintableSkipsAttachments(t *testing.T) {
+	msg := crlf(`From: orders@shop.example
+Subject: Receipt
+Content-Type: multipart/mixed; boundary=mixed
+
+--mixed
+Content-Type: text/plain; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+Linen Shirt, size M =E2=80=93 49,50 =E2=82=AC. A very long line that the sho=
+p's mailer wrapped with a soft line break.
+--mixed
+Content-Type: text/plain; name="terms.txt"
+Content-Disposition: attachment; filename="terms.txt"
+
+Terms and conditions
+--mixed--
+`)
+
+	email, err := Read(msg)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := "Linen Shirt, size M – 49,50 €. A very long line that the shop's mailer wrapped with a soft line break."
+	if email.Text != want {
+		t.Errorf("Text = %q, want %q", email.Text, want)
+	}
+}
+
+func TestReadForwardedMessage(t *testing.T) {
+	msg := crlf(`From: me@example.com
+Subject: Fwd: Your order
+Content-Type: multipart/mixed; boundary=outer
+
+--outer
+Content-Type: text/plain
+
+See below
+--outer
+Content-Type: message/rfc822
+
+From: orders@shop.example
+Subject: Your order
+Content-Type: text/plain
+Content-Transfer-Encoding: base64
+
+U2xpbSBKZWFucyAzMi8zMiAtIDg5LjAwIFVTRA==
+--outer--
+`)
+
+	email, err := Read(msg)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if email.Text != "See below\n\nSlim Jeans 32/32 - 89.00 USD" {
+		t.Errorf("Text = %q", email.Text)
+	}
+}
+
+func TestReadHTML(t *testing.T) {
+	page := []byte("\ufeff" + `<!DOCTYPE html><html><head><title>Order</title><style>td{color:red}</style></head>
+<body><script>track()</script>
+<h1>Thanks for your order</h1>
+<table>
+  <tr><th>Item</th><th>Price</th></tr>
+  <tr><td><img src="coat.jpg" alt="Wool Coat, camel"></td><td>199.00   EUR</td></tr>
+</table>
+<p>Total:<br>199.00 EUR</p>
+</body></html>`)
+
+	email, err := Read(page)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := "Thanks for your order\nItem | Price\nWool Coat, camel | 199.00 EUR\nTotal:\n199.00 EUR"
+	if email.Text != want {
+		t.Errorf("Text = %q, want %q", email.Text, want)
+	}
+	if email.Subject != "" || !email.Date.IsZero() {
+		t.Errorf("HTML paste has Subject %q and Date %v", email.Subject, email.Date)
+	}
+}
+
+func TestReadPlainText(t *testing.T) {
+	tests := map[string]string{
+		"plain":          "\n\n  Order #1234\nWool Coat  199.00 EUR\n  ",
+		"header-like":    "Order: #1234\nDate: 2 September 2025\nItem: Wool Coat 199.00 EUR",
+		"header, no end": "Subject: Your order\nWool Coat 199.00 EUR",
+	}
+	for name, paste := range tests {
+		t.Run(name, func(t *testing.T) {
+			email, err := Read([]byte(paste))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if want := strings.TrimSpace(paste); email.Text != want {
+				t.Errorf("Text = %q, want %q", email.Text, want)
+			}
+		})
+	}
+}
+
+func TestLooksLikeMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{"message", "From: a@example.com\nSubject: Hi\n\nBody", true},
+		{"CRLF message", "From: a@example.com\r\nSubject: Hi\r\n\r\nBody", true},
+		{"folded header", "Subject: A long\n  subject\nX-Mailer: Shop\n\nBody", true},
+		{"headers only", "From: a@example.com\n\n", true},
+		{"no blank line", "From: a@example.com\nSubject: Hi", false},
+		{"no blank line, body", "Date: 2 September 2025\nTotal: 199.00 EUR\nThanks!", false},
+		{"no known header", "Order: 1234\nTotal: 199.00\n\nThanks", false},
+		{"not a header", "Hello there\nFrom: a@example.com\n\nBody", false},
+		{"html", "<html><body>From: a@example.com</body></html>\n\n", false},
+	}
+	for _, tt := range tests {
+		if got := looksLikeMessage([]byte(tt.data)); got != tt.want {
+			t.Errorf("%s: looksLikeMessage() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,8 +1,14 @@
 package router
 
 import (
+	"log"
+	"os"
+	"strings"
+
+	"github.com/exply/armoire/internal/ai"
 	"github.com/exply/armoire/internal/handlers"
 	"github.com/exply/armoire/internal/middleware"
+	"github.com/exply/armoire/internal/ratelimit"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
@@ -13,35 +19,176 @@ import (
 func SetupRouter() *gin.Engine {
 	router := gin.Default()
 
+	// c.ClientIP() keys the per-IP rate limits, so X-Forwarded-For is only
+	// believed from the proxies listed in TRUSTED_PROXIES (comma-separated
+	// IPs or CIDRs); without it the connection's address is used
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("TRUSTED_PROXIES: %v", err)
+	}
+
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Last-Event-ID"},
+		ExposeHeaders:    []string{"Content-Length", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining"},
 		AllowCredentials: true,
 	}))
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Rate limits per route group. Credential endpoints are limited per IP
+	// (there's no user yet); anything that calls Gemini is limited per user too.
+	// Password checks, refreshes and emailed links each get their own budget,
+	// so a client refreshing tokens never eats into its login attempts
+	passwordLimit := middleware.RateLimit("password", ratelimit.PerMinute(10, 10), ratelimit.Limit{})
+	refreshLimit := middleware.RateLimit("refresh", ratelimit.PerMinute(60, 30), ratelimit.Limit{})
+	emailLimit := middleware.RateLimit("email", ratelimit.PerMinute(5, 5), ratelimit.PerHour(10, 5))
+	oidcLimit := middleware.RateLimit("oidc", ratelimit.PerMinute(30, 20), ratelimit.Limit{})
+	uploadLimit := middleware.RateLimit("upload", ratelimit.PerMinute(30, 20), ratelimit.PerHour(60, 20))
+	aiLimit := middleware.RateLimit("ai", ratelimit.PerMinute(60, 30), ratelimit.PerMinute(20, 10))
+	sharedLimit := middleware.RateLimit("shared", ratelimit.PerMinute(60, 30), ratelimit.Limit{})
+	apiLimit := middleware.RateLimit("api", ratelimit.PerMinute(600, 100), ratelimit.PerMinute(300, 100))
+
 	// auth
 
-	router.POST("/auth/register", handlers.RegisterHandler)
-	router.POST("/auth/login", handlers.LoginHandler)
+	authGroup := router.Group("/auth")
+	{
+		authGroup.POST("/register", passwordLimit, handlers.RegisterHandler)
+		authGroup.POST("/login", passwordLimit, handlers.LoginHandler)
+		authGroup.POST("/refresh", refreshLimit, handlers.RefreshHandler)
+		authGroup.POST("/verify-email/confirm", emailLimit, handlers.ConfirmEmailVerificationHandler)
+		authGroup.POST("/password-reset/request", emailLimit, handlers.RequestPasswordResetHandler)
+		authGroup.POST("/password-reset/confirm", emailLimit, handlers.ConfirmPasswordResetHandler)
+	}
+	oidcGroup := router.Group("/auth/oidc", oidcLimit)
+	{
+		oidcGroup.GET("/providers", handlers.ListOIDCProvidersHandler)
+		oidcGroup.GET("/:provider/login", handlers.OIDCLoginHandler)
+		oidcGroup.GET("/:provider/callback", handlers.OIDCCallbackHandler)
+	}
 
 	// Protected Routes
 	protected := router.Group("/")
-	protected.Use(middleware.AuthMiddleware())
+	protected.Use(middleware.AuthMiddleware(), apiLimit)
 	{
-		protected.POST("/clothing/upload", handlers.UploadClothingHandler)
-		protected.POST("/clothing/search", handlers.SearchClothingHandler)
+		protected.POST("/auth/logout", handlers.LogoutHandler)
+		protected.POST("/auth/logout-all", handlers.LogoutAllHandler)
+		protected.POST("/auth/verify-email/request", emailLimit, handlers.RequestEmailVerificationHandler)
+		protected.POST("/clothing/upload", uploadLimit, middleware.AIQuota(ai.OpTagging, ai.OpEmbedding, ai.OpBackgroundRemoval), handlers.UploadClothingHandler)
+		protected.POST("/clothing/import-url", uploadLimit, middleware.AIQuota(ai.OpTagging, ai.OpEmbedding, ai.OpBackgroundRemoval), handlers.ImportClothingFromURLHandler)
+		protected.POST("/clothing/receipts", uploadLimit, middleware.AIQuota(ai.OpReceipt), handlers.ImportReceiptHandler)
+		protected.POST("/clothing/search", aiLimit, middleware.AIQuota(ai.OpEmbedding), handlers.SearchClothingHandler)
 		protected.GET("/clothing/stats", handlers.GetUserStatsHandler)
+		protected.GET("/clothing/analysis", handlers.GetClosetAnalysisHandler)
+		protected.PATCH("/clothing/availability", handlers.BulkUpdateAvailabilityHandler)
 		protected.GET("/clothing/:id", handlers.GetClothingByIDHandler)
 		protected.PATCH("/clothing/:id", handlers.UpdateClothingHandler)
 		protected.DELETE("/clothing/:id", handlers.DeleteClothingHandler)
+		protected.POST("/clothing/:id/photo", uploadLimit, middleware.AIQuota(ai.OpTagging, ai.OpEmbedding, ai.OpBackgroundRemoval), handlers.AddDraftPhotoHandler)
+		protected.POST("/clothing/:id/care-label", uploadLimit, middleware.AIQuota(ai.OpCareLabel), handlers.UploadCareLabelHandler)
+		protected.POST("/laundry/loads", handlers.GetLaundryLoadsHandler)
 		protected.GET("/user/userinfo", handlers.GetCurrentUserHandler)
-		protected.GET("/dashboard/stylist", handlers.GetStylistMessageHandler)
+		protected.PATCH("/user/profile", handlers.UpdateProfileHandler)
+		protected.POST("/user/avatar", handlers.UploadAvatarHandler)
+		protected.PUT("/user/password", passwordLimit, handlers.ChangePasswordHandler)
+		protected.DELETE("/user", passwordLimit, handlers.DeleteAccountHandler)
+		protected.GET("/user/export", handlers.ExportAccountHandler)
+		protected.POST("/user/import", uploadLimit, middleware.AIQuota(ai.OpEmbedding), handlers.ImportAccountHandler)
+		protected.GET("/user/usage", handlers.GetUsageHandler)
+		protected.POST("/outfits", handlers.CreateOutfitHandler)
+		protected.GET("/outfits", handlers.ListOutfitsHandler)
+		protected.GET("/outfits/:id", handlers.GetOutfitHandler)
+		protected.PATCH("/outfits/:id", handlers.UpdateOutfitHandler)
+		protected.DELETE("/outfits/:id", handlers.DeleteOutfitHandler)
+		protected.POST("/collections", handlers.CreateCollectionHandler)
+		protected.GET("/collections", handlers.ListCollectionsHandler)
+		protected.GET("/collections/:id", handlers.GetCollectionHandler)
+		protected.PATCH("/collections/:id", handlers.UpdateCollectionHandler)
+		protected.DELETE("/collections/:id", handlers.DeleteCollectionHandler)
+		protected.POST("/share-links", handlers.CreateShareLinkHandler)
+		protected.GET("/share-links", handlers.ListShareLinksHandler)
+		protected.DELETE("/share-links/:id", handlers.RevokeShareLinkHandler)
+		protected.GET("/users/:id", handlers.GetProfileHandler)
+		protected.POST("/users/:id/follow", handlers.FollowUserHandler)
+		protected.DELETE("/users/:id/follow", handlers.UnfollowUserHandler)
+		protected.GET("/users/:id/followers", handlers.ListFollowersHandler)
+		protected.GET("/users/:id/following", handlers.ListFollowingHandler)
+		protected.GET("/user/follow-requests", handlers.ListFollowRequestsHandler)
+		protected.POST("/user/follow-requests/:id/approve", handlers.ApproveFollowRequestHandler)
+		protected.DELETE("/user/follow-requests/:id", handlers.DeclineFollowRequestHandler)
+		protected.DELETE("/user/followers/:id", handlers.RemoveFollowerHandler)
+		protected.GET("/feed", handlers.GetFeedHandler)
+		protected.POST("/borrow-requests", handlers.CreateBorrowRequestHandler)
+		protected.GET("/borrow-requests", handlers.ListBorrowRequestsHandler)
+		protected.GET("/borrow-requests/:id", handlers.GetBorrowRequestHandler)
+		protected.POST("/borrow-requests/:id/approve", handlers.ApproveBorrowRequestHandler)
+		protected.POST("/borrow-requests/:id/decline", handlers.DeclineBorrowRequestHandler)
+		protected.POST("/borrow-requests/:id/cancel", handlers.CancelBorrowRequestHandler)
+		protected.POST("/borrow-requests/:id/return", handlers.ReturnBorrowedItemHandler)
+		protected.GET("/clothing/:id/comments", handlers.ListItemCommentsHandler)
+		protected.POST("/clothing/:id/comments", handlers.CreateItemCommentHandler)
+		protected.DELETE("/clothing/:id/comments/:commentId", handlers.DeleteItemCommentHandler)
+		protected.PUT("/clothing/:id/comment-settings", handlers.SetItemCommentSettingsHandler)
+		protected.GET("/clothing/:id/reactions", handlers.GetItemReactionsHandler)
+		protected.POST("/clothing/:id/reactions", handlers.AddItemReactionHandler)
+		protected.DELETE("/clothing/:id/reactions", handlers.RemoveItemReactionHandler)
+		protected.GET("/outfits/:id/comments", handlers.ListOutfitCommentsHandler)
+		protected.POST("/outfits/:id/comments", handlers.CreateOutfitCommentHandler)
+		protected.DELETE("/outfits/:id/comments/:commentId", handlers.DeleteOutfitCommentHandler)
+		protected.PUT("/outfits/:id/comment-settings", handlers.SetOutfitCommentSettingsHandler)
+		protected.GET("/outfits/:id/reactions", handlers.GetOutfitReactionsHandler)
+		protected.POST("/outfits/:id/reactions", handlers.AddOutfitReactionHandler)
+		protected.DELETE("/outfits/:id/reactions", handlers.RemoveOutfitReactionHandler)
+		protected.GET("/notifications", handlers.ListNotificationsHandler)
+		protected.POST("/notifications/read-all", handlers.MarkAllNotificationsReadHandler)
+		protected.POST("/notifications/stream-ticket", handlers.CreateStreamTicketHandler)
+		protected.POST("/notifications/:id/read", handlers.MarkNotificationReadHandler)
+		protected.DELETE("/notifications/:id", handlers.DeleteNotificationHandler)
+		protected.POST("/planner", handlers.CreatePlanEntryHandler)
+		protected.GET("/planner", handlers.GetPlannerHandler)
+		protected.PATCH("/planner/:id", handlers.UpdatePlanEntryHandler)
+		protected.DELETE("/planner/:id", handlers.DeletePlanEntryHandler)
+		protected.POST("/planner/calendar-feed", handlers.CreateCalendarFeedHandler)
+		protected.DELETE("/planner/calendar-feed", handlers.DeleteCalendarFeedHandler)
+		protected.POST("/packing-lists", handlers.CreatePackingListHandler)
+		protected.GET("/packing-lists", handlers.ListPackingListsHandler)
+		protected.GET("/packing-lists/:id", handlers.GetPackingListHandler)
+		protected.PATCH("/packing-lists/:id/items/:itemId", handlers.UpdatePackingItemHandler)
+		protected.DELETE("/packing-lists/:id", handlers.DeletePackingListHandler)
+		protected.POST("/wishlist", uploadLimit, middleware.AIQuota(ai.OpTagging, ai.OpEmbedding, ai.OpBackgroundRemoval), handlers.CreateWishlistItemHandler)
+		protected.GET("/wishlist", handlers.ListWishlistHandler)
+		protected.GET("/wishlist/:id", handlers.GetWishlistItemHandler)
+		protected.PATCH("/wishlist/:id", aiLimit, middleware.AIQuota(ai.OpEmbedding), handlers.UpdateWishlistItemHandler)
+		protected.DELETE("/wishlist/:id", handlers.DeleteWishlistItemHandler)
+		protected.GET("/dashboard/stylist", aiLimit, middleware.AIQuota(ai.OpStylist), handlers.GetStylistMessageHandler)
 	}
+
+	// Live notifications; EventSource can't send headers, so it brings a one-time ?ticket=
+	router.GET("/notifications/stream", middleware.StreamAuthMiddleware(), apiLimit, handlers.NotificationStreamHandler)
+
+	admin := router.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), apiLimit, middleware.AdminMiddleware())
+	{
+		admin.GET("/usage", handlers.GetAdminUsageSummaryHandler)
+		admin.GET("/users", handlers.ListUsersHandler)
+		admin.GET("/users/:id", handlers.GetUserDetailHandler)
+		admin.POST("/users/:id/disable", handlers.DisableUserHandler)
+		admin.POST("/users/:id/enable", handlers.EnableUserHandler)
+		admin.PUT("/users/:id/role", handlers.SetUserRoleHandler)
+		admin.POST("/clothing/:id/reprocess", handlers.ReprocessClothingHandler)
+	}
+
+	// Anonymous share links; limited per IP so tokens can't be brute-forced cheaply
+	router.GET("/shared/:token", sharedLimit, handlers.GetSharedResourceHandler)
+	router.GET("/calendar/:token", sharedLimit, handlers.CalendarFeedHandler)
 	router.GET("/clothing/:id/owner", handlers.GetClothingOwnerNameHandler)
+	router.GET("/taxonomy", handlers.GetTaxonomyHandler)
 
 	router.GET("/ping", pingHandler)
 	return router
@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
@@ -53,9 +55,10 @@ func (s *StorageClient) DeleteFile(gcsURI string) error {
 	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
 	defer cancel()
 
-	// Extract filename from gs://bucket/filename format
-	// gcsURI format: "gs://bucket-name/filename"
-	filename := gcsURI[len("gs://"+s.BucketName+"/"):]
+	filename, err := s.objectName(gcsURI)
+	if err != nil {
+		return err
+	}
 
 	obj := s.Client.Bucket(s.BucketName).Object(filename)
 	if err := obj.Delete(ctx); err != nil {
@@ -64,3 +67,60 @@ func (s *StorageClient) DeleteFile(gcsURI string) error {
 
 	return nil
 }
+
+// ReadFile downloads an object by its gs:// URI
+func (s *StorageClient) ReadFile(gcsURI string) ([]byte, error) {
+	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(ctx, time.Second*50)
+	defer cancel()
+
+	filename, err := s.objectName(gcsURI)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := s.Client.Bucket(s.BucketName).Object(filename).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// Size returns an object's size in bytes
+func (s *StorageClient) Size(gcsURI string) (int64, error) {
+	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	filename, err := s.objectName(gcsURI)
+	if err != nil {
+		return 0, err
+	}
+
+	attrs, err := s.Client.Bucket(s.BucketName).Object(filename).Attrs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return attrs.Size, nil
+}
+
+// objectName extracts the filename from gs://bucket/filename format
+func (s *StorageClient) objectName(gcsURI string) (string, error) {
+	prefix := "gs://" + s.BucketName + "/"
+	if !strings.HasPrefix(gcsURI, prefix) {
+		return "", fmt.Errorf("%q is not in bucket %s", gcsURI, s.BucketName)
+	}
+	return gcsURI[len(prefix):], nil
+}
+
+// URIFromPublicURL turns a public URL ("https://storage.googleapis.com/bucket/filename")
+// back into its gs:// URI, for objects like thumbnails where only the URL is stored
+func (s *StorageClient) URIFromPublicURL(publicURL string) (string, bool) {
+	prefix := "https://storage.googleapis.com/" + s.BucketName + "/"
+	if !strings.HasPrefix(publicURL, prefix) {
+		return "", false
+	}
+	return "gs://" + s.BucketName + "/" + strings.TrimPrefix(publicURL, prefix), true
+}
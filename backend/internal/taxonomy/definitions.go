@@ -1,34 +1,33 @@
 package taxonomy
 
-var Categories = []string{
-	"Tops", "Dresses", "Bottoms", "Outerwear", "Shoes", "Accessories",
-}
+// Attribute keys the garment analysis knows how to fill in
+const (
+	AttrSleeveLength = "sleeve_length"
+	AttrFit          = "fit"
+	AttrMaterial     = "material"
+	AttrPattern      = "pattern"
+	AttrNeckline     = "neckline"
+)
 
-var SubCategories = []string{
-	// Tops
-	"T-Shirt", "Shirt", "Blouse", "Long-Sleeve", "Sweater", "Hoodie", "Tank Top",
-	// Dresses
-	"Dress", "Jumpsuit", "Romper",
-	// Bottoms
-	"Jeans", "Pants", "Shorts", "Skirt", "Leggings",
-	// Outerwear
-	"Jacket", "Coat", "Blazer", "Vest",
-	// Shoes
-	"Sneakers", "Boots", "Sandals", "Heels", "Loafers",
-	// Accessories
-	"Scarf", "Belt", "Jewelry", "Watch", "Gloves", "Hat", "Bag",
-}
+// The flat lists below are derived from the active Taxonomy by Use.
+// They are what prompts, validation and stats iterate over.
+var (
+	Categories    []string
+	SubCategories []string // All sub-categories, in category order
+	Colors        []string
+	Seasons       []string
+	Occasions     []string
 
-var Colors = []string{
-	"Black", "White", "Grey", "Beige", "Brown",
-	"Red", "Blue", "Green", "Yellow", "Orange", "Purple", "Pink",
-	"Gold", "Silver", "Multi-colored",
-}
+	// SubCategoriesByCategory groups every allowed sub-category under its parent category
+	SubCategoriesByCategory map[string][]string
 
-var Seasons = []string{
-	"Spring", "Summer", "Fall", "Winter", "All Season",
-}
+	// SubCategoryAttributes lists the attribute keys that apply to each sub-category
+	SubCategoryAttributes map[string][]string
 
-var Occasions = []string{
-	"Casual", "Business Casual", "Formal", "Party", "Sport/Active", "Lounge",
-}
+	// AttributeValues lists the allowed values of each attribute, keyed by attribute key
+	AttributeValues map[string][]string
+
+	// Synonyms maps a lowercased alias onto the canonical values it may stand for.
+	// An alias can be shared between lists (e.g. "Denim" the material vs. "Jeans").
+	Synonyms map[string][]string
+)
@@ -0,0 +1,99 @@
+package taxonomy
+
+// LocalizedValue is a tag paired with its display name in one language
+type LocalizedValue struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type LocalizedSubCategory struct {
+	LocalizedValue
+	Attributes []string `json:"attributes"`
+}
+
+type LocalizedCategory struct {
+	LocalizedValue
+	SubCategories []LocalizedSubCategory `json:"subCategories"`
+}
+
+type LocalizedAttribute struct {
+	Key         string           `json:"key"`
+	DisplayName string           `json:"displayName"`
+	Values      []LocalizedValue `json:"values"`
+}
+
+// LocalizedTaxonomy is the taxonomy as served to clients in a single language
+type LocalizedTaxonomy struct {
+	Version    string               `json:"version"`
+	Language   string               `json:"language"`
+	Languages  []string             `json:"languages"`
+	Categories []LocalizedCategory  `json:"categories"`
+	Attributes []LocalizedAttribute `json:"attributes"`
+	Colors     []LocalizedValue     `json:"colors"`
+	Seasons    []LocalizedValue     `json:"seasons"`
+	Occasions  []LocalizedValue     `json:"occasions"`
+}
+
+// Localize resolves every display name to lang. Unsupported languages fall back to English.
+func (t *Taxonomy) Localize(lang string) LocalizedTaxonomy {
+	if !t.Supports(lang) {
+		lang = DefaultLanguage
+	}
+
+	result := LocalizedTaxonomy{
+		Version:    t.Version,
+		Language:   lang,
+		Languages:  t.Languages,
+		Categories: []LocalizedCategory{},
+		Attributes: []LocalizedAttribute{},
+		Colors:     localizeValues(t.Colors, lang),
+		Seasons:    localizeValues(t.Seasons, lang),
+		Occasions:  localizeValues(t.Occasions, lang),
+	}
+
+	for _, category := range t.Categories {
+		localized := LocalizedCategory{
+			LocalizedValue: localizeValue(category.Value, lang),
+			SubCategories:  []LocalizedSubCategory{},
+		}
+		for _, sub := range category.SubCategories {
+			localized.SubCategories = append(localized.SubCategories, LocalizedSubCategory{
+				LocalizedValue: localizeValue(sub.Value, lang),
+				Attributes:     sub.Attributes,
+			})
+		}
+		result.Categories = append(result.Categories, localized)
+	}
+
+	for _, attr := range t.Attributes {
+		result.Attributes = append(result.Attributes, LocalizedAttribute{
+			Key:         attr.Key,
+			DisplayName: displayName(attr.DisplayNames, lang, attr.Key),
+			Values:      localizeValues(attr.Values, lang),
+		})
+	}
+
+	return result
+}
+
+// Supports reports whether the taxonomy declares display names for lang
+func (t *Taxonomy) Supports(lang string) bool {
+	for _, l := range t.Languages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func localizeValue(v Value, lang string) LocalizedValue {
+	return LocalizedValue{Name: v.Name, DisplayName: v.DisplayName(lang)}
+}
+
+func localizeValues(values []Value, lang string) []LocalizedValue {
+	result := make([]LocalizedValue, len(values))
+	for i, v := range values {
+		result[i] = localizeValue(v, lang)
+	}
+	return result
+}
@@ -0,0 +1,228 @@
+package taxonomy
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultLanguage is used whenever a display name is missing for the requested language
+const DefaultLanguage = "en"
+
+//go:embed taxonomy.json
+var defaultTaxonomy []byte
+
+// Value is a single allowed tag, e.g. the color "Grey"
+type Value struct {
+	Name         string            `json:"name" yaml:"name"`
+	DisplayNames map[string]string `json:"displayNames" yaml:"displayNames"`
+	Aliases      []string          `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+}
+
+// SubCategory lists which attributes apply to garments of that kind
+type SubCategory struct {
+	Value      `yaml:",inline"`
+	Attributes []string `json:"attributes" yaml:"attributes"`
+}
+
+type Category struct {
+	Value         `yaml:",inline"`
+	SubCategories []SubCategory `json:"subCategories" yaml:"subCategories"`
+}
+
+// Attribute is a garment property such as sleeve length, fit or material
+type Attribute struct {
+	Key          string            `json:"key" yaml:"key"`
+	DisplayNames map[string]string `json:"displayNames" yaml:"displayNames"`
+	Values       []Value           `json:"values" yaml:"values"`
+}
+
+// Taxonomy is the versioned category → sub-category → attribute hierarchy
+type Taxonomy struct {
+	Version    string      `json:"version" yaml:"version"`
+	Languages  []string    `json:"languages" yaml:"languages"`
+	Categories []Category  `json:"categories" yaml:"categories"`
+	Attributes []Attribute `json:"attributes" yaml:"attributes"`
+	Colors     []Value     `json:"colors" yaml:"colors"`
+	Seasons    []Value     `json:"seasons" yaml:"seasons"`
+	Occasions  []Value     `json:"occasions" yaml:"occasions"`
+}
+
+var current *Taxonomy
+
+func init() {
+	t, err := Parse(defaultTaxonomy, ".json")
+	if err != nil {
+		panic("taxonomy: invalid embedded taxonomy: " + err.Error())
+	}
+	Use(t)
+}
+
+// Current returns the active taxonomy
+func Current() *Taxonomy {
+	return current
+}
+
+// LoadFile reads a taxonomy from a .json, .yaml or .yml file
+func LoadFile(path string) (*Taxonomy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, filepath.Ext(path))
+}
+
+// Parse decodes and validates a taxonomy. ext selects the format (".json", ".yaml" or ".yml").
+func Parse(data []byte, ext string) (*Taxonomy, error) {
+	var t Taxonomy
+	var err error
+
+	switch strings.ToLower(ext) {
+	case ".json":
+		err = json.Unmarshal(data, &t)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &t)
+	default:
+		return nil, fmt.Errorf("unsupported taxonomy format %q", ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Validate checks that names are unique and every referenced attribute is defined
+func (t *Taxonomy) Validate() error {
+	if t.Version == "" {
+		return fmt.Errorf("taxonomy version is required")
+	}
+	if len(t.Categories) == 0 {
+		return fmt.Errorf("taxonomy has no categories")
+	}
+
+	attributes := make(map[string]bool)
+	for _, attr := range t.Attributes {
+		if attr.Key == "" || attributes[attr.Key] {
+			return fmt.Errorf("attribute key %q is empty or duplicated", attr.Key)
+		}
+		attributes[attr.Key] = true
+		if err := checkUnique("attribute "+attr.Key, attr.Values); err != nil {
+			return err
+		}
+	}
+
+	categories := make([]Value, 0, len(t.Categories))
+	var subCategories []Value
+	for _, category := range t.Categories {
+		categories = append(categories, category.Value)
+		for _, sub := range category.SubCategories {
+			subCategories = append(subCategories, sub.Value)
+			for _, key := range sub.Attributes {
+				if !attributes[key] {
+					return fmt.Errorf("sub-category %q uses undefined attribute %q", sub.Name, key)
+				}
+			}
+		}
+	}
+
+	for name, values := range map[string][]Value{
+		"categories":     categories,
+		"sub-categories": subCategories,
+		"colors":         t.Colors,
+		"seasons":        t.Seasons,
+		"occasions":      t.Occasions,
+	} {
+		if err := checkUnique(name, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkUnique(list string, values []Value) error {
+	seen := make(map[string]bool)
+	for _, v := range values {
+		key := strings.ToLower(v.Name)
+		if key == "" || seen[key] {
+			return fmt.Errorf("%s: value %q is empty or duplicated", list, v.Name)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// Use makes t the active taxonomy and rebuilds the flat lookup lists
+func Use(t *Taxonomy) {
+	current = t
+
+	Categories = nil
+	SubCategories = nil
+	SubCategoriesByCategory = make(map[string][]string)
+	SubCategoryAttributes = make(map[string][]string)
+	AttributeValues = make(map[string][]string)
+	Synonyms = make(map[string][]string)
+
+	addSynonyms := func(values []Value) {
+		for _, v := range values {
+			for _, alias := range v.Aliases {
+				key := strings.ToLower(alias)
+				Synonyms[key] = append(Synonyms[key], v.Name)
+			}
+		}
+	}
+
+	for _, category := range t.Categories {
+		Categories = append(Categories, category.Name)
+		addSynonyms([]Value{category.Value})
+		for _, sub := range category.SubCategories {
+			SubCategoriesByCategory[category.Name] = append(SubCategoriesByCategory[category.Name], sub.Name)
+			SubCategories = append(SubCategories, sub.Name)
+			SubCategoryAttributes[sub.Name] = sub.Attributes
+			addSynonyms([]Value{sub.Value})
+		}
+	}
+
+	for _, attr := range t.Attributes {
+		AttributeValues[attr.Key] = names(attr.Values)
+		addSynonyms(attr.Values)
+	}
+
+	Colors = names(t.Colors)
+	Seasons = names(t.Seasons)
+	Occasions = names(t.Occasions)
+	addSynonyms(t.Colors)
+	addSynonyms(t.Seasons)
+	addSynonyms(t.Occasions)
+}
+
+func names(values []Value) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = v.Name
+	}
+	return result
+}
+
+// DisplayName picks the name for lang, falling back to English and then to the canonical name
+func (v Value) DisplayName(lang string) string {
+	return displayName(v.DisplayNames, lang, v.Name)
+}
+
+func displayName(names map[string]string, lang, fallback string) string {
+	if name, ok := names[lang]; ok && name != "" {
+		return name
+	}
+	if name, ok := names[DefaultLanguage]; ok && name != "" {
+		return name
+	}
+	return fallback
+}
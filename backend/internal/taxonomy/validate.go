@@ -0,0 +1,106 @@
+package taxonomy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes a single field that failed taxonomy validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors collects every field error found while validating a payload
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Field + ": " + e.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Normalize maps a raw value onto its canonical spelling in allowed.
+// Matching ignores case and surrounding whitespace and understands Synonyms.
+func Normalize(value string, allowed []string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(value))
+	if key == "" {
+		return "", false
+	}
+	for _, candidate := range allowed {
+		if strings.ToLower(candidate) == key {
+			return candidate, true
+		}
+	}
+	for _, canonical := range Synonyms[key] {
+		for _, candidate := range allowed {
+			if candidate == canonical {
+				return candidate, true
+			}
+		}
+	}
+	return "", false
+}
+
+// NormalizeList normalizes every value of a list field, dropping duplicates.
+// Unknown values are left out of the result and reported as field errors.
+func NormalizeList(field string, values []string, allowed []string) ([]string, ValidationErrors) {
+	var errs ValidationErrors
+	result := []string{}
+	seen := make(map[string]bool)
+
+	for _, value := range values {
+		canonical, ok := Normalize(value, allowed)
+		if !ok {
+			errs = append(errs, FieldError{Field: field, Value: value, Message: "unknown value"})
+			continue
+		}
+		if !seen[canonical] {
+			seen[canonical] = true
+			result = append(result, canonical)
+		}
+	}
+	return result, errs
+}
+
+// CategoryOf returns the parent category of a canonical sub-category
+func CategoryOf(subCategory string) (string, bool) {
+	for category, subs := range SubCategoriesByCategory {
+		for _, sub := range subs {
+			if sub == subCategory {
+				return category, true
+			}
+		}
+	}
+	return "", false
+}
+
+// AttributeApplies reports whether an attribute is relevant for a sub-category.
+// Unknown sub-categories accept every attribute so nothing is dropped before review.
+func AttributeApplies(subCategory, key string) bool {
+	keys, ok := SubCategoryAttributes[subCategory]
+	if !ok {
+		return true
+	}
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckSubCategory reports an error when subCategory does not belong to category
+func CheckSubCategory(category, subCategory string) *FieldError {
+	if parent, ok := CategoryOf(subCategory); ok && parent != category {
+		return &FieldError{
+			Field:   "sub_category",
+			Value:   subCategory,
+			Message: fmt.Sprintf("belongs to %s, not %s", parent, category),
+		}
+	}
+	return nil
+}
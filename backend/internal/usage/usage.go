@@ -0,0 +1,179 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/exply/armoire/internal/ai"
+	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection holds one models.UsageCounter per user, month and operation
+const Collection = "ai_usage"
+
+// Quota keys besides the ai.Op* operations, which limit calls
+const (
+	QuotaTokens  = "tokens"  // Gemini tokens across every operation
+	QuotaCredits = "credits" // Clipdrop credits
+)
+
+// Quotas are the monthly limits per user; 0 means unlimited. Override with
+// AI_QUOTAS, e.g. "tagging=100,embedding=1000,tokens=500000".
+var Quotas = map[string]int64{
+	ai.OpTagging:           300,
+	ai.OpCareLabel:         300,
+	ai.OpEmbedding:         3000,
+	ai.OpStylist:           300,
+	ai.OpReceipt:           100,
+	ai.OpBackgroundRemoval: 300,
+	QuotaTokens:            2_000_000,
+	QuotaCredits:           300,
+}
+
+// Init applies AI_QUOTAS on top of the default Quotas
+func Init() error {
+	raw := os.Getenv("AI_QUOTAS")
+	if raw == "" {
+		return nil
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return fmt.Errorf("AI_QUOTAS: %q is not key=value", pair)
+		}
+		if _, known := Quotas[key]; !known {
+			return fmt.Errorf("AI_QUOTAS: unknown quota %q", key)
+		}
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit < 0 {
+			return fmt.Errorf("AI_QUOTAS: %s must be a non-negative number", key)
+		}
+		Quotas[key] = limit
+	}
+	return nil
+}
+
+// Period is the accounting month t falls in
+func Period(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// PeriodEnd is when period's quotas reset
+func PeriodEnd(period string) (time.Time, error) {
+	start, err := time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return start.AddDate(0, 1, 0), nil
+}
+
+// Recorder books AI usage against one user
+type Recorder struct {
+	UserID string
+}
+
+func ForUser(userID string) Recorder {
+	return Recorder{UserID: userID}
+}
+
+func (r Recorder) RecordUsage(ctx context.Context, u ai.Usage) {
+	// The call already happened and cost money, so record it even if the client hung up
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now()
+
+	collection := database.GetCollection(Collection)
+	filter := bson.M{"user_id": r.UserID, "period": Period(now), "operation": u.Operation}
+	update := bson.M{
+		"$inc": bson.M{
+			"calls":         1,
+			"prompt_tokens": u.PromptTokens,
+			"output_tokens": u.OutputTokens,
+			"total_tokens":  u.TotalTokens,
+			"credits":       u.Credits,
+		},
+		"$set": bson.M{"updated_at": now},
+	}
+	_, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent call created this month's counter first; add to it
+		_, err = collection.UpdateOne(ctx, filter, update)
+	}
+	if err != nil {
+		log.Printf("Could not record %s usage for %s: %v", u.Operation, r.UserID, err)
+	}
+}
+
+// Counters returns the user's usage for period, one counter per operation used
+func Counters(ctx context.Context, userID, period string) ([]models.UsageCounter, error) {
+	cursor, err := database.GetCollection(Collection).Find(ctx, bson.M{"user_id": userID, "period": period})
+	if err != nil {
+		return nil, err
+	}
+	counters := []models.UsageCounter{}
+	if err := cursor.All(ctx, &counters); err != nil {
+		return nil, err
+	}
+	return counters, nil
+}
+
+// Used totals counters per operation and for the shared token and credit
+// quotas, keyed like Quotas
+func Used(counters []models.UsageCounter) map[string]int64 {
+	used := map[string]int64{}
+	for _, counter := range counters {
+		used[counter.Operation] += counter.Calls
+		used[QuotaTokens] += counter.TotalTokens
+		used[QuotaCredits] += counter.Credits
+	}
+	return used
+}
+
+// QuotaError reports the quota that ran out
+type QuotaError struct {
+	Quota    string
+	Limit    int64
+	Used     int64
+	ResetsAt time.Time
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("monthly %s quota of %d reached", e.Quota, e.Limit)
+}
+
+// Check returns a *QuotaError if the user has used up the monthly quota of any
+// of operations, or the shared token/credit quota those operations draw on
+func Check(ctx context.Context, userID string, operations ...string) error {
+	period := Period(time.Now())
+	counters, err := Counters(ctx, userID, period)
+	if err != nil {
+		return err
+	}
+	resetsAt, _ := PeriodEnd(period)
+
+	used := Used(counters)
+
+	keys := append([]string{}, operations...)
+	for _, op := range operations {
+		if op == ai.OpBackgroundRemoval {
+			keys = append(keys, QuotaCredits)
+		} else {
+			keys = append(keys, QuotaTokens)
+		}
+	}
+
+	for _, key := range keys {
+		if limit := Quotas[key]; limit > 0 && used[key] >= limit {
+			return &QuotaError{Quota: key, Limit: limit, Used: used[key], ResetsAt: resetsAt}
+		}
+	}
+	return nil
+}
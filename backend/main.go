@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"time"
 
 	_ "github.com/exply/armoire/docs"
+	"github.com/exply/armoire/internal/auth"
 	"github.com/exply/armoire/internal/database"
+	"github.com/exply/armoire/internal/handlers"
+	"github.com/exply/armoire/internal/mailer"
+	"github.com/exply/armoire/internal/oidc"
+	"github.com/exply/armoire/internal/ratelimit"
 	"github.com/exply/armoire/internal/router"
+	"github.com/exply/armoire/internal/taxonomy"
+	"github.com/exply/armoire/internal/usage"
 	"github.com/joho/godotenv"
 )
 
@@ -29,9 +38,46 @@ func main() {
 		log.Println("No .env file found")
 	}
 
+	// Refuse to start without a signing key rather than accept tokens signed with ""
+	if err := auth.Init(); err != nil {
+		log.Fatal("Could not configure JWT keys: ", err)
+	}
+
+	if err := mailer.Init(); err != nil {
+		log.Fatal("Could not configure mailer: ", err)
+	}
+
+	// In-memory by default; RATE_LIMIT_STORE=redis shares limits across instances
+	if err := ratelimit.Init(); err != nil {
+		log.Fatal("Could not configure rate limiting: ", err)
+	}
+
+	if err := usage.Init(); err != nil {
+		log.Fatal("Could not configure AI quotas: ", err)
+	}
+
+	// Social login is optional; providers come from a JSON file
+	if providersFile := os.Getenv("OIDC_PROVIDERS_FILE"); providersFile != "" {
+		if err := oidc.LoadProviders(providersFile); err != nil {
+			log.Fatal("Could not load OIDC providers: ", err)
+		}
+	}
+
+	// Swap in a custom taxonomy if one is configured, otherwise the embedded default is used
+	if taxonomyFile := os.Getenv("TAXONOMY_FILE"); taxonomyFile != "" {
+		t, err := taxonomy.LoadFile(taxonomyFile)
+		if err != nil {
+			log.Fatal("Could not load taxonomy: ", err)
+		}
+		taxonomy.Use(t)
+	}
+
 	mongoURI := os.Getenv("MONGO_URI")
 	database.InitDB(mongoURI)
 
+	// Approved loans mark items lent when they start and available when they end
+	go handlers.RunLoanSweeper(context.Background(), time.Minute)
+
 	router := router.SetupRouter()
 	router.Run() // listens on 0.0.0.0:8080 by default
 }
@@ -1,5 +1,54 @@
 import wretch from "wretch";
+import { clearTokens, storeTokens, type TokenPair } from "../../utils/authUtils";
+
 export const API_BASE_URL =
   import.meta.env.VITE_API_BASE_URL || "http://localhost:8080";
 
-export const api = wretch(API_BASE_URL);
\ No newline at end of file
+export const api = wretch(API_BASE_URL);
+
+let refreshing: Promise<string> | null = null;
+
+/**
+ * trade the stored refresh token for a new token pair. Refresh tokens only
+ * work once, so requests that fail together share a single refresh.
+ */
+export function refreshAccessToken() {
+  if (!refreshing) {
+    const refreshToken = localStorage.getItem("refreshToken");
+    const request = refreshToken
+      ? api.url("/auth/refresh").post({ refreshToken }).json<TokenPair>()
+      : Promise.reject(new Error("No refresh token"));
+    refreshing = request
+      .then((tokens) => {
+        storeTokens(tokens);
+        return tokens.token;
+      })
+      .finally(() => {
+        refreshing = null;
+      });
+  }
+  return refreshing;
+}
+
+/**
+ * api for endpoints that need the access token. Access tokens are
+ * short-lived: on a 401 the token is refreshed and the request replayed once.
+ * If the session can't be refreshed the user is logged out.
+ */
+export const authApi = api.catcher(401, async (error, request) => {
+  let token: string;
+  try {
+    token = await refreshAccessToken();
+  } catch {
+    clearTokens();
+    window.location.reload();
+    throw error;
+  }
+  return request
+    .auth(`Bearer ${token}`)
+    .fetch()
+    .unauthorized((err) => {
+      throw err;
+    })
+    .json();
+});
@@ -1,6 +1,6 @@
 import { useMutation, useQuery } from "@tanstack/react-query";
 import type { ClothingItem } from "../../models/models";
-import { api } from "./api";
+import { api, authApi } from "./api";
 
 export type SearchClothingParams = {
   query: string;
@@ -13,7 +13,7 @@ export const useSearchClothing = () => {
   return useMutation({
     mutationKey: ["clothing", "search"],
     mutationFn: (searchParams: SearchClothingParams) =>
-      api
+      authApi
         .auth(`Bearer ${localStorage.getItem("token") || ""}`)
         .url("/clothing/search")
         .post(searchParams)
@@ -31,7 +31,7 @@ export const useGetClothingStats = () => {
   return useQuery({
     queryKey: ["clothing", "stats"],
     queryFn: () =>
-      api
+      authApi
         .auth(`Bearer ${localStorage.getItem("token") || ""}`)
         .get("/clothing/stats")
         .json<ClothingStats>(),
@@ -42,7 +42,7 @@ export const useUploadClothingItem = () => {
   return useMutation({
     mutationKey: ["clothing", "upload"],
     mutationFn: (formData: FormData) =>
-      api
+      authApi
         .auth(`Bearer ${localStorage.getItem("token") || ""}`)
         .url("/clothing/upload")
         .post(formData)
@@ -54,7 +54,7 @@ export const useGetClothingItem = (clothingId: string) => {
   return useQuery({
     queryKey: ["clothing", clothingId],
     queryFn: () =>
-      api
+      authApi
         .auth(`Bearer ${localStorage.getItem("token") || ""}`)
         .get(`/clothing/${clothingId}`)
         .json<ClothingItem>(),
@@ -83,7 +83,7 @@ export const useUpdateClothingItem = () => {
   return useMutation({
     mutationKey: ["clothing", "update"],
     mutationFn: ({ id, ...updateData }: UpdateClothingItemParams) =>
-      api
+      authApi
         .auth(`Bearer ${localStorage.getItem("token") || ""}`)
         .url(`/clothing/${id}`)
         .patch(updateData)
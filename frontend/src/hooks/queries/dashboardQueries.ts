@@ -1,11 +1,11 @@
 import { useQuery } from "@tanstack/react-query";
-import { api } from "./api";
+import { authApi } from "./api";
 
 export const useGetDashboardStylistMessage = () => {
   return useQuery({
     queryKey: ["dashboard", "stylist"],
     queryFn: () =>
-      api
+      authApi
         .auth(`Bearer ${localStorage.getItem("token") || ""}`)
         .get("/dashboard/stylist")
         .json<DashboardStylistResponse>(),
@@ -1,5 +1,6 @@
 import { useMutation, useQuery } from "@tanstack/react-query";
-import { api } from "./api";
+import { api, authApi } from "./api";
+import { storeTokens } from "../../utils/authUtils";
 import { toast } from "react-toastify";
 import type { UserModel } from "../../models/models";
 
@@ -10,7 +11,9 @@ export type LoginRequest = {
 
 export type LoginResponse = {
   token: string;
-  userId: string;
+  refreshToken: string;
+  expiresIn: number;
+  user: UserModel;
 };
 
 export const useLogin = () => {
@@ -19,7 +22,7 @@ export const useLogin = () => {
     mutationFn: (req: LoginRequest) =>
       api.url("/auth/login").post(req).json<LoginResponse>(),
     onSuccess: (data) => {
-      localStorage.setItem("token", data.token);
+      storeTokens(data);
       window.location.replace("/");
     },
     onError: (error: any) => {
@@ -44,6 +47,8 @@ export type RegisterRequest = {
 
 export type RegisterResponse = {
   token: string;
+  refreshToken: string;
+  expiresIn: number;
   user: UserModel;
 };
 
@@ -53,7 +58,7 @@ export const useRegister = () => {
     mutationFn: (req: RegisterRequest) =>
       api.url("/auth/register").post(req).json<RegisterResponse>(),
     onSuccess: (data) => {
-      localStorage.setItem("token", data.token);
+      storeTokens(data);
       window.location.replace("/");
     },
     onError: (error) => {
@@ -79,7 +84,7 @@ export const useGetUserInfo = () => {
       if (!token) {
         throw new Error("No authentication token found");
       }
-      return api
+      return authApi
         .auth(`Bearer ${token}`)
         .url("/user/userinfo")
         .get()
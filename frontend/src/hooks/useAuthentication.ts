@@ -1,18 +1,14 @@
 import { useEffect, useState } from "react";
 import useUserStore from "../stores/userStore";
 import { useGetUserInfo } from "./queries/userQueries";
-import { isTokenExpired } from "../utils/authUtils";
+import { checkIsLoggedIn, clearTokens } from "../utils/authUtils";
 
 export const useAuthentication = () => {
-  const [isLoggedIn, setIsLoggedIn] = useState(() => {
-    return !isTokenExpired(localStorage.getItem("token"));
-  });
+  const [isLoggedIn, setIsLoggedIn] = useState(checkIsLoggedIn);
 
   useEffect(() => {
     const handleStorageChange = () => {
-      const token = localStorage.getItem("token");
-      const loggedIn = token !== null && !isTokenExpired(token);
-      setIsLoggedIn(loggedIn);
+      setIsLoggedIn(checkIsLoggedIn());
     };
 
     window.addEventListener("storage", handleStorageChange);
@@ -28,14 +24,14 @@ export const useAuthentication = () => {
     } else {
       localStorage.removeItem("token");
     }
-    setIsLoggedIn(isTokenExpired(token));
+    setIsLoggedIn(checkIsLoggedIn());
   };
 
   return { isLoggedIn, setToken };
 };
 
 export function logout(reload: boolean = true) {
-  localStorage.removeItem("token");
+  clearTokens();
   if (reload) {
     window.location.reload();
   }
@@ -1,13 +1,37 @@
 
 /**
  * use to check if user is logged in
- * source of truth is localStorage token
+ * source of truth is localStorage token; an expired access token still
+ * counts while there is a refresh token to renew it with
  */
 export const checkIsLoggedIn = () => {
   const token = localStorage.getItem("token");
-  return token !== null && !isTokenExpired(token);
+  return (
+    localStorage.getItem("refreshToken") !== null ||
+    (token !== null && !isTokenExpired(token))
+  );
 };
 
+export type TokenPair = {
+  token: string;
+  refreshToken?: string;
+};
+
+/**
+ * store the tokens returned by login, register and refresh
+ */
+export function storeTokens(tokens: TokenPair) {
+  localStorage.setItem("token", tokens.token);
+  if (tokens.refreshToken) {
+    localStorage.setItem("refreshToken", tokens.refreshToken);
+  }
+}
+
+export function clearTokens() {
+  localStorage.removeItem("token");
+  localStorage.removeItem("refreshToken");
+}
+
 
 export function isTokenExpired(token: string | null) {
   const decoded = parseJwt(token);